@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldAppender appends the fields describing a message to fields and returns
+// the extended slice, the same way append does.  Unlike a FieldOpt, which
+// produces exactly one field, a FieldAppender can add any number of fields,
+// including none, without allocating its own slice.  Use Appender to
+// configure one in an Observer.
+type FieldAppender interface {
+	AppendFields(msg wrp.Message, fields []zap.Field) []zap.Field
+}
+
+// FieldAppenderFunc is a function that implements FieldAppender.
+type FieldAppenderFunc func(msg wrp.Message, fields []zap.Field) []zap.Field
+
+// AppendFields calls the function.
+func (f FieldAppenderFunc) AppendFields(msg wrp.Message, fields []zap.Field) []zap.Field {
+	return f(msg, fields)
+}
+
+// fieldMarker evaluates the FieldOpts created by Appender, given the
+// Observer's fieldEnv.
+type fieldMarker interface {
+	appendFields(env fieldEnv, msg wrp.Message, fields []zap.Field) []zap.Field
+}
+
+// appended is the fieldMarker of Appender.
+type appended struct {
+	a FieldAppender
+}
+
+func (m *appended) appendFields(_ fieldEnv, msg wrp.Message, fields []zap.Field) []zap.Field {
+	return m.a.AppendFields(msg, fields)
+}
+
+// markedFields is the field a FieldOpt created by Appender produces when it
+// is called directly, which logs the fields of its marker inline.
+type markedFields struct {
+	marker fieldMarker
+	msg    wrp.Message
+}
+
+func (f markedFields) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, field := range f.marker.appendFields(fieldEnv{}, f.msg, nil) {
+		field.AddTo(enc)
+	}
+	return nil
+}
+
+// marked returns a FieldOpt that an Observer evaluates with the marker instead
+// of calling it, so it appends its fields without allocating.
+func marked(name, key string, marker fieldMarker) FieldOpt {
+	s := &optState{marker: marker}
+	return newOpt(s, optInfo{name: name, key: key, marker: marker}, func(msg wrp.Message) zap.Field {
+		return zap.Inline(markedFields{marker: s.marker, msg: msg})
+	})
+}
+
+// markedCode is the code of the FieldOpts created by marked.
+var markedCode = reflect.ValueOf(marked("", "", nil)).Pointer()
+
+// markerOf returns the marker of a FieldOpt created by marked, or nil.
+func markerOf(opt FieldOpt) fieldMarker {
+	if reflect.ValueOf(opt).Pointer() != markedCode {
+		return nil
+	}
+	if info, ok := lookupOpt(opt); ok {
+		return info.marker
+	}
+	return nil
+}
+
+// Appender adapts a FieldAppender to a FieldOpt, so that it can be used
+// anywhere a FieldOpt can.  The Observer appends the FieldAppender's fields
+// in place of the FieldOpt's, without calling the FieldOpt.  Calling the
+// returned FieldOpt directly produces an inline field holding the
+// FieldAppender's fields, as AppendFields appends them.
+func Appender(a FieldAppender) FieldOpt {
+	return marked("", "", &appended{a: a})
+}
+
+// fieldEnv is what the FieldOpts created by envAppender use of the Observer
+// evaluating them.  The zero fieldEnv is used outside of an Observer.
+type fieldEnv struct {
+	// clock is the Observer's Clock, nil for the system clock.
+	clock Clock
+
+	// normalizeMetadata is the Observer's NormalizeMetadataKeys.
+	normalizeMetadata bool
+}
+
+// fieldEnv returns the fieldEnv of the Observer's FieldOpts.
+func (ob Observer) fieldEnv() fieldEnv {
+	return fieldEnv{
+		clock:             ob.Clock,
+		normalizeMetadata: ob.NormalizeMetadataKeys,
+	}
+}
+
+// envAppended is the fieldMarker of envAppender.
+type envAppended struct {
+	fn func(env fieldEnv, msg wrp.Message, fields []zap.Field) []zap.Field
+}
+
+func (m *envAppended) appendFields(env fieldEnv, msg wrp.Message, fields []zap.Field) []zap.Field {
+	return m.fn(env, msg, fields)
+}
+
+// envAppender is Appender for the built-in FieldOpts that depend on the
+// Observer evaluating them, like its Clock and whether it normalizes the
+// metadata keys.  fn appends the fields given the Observer's fieldEnv, which
+// is the zero fieldEnv when the FieldOpt is called directly.
+func envAppender(fn func(env fieldEnv, msg wrp.Message, fields []zap.Field) []zap.Field) FieldOpt {
+	return marked("", "", &envAppended{fn: fn})
+}
+
+// AppendFields appends the FieldOpt's field, or the fields of the
+// FieldAppender it adapts, making every FieldOpt a FieldAppender.  Since no
+// logger is involved, fields from FieldOptAtLevel are not included, and the
+// FieldOpts that use the Observer's configuration, like LogMessageAge, use
+// the defaults.
+func (opt FieldOpt) AppendFields(msg wrp.Message, fields []zap.Field) []zap.Field {
+	return appendField(nil, fieldEnv{}, fields, opt, &msg)
+}
+
+// appendField evaluates the FieldOpt and appends its fields.  The FieldOpts
+// created by Appender are evaluated with their marker, given env, rather than
+// called, and the markers carried by skipped fields are interpreted.  Leveled
+// fields are included when the level is enabled, which is never when enabled
+// is nil.
+//
+// Markers are carried in skipped fields, so a FieldOpt producing one is
+// harmless when it is used without an Observer.  Every other skipped field,
+// including a plain zap.Skip(), is left out.  The LogObserveCost placeholder
+// and FieldError fields are kept for the Observer to handle.
+func appendField(enabled zapcore.LevelEnabler, env fieldEnv, fields []zap.Field, opt FieldOpt, msg *wrp.Message) []zap.Field {
+	for {
+		if marker := markerOf(opt); marker != nil {
+			return marker.appendFields(env, *msg, fields)
+		}
+
+		field := opt(*msg)
+		switch field.Type {
+		case zapcore.SkipType:
+		case zapcore.InlineMarshalerType:
+			// A FieldOpt wrapping one created by Appender, like LogIf's,
+			// called it.
+			if f, ok := field.Interface.(markedFields); ok {
+				return f.marker.appendFields(env, *msg, fields)
+			}
+			return append(fields, field)
+		default:
+			return append(fields, field)
+		}
+
+		switch marker := field.Interface.(type) {
+		case *leveled:
+			if enabled == nil || !enabled.Enabled(marker.min) {
+				return fields
+			}
+			opt = marker.opt
+		case observeCost, *fieldError:
+			return append(fields, field)
+		default:
+			return fields
+		}
+	}
+}
+
+// appenderName names a FieldAppender adapted by Appender.
+func appenderName(a FieldAppender) string {
+	if fn, isFunc := a.(FieldAppenderFunc); isFunc {
+		return funcName(fn)
+	}
+	return fmt.Sprintf("%T", a)
+}
@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// routeAppender is a FieldAppender that is not a function.
+type routeAppender struct{}
+
+func (routeAppender) AppendFields(msg wrp.Message, fields []zap.Field) []zap.Field {
+	return append(fields,
+		zap.String("from", msg.Source),
+		zap.String("to", msg.Destination),
+	)
+}
+
+func TestAppender(t *testing.T) {
+	msg := wrp.Message{
+		Source:      "mac:112233445566",
+		Destination: "dns:talaria",
+	}
+
+	none := Appender(FieldAppenderFunc(func(_ wrp.Message, fields []zap.Field) []zap.Field {
+		return fields
+	}))
+
+	tests := []struct {
+		name      string
+		coreLevel zapcore.Level
+		fields    []FieldOpt
+		expected  []zap.Field
+	}{
+		{
+			name:      "appends every field in order",
+			coreLevel: zap.InfoLevel,
+			fields:    []FieldOpt{LogTransactionUUID(), Appender(routeAppender{}), LogSessionID()},
+			expected: []zap.Field{
+				zap.String(fTransactionUUID, ""),
+				zap.String("from", "mac:112233445566"),
+				zap.String("to", "dns:talaria"),
+				zap.String(fSessionID, ""),
+			},
+		}, {
+			name:      "appends nothing",
+			coreLevel: zap.InfoLevel,
+			fields:    []FieldOpt{none, LogSource()},
+			expected:  []zap.Field{zap.String(fSource, "mac:112233445566")},
+		}, {
+			name:      "leveled appender disabled",
+			coreLevel: zap.InfoLevel,
+			fields:    []FieldOpt{FieldOptAtLevel(zap.DebugLevel, Appender(routeAppender{}))},
+			expected:  []zap.Field{},
+		}, {
+			name:      "leveled appender enabled",
+			coreLevel: zap.DebugLevel,
+			fields:    []FieldOpt{FieldOptAtLevel(zap.DebugLevel, Appender(routeAppender{}))},
+			expected: []zap.Field{
+				zap.String("from", "mac:112233445566"),
+				zap.String("to", "dns:talaria"),
+			},
+		}, {
+			name:      "omitted fields",
+			coreLevel: zap.InfoLevel,
+			fields:    []FieldOpt{NoFields()[0], Appender(routeAppender{})},
+			expected: []zap.Field{
+				zap.String("from", "mac:112233445566"),
+				zap.String("to", "dns:talaria"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(tt.coreLevel)
+
+			ob := Observer{
+				Logger:  zap.New(core),
+				Level:   AtLevel(zap.InfoLevel),
+				Message: "test message",
+				Fields:  tt.fields,
+			}
+
+			ob.ObserveWRP(context.Background(), msg)
+			entries := recorded.AllUntimed()
+			if assert.Len(t, entries, 1) {
+				assert.Equal(t, tt.expected, entries[0].Context)
+			}
+		})
+	}
+}
+
+func TestFieldOpt_AppendFields(t *testing.T) {
+	msg := wrp.Message{
+		Source:      "mac:112233445566",
+		Destination: "dns:talaria",
+		Payload:     []byte("test payload"),
+	}
+	prefix := []zap.Field{zap.String("prefix", "kept")}
+
+	tests := []struct {
+		name     string
+		opt      FieldOpt
+		expected []zap.Field
+	}{
+		{
+			name:     "single field",
+			opt:      LogSource(),
+			expected: []zap.Field{zap.String(fSource, "mac:112233445566")},
+		}, {
+			name: "appender",
+			opt:  Appender(routeAppender{}),
+			expected: []zap.Field{
+				zap.String("from", "mac:112233445566"),
+				zap.String("to", "dns:talaria"),
+			},
+		}, {
+			name:     "omitted",
+			opt:      NoFields()[0],
+			expected: []zap.Field{},
+		}, {
+			name:     "leveled fields are left out",
+			opt:      FieldOptAtLevel(zap.DebugLevel, LogPayload()),
+			expected: []zap.Field{},
+		}, {
+			name:     "skipped",
+			opt:      func(wrp.Message) zap.Field { return zap.Skip() },
+			expected: []zap.Field{},
+		}, {
+			name: "skipped with an unknown value",
+			opt: func(wrp.Message) zap.Field {
+				return zap.Field{Type: zapcore.SkipType, Interface: "unknown"}
+			},
+			expected: []zap.Field{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := tt.opt.AppendFields(msg, nil)
+			assert.Equal(t, tt.expected, append([]zap.Field{}, fields...))
+
+			fields = tt.opt.AppendFields(msg, prefix)
+			assert.Equal(t, append(prefix[:1:1], tt.expected...), fields)
+		})
+	}
+}
+
+func TestAppender_CalledDirectly(t *testing.T) {
+	msg := wrp.Message{
+		Type:        wrp.SimpleRequestResponseMessageType,
+		Source:      "mac:112233445566",
+		Destination: "dns:talaria/config",
+		Headers:     []string{"X-Xmidt-Retry-Count: 2", "x-xmidt-retry-count: 3"},
+		Metadata: map[string]string{
+			"/boot-time":         "1000",
+			"/parent-trans-uuid": "parent",
+		},
+		PartnerIDs: []string{" Comcast", "comcast"},
+		Payload:    []byte("payload"),
+	}
+
+	encode := func(fields ...zap.Field) map[string]any {
+		enc := zapcore.NewMapObjectEncoder()
+		for _, field := range fields {
+			field.AddTo(enc)
+		}
+		return enc.Fields
+	}
+
+	tests := []struct {
+		name string
+		opt  FieldOpt
+	}{
+		{name: "appender", opt: Appender(routeAppender{})},
+		{name: "LogMessageAge", opt: LogMessageAgeWithClock("/boot-time", ClockFunc(func() time.Time { return time.UnixMilli(3000) }))},
+		{name: "LogUnknownMetadataKeysMax", opt: LogUnknownMetadataKeysMax(1, "/boot-time")},
+		{name: "LogMissingMetadataKeys", opt: LogMissingMetadataKeys("/boot-time", "/hw-model")},
+		{name: "LogParentTransactionUUID", opt: LogParentTransactionUUID("/parent-trans-uuid")},
+		{name: "LogRetryCountFrom", opt: LogRetryCountFrom("X-Xmidt-Retry-Count")},
+		{name: "LogSourceIsCloud", opt: LogSourceIsCloud()},
+		{name: "LogLocatorSchemes", opt: LogLocatorSchemes()},
+		{name: "LogDestinationNormalized", opt: LogDestinationNormalized()},
+		{name: "LogSourceNormalized", opt: LogSourceNormalized()},
+		{name: "LogHeaderDuplicates", opt: LogHeaderDuplicates()},
+		{name: "LogPartnerIDsNormalized", opt: LogPartnerIDsNormalized()},
+		{name: "LogPayloadSampled", opt: LogPayloadSampled(1, LogPayloadSize())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Called directly the FieldOpt logs the fields it appends,
+			// inline.
+			field := tt.opt(msg)
+			assert.Equal(t, zapcore.InlineMarshalerType, field.Type)
+
+			expected := encode(tt.opt.AppendFields(msg, nil)...)
+			require.NotEmpty(t, expected)
+			assert.Equal(t, expected, encode(field))
+		})
+	}
+}
+
+func TestAppender_Wrapped(t *testing.T) {
+	// A wrapper calling the FieldOpt, like LogIf, still gets the Observer's
+	// configuration.
+	clock := ClockFunc(func() time.Time { return time.UnixMilli(3000) })
+	core, recorded := observer.New(zap.InfoLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithClock(clock),
+		WithFields(LogIf(func(wrp.Message) bool { return true }, LogMessageAge("/boot-time"))),
+	)
+	require.NoError(t, err)
+
+	ob.ObserveWRP(context.Background(), wrp.Message{Metadata: map[string]string{"/boot-time": "1000"}})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(2000), entries[0].ContextMap()[KeyMessageAge])
+}
+
+func TestAppender_Describe(t *testing.T) {
+	custom := Appender(FieldAppenderFunc(func(_ wrp.Message, fields []zap.Field) []zap.Field {
+		return fields
+	}))
+
+	tests := []struct {
+		name     string
+		opt      FieldOpt
+		expected string
+	}{
+		{name: "builtin", opt: LogLocatorSchemes(), expected: "locator_schemes"},
+		{name: "builtin with parameters", opt: LogRetryCountFrom("X-Retry"), expected: "retry_count"},
+		{name: "function", opt: custom, expected: "wrpzap.TestAppender_Describe"},
+		{name: "type", opt: Appender(routeAppender{}), expected: "wrpzap.routeAppender"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, fieldOptName(tt.opt))
+		})
+	}
+}
+
+func BenchmarkObserver_Appender(b *testing.B) {
+	msg := wrp.Message{
+		Type:            wrp.SimpleEventMessageType,
+		Source:          "mac:112233445566",
+		Destination:     "event:device-status/mac:112233445566/online",
+		TransactionUUID: "c07ee5e1-70be-444c-a156-097c767ad8aa",
+		SessionID:       "session",
+		PartnerIDs:      []string{"comcast"},
+		Metadata:        map[string]string{"/boot-time": "1700000000"},
+	}
+
+	schemes := func(msg wrp.Message) zap.Field {
+		return zap.String(fSourceScheme, LocatorScheme(msg.Source))
+	}
+	dest := func(msg wrp.Message) zap.Field {
+		return zap.String(fDestinationScheme, LocatorScheme(msg.Destination))
+	}
+
+	benchmarks := []struct {
+		name   string
+		fields []FieldOpt
+	}{
+		{name: "field opts", fields: append(DefaultFields(), schemes, dest)},
+		{name: "appender", fields: append(DefaultFields(), LogLocatorSchemes())},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			core := zapcore.NewCore(
+				zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+				zapcore.AddSync(io.Discard),
+				zap.InfoLevel,
+			)
+			ob := Observer{
+				Logger:  zap.New(core),
+				Level:   AtLevel(zap.InfoLevel),
+				Message: "wrp",
+				Fields:  bm.fields,
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ob.ObserveWRP(context.Background(), msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// BackpressurePolicy chooses what an AsyncObserver does with a message when
+// its buffer is full.
+type BackpressurePolicy int
+
+const (
+	// DropNewest drops the message being observed, so the caller is never
+	// delayed.  This is the default.
+	DropNewest BackpressurePolicy = iota
+
+	// DropOldest drops the oldest message in the buffer to make room for the
+	// message being observed, so the entries logged are the most recent.
+	DropOldest
+
+	// Block waits for room in the buffer, dropping the message when there
+	// is no room by the block timeout or the context is done.
+	Block
+)
+
+var backpressureNames = [...]string{
+	DropNewest: "drop_newest",
+	DropOldest: "drop_oldest",
+	Block:      "block",
+}
+
+func (p BackpressurePolicy) String() string {
+	if p >= 0 && int(p) < len(backpressureNames) {
+		return backpressureNames[p]
+	}
+	return "BackpressurePolicy(" + strconv.Itoa(int(p)) + ")"
+}
+
+// DefaultBlockTimeout is how long an AsyncObserver with the Block policy
+// waits for room in its buffer when no timeout is set with WithBlockTimeout.
+const DefaultBlockTimeout = 100 * time.Millisecond
+
+// DropWarningMessage is the message text of the entries WithDropWarnings
+// logs.
+const DropWarningMessage = "wrp messages dropped"
+
+// AsyncOption is a configuration option for NewAsyncObserver.
+type AsyncOption interface {
+	applyAsync(*AsyncObserver) error
+}
+
+type asyncOptionFunc func(*AsyncObserver) error
+
+func (f asyncOptionFunc) applyAsync(a *AsyncObserver) error {
+	return f(a)
+}
+
+// WithBackpressure sets what the AsyncObserver does with a message when its
+// buffer is full.
+func WithBackpressure(policy BackpressurePolicy) AsyncOption {
+	return asyncOptionFunc(func(a *AsyncObserver) error {
+		if policy < 0 || int(policy) >= len(backpressureNames) {
+			return fmt.Errorf("%w: unknown backpressure policy %v", ErrInvalidInput, policy)
+		}
+		a.policy = policy
+		return nil
+	})
+}
+
+// WithBlockTimeout sets how long the Block policy waits for room in the
+// buffer.
+func WithBlockTimeout(timeout time.Duration) AsyncOption {
+	return asyncOptionFunc(func(a *AsyncObserver) error {
+		if timeout <= 0 {
+			return fmt.Errorf("%w: block timeout must be positive", ErrInvalidInput)
+		}
+		a.timeout = timeout
+		return nil
+	})
+}
+
+// WithDropWarnings logs a Warn entry every interval in which messages were
+// dropped, with the number dropped as dropped and the policy as
+// backpressure.
+func WithDropWarnings(interval time.Duration) AsyncOption {
+	return asyncOptionFunc(func(a *AsyncObserver) error {
+		if interval <= 0 {
+			return fmt.Errorf("%w: drop warning interval must be positive", ErrInvalidInput)
+		}
+		a.interval = interval
+		return nil
+	})
+}
+
+// AsyncObserver logs messages with an Observer on a separate goroutine, so
+// that observing a message only costs the caller adding it to a buffer.
+// When the buffer is full the BackpressurePolicy decides whether the message
+// is dropped, the oldest message is dropped or the caller waits.  The
+// messages dropped are counted in Stats, reported to the Observer's OnError
+// as ErrDropped and, with WithDropWarnings, summarized in periodic Warn
+// entries.
+//
+// The message is logged after ObserveWRP returns, so its slices and maps
+// must not be changed once it is observed.
+type AsyncObserver struct {
+	ob       Observer
+	policy   BackpressurePolicy
+	timeout  time.Duration
+	interval time.Duration
+
+	lock   sync.RWMutex
+	queue  chan wrp.Message
+	closed bool
+
+	dropped atomic.Uint64
+	warned  uint64
+
+	ticker Timer
+	stop   chan struct{}
+	done   chan struct{}
+	warner chan struct{}
+}
+
+// NewAsyncObserver creates an AsyncObserver that buffers up to size messages
+// for the Observer.  size must be positive.  Close must be called to stop the
+// AsyncObserver and log the messages still buffered.
+func NewAsyncObserver(ob Observer, size int, opts ...AsyncOption) (*AsyncObserver, error) {
+	if ob.Logger == nil {
+		return nil, fmt.Errorf("%w: logger is nil", ErrInvalidInput)
+	}
+	if size < 1 {
+		return nil, fmt.Errorf("%w: size must be positive", ErrInvalidInput)
+	}
+
+	a := &AsyncObserver{
+		ob:      ob,
+		timeout: DefaultBlockTimeout,
+		queue:   make(chan wrp.Message, size),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		warner:  make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt.applyAsync(a); err != nil {
+			return nil, fmt.Errorf("%s: %w", funcName(opt), err)
+		}
+	}
+
+	if a.interval > 0 {
+		a.ticker = a.ob.timers().NewTicker(a.interval)
+	}
+
+	go a.run()
+	go a.warn()
+
+	return a, nil
+}
+
+// ObserveWRP adds the message to the buffer, applying the BackpressurePolicy
+// when it is full.  With the Block policy the wait ends early, dropping the
+// message, when the context is done.  Dropped messages are reported to the
+// Observer's OnError as ErrDropped.  Messages observed after Close are
+// dropped and reported to the Observer's OnError as ErrClosed.
+func (a *AsyncObserver) ObserveWRP(ctx context.Context, msg wrp.Message) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	if a.closed {
+		a.ob.reportError(fmt.Errorf("%w: async message dropped", ErrClosed))
+		return
+	}
+
+	switch a.policy {
+	case DropOldest:
+		for {
+			select {
+			case a.queue <- msg:
+				return
+			default:
+			}
+
+			// The worker may empty a slot first, in which case nothing is
+			// dropped and the send is retried.
+			select {
+			case <-a.queue:
+				a.drop("dropped the oldest message")
+			default:
+			}
+		}
+
+	case Block:
+		select {
+		case a.queue <- msg:
+			return
+		default:
+		}
+
+		timer := a.ob.timers().NewTimer(a.timeout)
+		defer timer.Stop()
+
+		select {
+		case a.queue <- msg:
+		case <-timer.C():
+			a.drop("timed out after " + a.timeout.String())
+		case <-ctx.Done():
+			a.drop(ctx.Err().Error())
+		}
+
+	default:
+		select {
+		case a.queue <- msg:
+		default:
+			a.drop("dropped the newest message")
+		}
+	}
+}
+
+// drop counts a message dropped because the buffer was full and reports it
+// to the Observer's OnError.
+func (a *AsyncObserver) drop(reason string) {
+	a.dropped.Add(1)
+	a.ob.reportError(fmt.Errorf("%w: async buffer full, %s", ErrDropped, reason))
+}
+
+// Stats returns the Observer's Stats along with the number of messages the
+// AsyncObserver dropped because its buffer was full.
+func (a *AsyncObserver) Stats() Stats {
+	stats := a.ob.Stats()
+	stats.Dropped = a.dropped.Load()
+	return stats
+}
+
+// Close stops accepting messages and waits for the messages still buffered
+// to be logged, then logs the last drop warning.  When the context is done
+// first its error is returned and the buffered messages are logged in the
+// background.  Callers of ObserveWRP waiting with the Block policy finish
+// waiting before Close stops accepting messages.
+func (a *AsyncObserver) Close(ctx context.Context) error {
+	a.lock.Lock()
+	if a.closed {
+		a.lock.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.queue)
+	a.lock.Unlock()
+
+	close(a.stop)
+	<-a.warner
+
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	a.warnDropped()
+	return nil
+}
+
+// run logs the buffered messages until the buffer is closed and empty.
+func (a *AsyncObserver) run() {
+	defer close(a.done)
+
+	for msg := range a.queue {
+		a.ob.observe(&msg, nil)
+	}
+}
+
+// warn logs the drop warnings every interval until the AsyncObserver is
+// closed.
+func (a *AsyncObserver) warn() {
+	defer close(a.warner)
+
+	if a.ticker == nil {
+		return
+	}
+	defer a.ticker.Stop()
+
+	for {
+		select {
+		case <-a.ticker.C():
+			a.warnDropped()
+		case <-a.stop:
+			return
+		}
+	}
+}
+
+// warnDropped logs the number of messages dropped since the last warning,
+// when WithDropWarnings is set and there were any.  It is only called by
+// warn, and by Close once warn has returned.
+func (a *AsyncObserver) warnDropped() {
+	if a.interval <= 0 {
+		return
+	}
+
+	total := a.dropped.Load()
+	dropped := total - a.warned
+	a.warned = total
+	if dropped == 0 {
+		return
+	}
+
+	if ce := a.ob.Logger.Check(zapcore.WarnLevel, DropWarningMessage); ce != nil {
+		ce.Write(a.ob.withSeq(
+			zap.Uint64(fDropped, dropped),
+			zap.Stringer(fBackpressure, a.policy),
+		)...)
+		a.ob.emitted(zapcore.WarnLevel)
+	}
+}
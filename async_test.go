@@ -0,0 +1,375 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// slowCore is a deliberately slow core: writing a message entry waits until
+// the gate is opened, after signalling that the write started.
+type slowCore struct {
+	zapcore.Core
+	started chan struct{}
+	gate    chan struct{}
+}
+
+func newSlowCore(core zapcore.Core) *slowCore {
+	return &slowCore{
+		Core:    core,
+		started: make(chan struct{}, 100),
+		gate:    make(chan struct{}),
+	}
+}
+
+func (c *slowCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *slowCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Message != DropWarningMessage && ent.Message != ErrorLogMessage {
+		c.started <- struct{}{}
+		<-c.gate
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// sources returns the sources of the message entries, in the order written.
+func sources(entries []observer.LoggedEntry) []string {
+	list := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if source, ok := entry.ContextMap()[KeySource]; ok {
+			list = append(list, source.(string))
+		}
+	}
+	return list
+}
+
+func TestNewAsyncObserver(t *testing.T) {
+	ob := Observer{Logger: zap.NewNop()}
+
+	tests := []struct {
+		name string
+		ob   Observer
+		size int
+		opts []AsyncOption
+	}{
+		{name: "no logger", size: 1},
+		{name: "zero size", ob: ob},
+		{name: "unknown policy", ob: ob, size: 1, opts: []AsyncOption{WithBackpressure(BackpressurePolicy(3))}},
+		{name: "negative policy", ob: ob, size: 1, opts: []AsyncOption{WithBackpressure(BackpressurePolicy(-1))}},
+		{name: "zero block timeout", ob: ob, size: 1, opts: []AsyncOption{WithBlockTimeout(0)}},
+		{name: "zero warning interval", ob: ob, size: 1, opts: []AsyncOption{WithDropWarnings(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAsyncObserver(tt.ob, tt.size, tt.opts...)
+			assert.ErrorIs(t, err, ErrInvalidInput)
+			assert.Nil(t, a)
+		})
+	}
+}
+
+func TestAsyncObserver_Backpressure(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []AsyncOption
+		timeouts int
+		release  bool
+		expected []string
+		dropped  uint64
+	}{
+		{
+			name:     "drop newest",
+			expected: []string{"1", "2", "3"},
+			dropped:  2,
+		}, {
+			name:     "explicit drop newest",
+			opts:     []AsyncOption{WithBackpressure(DropNewest)},
+			expected: []string{"1", "2", "3"},
+			dropped:  2,
+		}, {
+			name:     "drop oldest",
+			opts:     []AsyncOption{WithBackpressure(DropOldest)},
+			expected: []string{"1", "4", "5"},
+			dropped:  2,
+		}, {
+			name:     "block timing out",
+			opts:     []AsyncOption{WithBackpressure(Block), WithBlockTimeout(time.Minute)},
+			timeouts: 2,
+			expected: []string{"1", "2", "3"},
+			dropped:  2,
+		}, {
+			name:     "block until there is room",
+			opts:     []AsyncOption{WithBackpressure(Block), WithBlockTimeout(time.Minute)},
+			release:  true,
+			expected: []string{"1", "2", "3", "4", "5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, recorded := observer.New(zap.InfoLevel)
+			core := newSlowCore(recorder)
+			clock := newFakeClock(time.UnixMilli(0))
+			var reported []error
+			a, err := NewAsyncObserver(Observer{
+				Logger:  zap.New(core),
+				Fields:  []FieldOpt{LogSource()},
+				Clock:   clock,
+				OnError: func(err error) { reported = append(reported, err) },
+			}, 2, tt.opts...)
+			require.NoError(t, err)
+
+			// The first message is taken by the worker, which is held up
+			// writing it, so the next two fill the buffer.
+			a.ObserveWRP(context.Background(), wrp.Message{Source: "1"})
+			<-core.started
+
+			// The messages waiting for room either time out, one at a
+			// time, or get room.
+			go func() {
+				for range tt.timeouts {
+					clock.awaitTimers(1)
+					clock.Add(time.Minute)
+				}
+				if tt.release {
+					clock.awaitTimers(1)
+					close(core.gate)
+				}
+			}()
+
+			for _, source := range []string{"2", "3", "4", "5"} {
+				a.ObserveWRP(context.Background(), wrp.Message{Source: source})
+			}
+
+			if !tt.release {
+				close(core.gate)
+			}
+			require.NoError(t, a.Close(context.Background()))
+
+			assert.Equal(t, tt.expected, sources(recorded.AllUntimed()))
+			assert.Equal(t, tt.dropped, a.Stats().Dropped)
+
+			// Every drop is reported.
+			assert.Len(t, reported, int(tt.dropped))
+			for _, err := range reported {
+				assert.ErrorIs(t, err, ErrDropped)
+			}
+		})
+	}
+}
+
+func TestAsyncObserver_BlockContext(t *testing.T) {
+	recorder, recorded := observer.New(zap.InfoLevel)
+	core := newSlowCore(recorder)
+	clock := newFakeClock(time.UnixMilli(0))
+	var reported []error
+	a, err := NewAsyncObserver(Observer{
+		Logger:  zap.New(core),
+		Fields:  []FieldOpt{LogSource()},
+		Clock:   clock,
+		OnError: func(err error) { reported = append(reported, err) },
+	}, 1, WithBackpressure(Block), WithBlockTimeout(time.Minute))
+	require.NoError(t, err)
+
+	a.ObserveWRP(context.Background(), wrp.Message{Source: "1"})
+	<-core.started
+	a.ObserveWRP(context.Background(), wrp.Message{Source: "2"})
+
+	// The context is done while the message waits for room.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		clock.awaitTimers(1)
+		cancel()
+	}()
+	a.ObserveWRP(ctx, wrp.Message{Source: "3"})
+
+	close(core.gate)
+	require.NoError(t, a.Close(context.Background()))
+
+	assert.Equal(t, []string{"1", "2"}, sources(recorded.AllUntimed()))
+	assert.Equal(t, uint64(1), a.Stats().Dropped)
+	require.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0], ErrDropped)
+	assert.ErrorContains(t, reported[0], context.Canceled.Error())
+}
+
+func TestAsyncObserver_DropWarnings(t *testing.T) {
+	recorder, recorded := observer.New(zap.InfoLevel)
+	core := newSlowCore(recorder)
+	clock := newFakeClock(time.UnixMilli(0))
+	hook, writes := written()
+	var reported int
+	a, err := NewAsyncObserver(Observer{
+		Logger:  zap.New(core, hook),
+		Fields:  []FieldOpt{LogSource()},
+		Clock:   clock,
+		OnError: func(error) { reported++ },
+	}, 1, WithBackpressure(DropOldest), WithDropWarnings(time.Hour))
+	require.NoError(t, err)
+
+	a.ObserveWRP(context.Background(), wrp.Message{Source: "1"})
+	<-core.started
+	for _, source := range []string{"2", "3", "4"} {
+		a.ObserveWRP(context.Background(), wrp.Message{Source: source})
+	}
+
+	// The periodic warning, written while the first message is still held
+	// up, and, with nothing dropped since, no warning.
+	clock.Add(time.Hour)
+	assert.Equal(t, DropWarningMessage, (<-writes).Message)
+	a.warnDropped()
+
+	a.ObserveWRP(context.Background(), wrp.Message{Source: "5"})
+	close(core.gate)
+	require.NoError(t, a.Close(context.Background()))
+
+	var warnings []map[string]any
+	for _, entry := range recorded.AllUntimed() {
+		if entry.Message == DropWarningMessage {
+			assert.Equal(t, zap.WarnLevel, entry.Level)
+			warnings = append(warnings, entry.ContextMap())
+		}
+	}
+
+	// The final warning is logged by Close.
+	assert.Equal(t, []map[string]any{
+		{KeyDropped: uint64(2), KeyBackpressure: "drop_oldest"},
+		{KeyDropped: uint64(1), KeyBackpressure: "drop_oldest"},
+	}, warnings)
+	assert.Equal(t, uint64(3), a.Stats().Dropped)
+	assert.Equal(t, 3, reported)
+}
+
+func TestAsyncObserver_NoDropWarnings(t *testing.T) {
+	recorder, recorded := observer.New(zap.InfoLevel)
+	core := newSlowCore(recorder)
+
+	// The Observer has errors of its own to rate limit.
+	ob, err := NewObserver(zap.New(core), WithLevel(zap.InfoLevel))
+	require.NoError(t, err)
+	a, err := NewAsyncObserver(ob, 1)
+	require.NoError(t, err)
+
+	a.ObserveWRP(context.Background(), wrp.Message{})
+	<-core.started
+	a.ObserveWRP(context.Background(), wrp.Message{})
+	a.ObserveWRP(context.Background(), wrp.Message{})
+	close(core.gate)
+	require.NoError(t, a.Close(context.Background()))
+
+	// Without OnError the drop is logged.
+	var messages []string
+	for _, entry := range recorded.AllUntimed() {
+		messages = append(messages, entry.Message)
+	}
+	assert.ElementsMatch(t, []string{"", "", ErrorLogMessage}, messages)
+	assert.Equal(t, uint64(1), a.Stats().Dropped)
+}
+
+func TestAsyncObserver_Close(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	var reported []error
+	a, err := NewAsyncObserver(Observer{
+		Logger:  zap.New(core),
+		Fields:  []FieldOpt{LogSource()},
+		OnError: func(err error) { reported = append(reported, err) },
+	}, 100)
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		a.ObserveWRP(context.Background(), wrp.Message{Source: "a"})
+	}
+	require.NoError(t, a.Close(context.Background()))
+	assert.Len(t, recorded.AllUntimed(), 100)
+
+	// Messages after Close are dropped and reported, and closing again does
+	// nothing.
+	a.ObserveWRP(context.Background(), wrp.Message{Source: "a"})
+	require.NoError(t, a.Close(context.Background()))
+	assert.Len(t, recorded.AllUntimed(), 100)
+	require.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0], ErrClosed)
+	assert.Zero(t, a.Stats().Dropped)
+}
+
+func TestAsyncObserver_CloseContext(t *testing.T) {
+	recorder, recorded := observer.New(zap.InfoLevel)
+	core := newSlowCore(recorder)
+	a, err := NewAsyncObserver(Observer{Logger: zap.New(core)}, 1)
+	require.NoError(t, err)
+
+	a.ObserveWRP(context.Background(), wrp.Message{})
+	<-core.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, a.Close(ctx), context.DeadlineExceeded)
+
+	// The buffered message is still logged once the core catches up.
+	close(core.gate)
+	<-a.done
+	assert.Len(t, recorded.AllUntimed(), 1)
+}
+
+func TestAsyncObserver_Concurrent(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	a, err := NewAsyncObserver(Observer{Logger: zap.New(core)}, 4,
+		WithBackpressure(DropOldest),
+		WithDropWarnings(time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	const goroutines, messages = 8, 200
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < messages; j++ {
+				a.ObserveWRP(context.Background(), wrp.Message{})
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, a.Close(context.Background()))
+
+	// Every message is either logged or dropped, and every drop is warned
+	// about.
+	var logged, warned uint64
+	for _, entry := range recorded.AllUntimed() {
+		switch entry.Message {
+		case DropWarningMessage:
+			warned += entry.ContextMap()[KeyDropped].(uint64)
+		case ErrorLogMessage:
+		default:
+			logged++
+		}
+	}
+	assert.Equal(t, uint64(goroutines*messages), logged+a.Stats().Dropped)
+	assert.Equal(t, a.Stats().Dropped, warned)
+}
+
+func TestBackpressurePolicy_String(t *testing.T) {
+	assert.Equal(t, "drop_newest", DropNewest.String())
+	assert.Equal(t, "drop_oldest", DropOldest.String())
+	assert.Equal(t, "block", Block.String())
+	assert.Equal(t, "BackpressurePolicy(7)", BackpressurePolicy(7).String())
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ObserveWRPBatch logs a batch of messages as a single entry.  The entry has
+// a messages array with one object per message containing the configured
+// Fields, along with the batch_size and the total payload_bytes of the batch.
+// When the batch is larger than BatchCap only the summary fields are logged.
+//
+// Each message is handled as ObserveWRP would up to the entry: it is kept by
+// KeepLast, recorded in the CorrelationIndex and counted by the Metrics and
+// the type counters, whose msg_type_count is added to its object, and the
+// messages the Filter rejects are left out of the entry and its summary
+// fields.  A batch whose messages are all rejected logs nothing.  The entry
+// is logged at the highest level the Escalations raise any of its messages
+// to.  The EntryBudget and the DebugFields don't apply to batches; use
+// BatchCap to bound the size of their entries.
+func (ob Observer) ObserveWRPBatch(_ context.Context, msgs []wrp.Message) {
+	if ob.Logger == nil {
+		return
+	}
+
+	kept := make([]*wrp.Message, 0, len(msgs))
+	counts := make([]zap.Field, 0, len(msgs))
+	level := ob.baseLevel()
+	for i := range msgs {
+		count, counted := ob.record(&msgs[i])
+		if ob.Filter != nil && !ob.Filter(msgs[i]) {
+			continue
+		}
+		if !counted {
+			count = zap.Skip()
+		}
+		kept = append(kept, &msgs[i])
+		counts = append(counts, count)
+		level = max(level, ob.level(&msgs[i]))
+	}
+	if len(kept) == 0 && len(msgs) > 0 {
+		return
+	}
+
+	ce := ob.Logger.Check(level, ob.Message)
+	if ce == nil {
+		return
+	}
+
+	var size int
+	for _, msg := range kept {
+		size += len(msg.Payload)
+	}
+
+	fields := []zap.Field{
+		zap.Int(fBatchSize, len(kept)),
+		zap.Int(fPayloadBytes, size),
+	}
+
+	if ob.BatchCap == 0 || len(kept) <= ob.BatchCap {
+		objects := make(messageObjects, 0, len(kept))
+		for i, msg := range kept {
+			obj := ob.fields(ob.fieldOpts(msg), msg)
+			if counts[i].Type != zapcore.SkipType {
+				obj = append(obj, counts[i])
+			}
+			objects = append(objects, messageObject(obj))
+		}
+		fields = append(fields, zap.Array(fMessages, objects))
+	}
+
+	if ob.StackOnError && level >= zapcore.ErrorLevel {
+		fields = append(fields, zap.StackSkip(fStacktrace, 1))
+	}
+
+	ce.Write(ob.withSeq(fields...)...)
+	ob.emitted(level)
+}
+
+// messageObject is the set of fields describing one message, logged as an
+// object.
+type messageObject []zap.Field
+
+func (m messageObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, field := range m {
+		field.AddTo(enc)
+	}
+	return nil
+}
+
+// messageObjects is a list of messages logged as an array.
+type messageObjects []messageObject
+
+func (m messageObjects) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, obj := range m {
+		if err := enc.AppendObject(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestObserver_ObserveWRPBatch(t *testing.T) {
+	msgs := []wrp.Message{
+		{Destination: "mac:112233445566/config", Payload: []byte("12345")},
+		{Destination: "mac:665544332211/iot", Payload: []byte("123")},
+		{Destination: "event:device-status"},
+	}
+
+	tests := []struct {
+		name     string
+		cap      int
+		msgs     []wrp.Message
+		size     int64
+		bytes    int64
+		expected []map[string]any
+	}{
+		{
+			name:     "empty",
+			expected: []map[string]any{},
+		}, {
+			name:  "small",
+			msgs:  msgs,
+			size:  3,
+			bytes: 8,
+			expected: []map[string]any{
+				{fDestination: "mac:112233445566/config"},
+				{fDestination: "mac:665544332211/iot"},
+				{fDestination: "event:device-status"},
+			},
+		}, {
+			name:  "at the cap",
+			cap:   3,
+			msgs:  msgs,
+			size:  3,
+			bytes: 8,
+			expected: []map[string]any{
+				{fDestination: "mac:112233445566/config"},
+				{fDestination: "mac:665544332211/iot"},
+				{fDestination: "event:device-status"},
+			},
+		}, {
+			name:  "over the cap",
+			cap:   2,
+			msgs:  msgs,
+			size:  3,
+			bytes: 8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zap.InfoLevel)
+			ob := Observer{
+				Logger:   zap.New(core),
+				Level:    AtLevel(zap.InfoLevel),
+				Message:  "test message",
+				Fields:   []FieldOpt{LogDestination()},
+				BatchCap: tt.cap,
+			}
+
+			ob.ObserveWRPBatch(context.Background(), tt.msgs)
+
+			entries := recorded.All()
+			require.Len(t, entries, 1)
+
+			got := entries[0].ContextMap()
+			assert.Equal(t, tt.size, got[fBatchSize])
+			assert.Equal(t, tt.bytes, got[fPayloadBytes])
+
+			if tt.expected == nil {
+				assert.NotContains(t, got, fMessages)
+				return
+			}
+
+			require.Contains(t, got, fMessages)
+			objects := got[fMessages].([]any)
+			require.Len(t, objects, len(tt.expected))
+			for i := range tt.expected {
+				assert.Equal(t, tt.expected[i], objects[i])
+			}
+		})
+	}
+}
+
+func TestObserver_ObserveWRPBatch_Disabled(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	ob := Observer{
+		Logger: zap.New(core),
+		Level:  AtLevel(zapcore.DebugLevel),
+	}
+
+	ob.ObserveWRPBatch(context.Background(), []wrp.Message{{}})
+	assert.Empty(t, recorded.All())
+
+	ob.Logger = nil
+	ob.ObserveWRPBatch(context.Background(), []wrp.Message{{}})
+}
+
+func TestObserver_ObserveWRPBatch_StackOnError(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	ob := Observer{
+		Logger:       zap.New(core),
+		Level:        AtLevel(zapcore.ErrorLevel),
+		Fields:       []FieldOpt{LogTransactionUUID()},
+		StackOnError: true,
+	}
+
+	ob.ObserveWRPBatch(context.Background(), []wrp.Message{{}})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+
+	stack := entries[0].ContextMap()[KeyStacktrace]
+	require.IsType(t, "", stack)
+	assert.True(t, strings.HasPrefix(stack.(string), "github.com/xmidt-org/wrpzap.TestObserver_ObserveWRPBatch_StackOnError"),
+		"unexpected stack: %s", stack)
+}
+
+func TestObserver_ObserveWRPBatch_PerMessage(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	index, err := NewCorrelationIndex(10, 10)
+	require.NoError(t, err)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithFields(LogDestination()),
+		WithFilter(func(msg wrp.Message) bool { return msg.Destination != "event:filtered" }),
+		WithEscalations(EscalateOnRDRFailure()),
+		WithKeepLast(),
+		WithTypeCounters(),
+		WithCorrelationIndex(index),
+	)
+	require.NoError(t, err)
+
+	ob.ObserveWRPBatch(context.Background(), []wrp.Message{
+		{Type: wrp.SimpleEventMessageType, Destination: "event:kept", Payload: []byte("123")},
+		{Type: wrp.SimpleEventMessageType, Destination: "event:filtered", Payload: []byte("12345")},
+		{
+			Type:                    wrp.SimpleRequestResponseMessageType,
+			Source:                  "mac:112233445566/config",
+			Destination:             "dns:talaria.example.net",
+			TransactionUUID:         "uuid-1",
+			RequestDeliveryResponse: int64p(1),
+		},
+	})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.ErrorLevel, entries[0].Level, "the entry is logged at the highest escalated level")
+
+	got := entries[0].ContextMap()
+	assert.Equal(t, int64(2), got[KeyBatchSize])
+	assert.Equal(t, int64(3), got[KeyPayloadBytes])
+	assert.Equal(t, []any{
+		map[string]any{KeyDestination: "event:kept", KeyMsgTypeCount: uint64(1)},
+		map[string]any{KeyDestination: "dns:talaria.example.net", KeyMsgTypeCount: uint64(1)},
+	}, got[KeyMessages])
+
+	// Rejected messages are still observed, as with ObserveWRP.
+	last, _, found := ob.LastObserved()
+	require.True(t, found)
+	assert.Equal(t, "uuid-1", last.TransactionUUID)
+	assert.Len(t, index.LastTransactions("mac:112233445566"), 1)
+
+	// A batch whose messages are all rejected logs nothing.
+	ob.ObserveWRPBatch(context.Background(), []wrp.Message{{Destination: "event:filtered"}})
+	assert.Len(t, recorded.AllUntimed(), 1)
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"cmp"
+	"slices"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Priority orders the fields of an entry for dropping when the entry is over
+// its budget, see Observer.EntryBudget.  Fields with a lower priority are
+// dropped first.
+type Priority int
+
+const (
+	// PriorityLow is the priority of the payload.
+	PriorityLow Priority = -1
+
+	// PriorityNormal is the priority of the fields without one of their own.
+	PriorityNormal Priority = 0
+
+	// PriorityHigh is the priority of the routing fields: the message type,
+	// source, destination, transaction UUID, session ID, partner IDs, status,
+	// RDR and QOS.
+	PriorityHigh Priority = 1
+)
+
+// EntryOverhead is the size the entry budget sets aside for what an entry
+// holds besides its fields and message text: the time, level, logger name
+// and caller, and the fields the Observer adds itself, like seq.
+const EntryOverhead = 256
+
+// defaultPriorities are the priorities of the fields that are not
+// PriorityNormal.
+var defaultPriorities = map[string]Priority{
+	KeyMsgType:                 PriorityHigh,
+	KeySource:                  PriorityHigh,
+	KeyDestination:             PriorityHigh,
+	KeyTransactionUUID:         PriorityHigh,
+	KeySessionID:               PriorityHigh,
+	KeyPartnerIDs:              PriorityHigh,
+	KeyStatus:                  PriorityHigh,
+	KeyRequestDeliveryResponse: PriorityHigh,
+	KeyQualityOfService:        PriorityHigh,
+	KeyPayload:                 PriorityLow,
+	KeyPayloadPreview:          PriorityLow,
+}
+
+// priority returns the priority of the field with the key.
+func (ob Observer) priority(key string) Priority {
+	if p, found := ob.FieldPriorities[key]; found {
+		return p
+	}
+	return defaultPriorities[key]
+}
+
+// fieldOverhead is the JSON around a field: the quotes around the key, the
+// colon and the comma.
+const fieldOverhead = 4
+
+// scalarSize is the size allowed for numbers, booleans and times, the
+// longest they can be.
+const scalarSize = 24
+
+// stringSize is the estimated size of a string field with the key and a
+// value of n bytes.
+func stringSize(key string, n int) int {
+	return len(key) + fieldOverhead + n + 2
+}
+
+// scalarFieldSize is the estimated size of a number, boolean or time field
+// with the key.
+func scalarFieldSize(key string) int {
+	return len(key) + fieldOverhead + scalarSize
+}
+
+// estimateSize estimates the size of the field encoded as JSON.  Strings are
+// counted without escaping and numbers, booleans and times as scalarSize.
+// Lazy fields carry their own estimate, so they are not computed before the
+// entry is encoded.  Other fields, like objects and arrays, are encoded with
+// enc to measure them.
+func estimateSize(enc zapcore.Encoder, field zap.Field) int {
+	if _, ok := field.Interface.(lazyFunc); ok && field.Type == zapcore.InlineMarshalerType {
+		return int(field.Integer)
+	}
+
+	size := len(field.Key) + fieldOverhead
+	switch field.Type {
+	case zapcore.StringType:
+		return size + len(field.String) + 2
+	case zapcore.BinaryType:
+		n := len(field.Interface.([]byte))
+		return size + (n+2)/3*4 + 2
+	case zapcore.ByteStringType:
+		return size + len(field.Interface.([]byte)) + 2
+	case zapcore.BoolType, zapcore.DurationType, zapcore.TimeType,
+		zapcore.Float64Type, zapcore.Float32Type,
+		zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type,
+		zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type,
+		zapcore.UintptrType:
+		return size + scalarSize
+	}
+
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{field})
+	if err != nil {
+		return size
+	}
+	defer buf.Free()
+
+	// The entry is the field in braces followed by a newline.
+	return buf.Len() - 3 + 1
+}
+
+// budgetEncoder measures the fields estimateSize can't.
+var budgetEncoder = zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+
+// fitBudget drops the fields with the lowest priority until the estimated
+// size of the entry with the message text is within the EntryBudget, and
+// names the dropped fields, in the order they were dropped, in
+// fields_dropped.  Among fields with the same priority the last one is
+// dropped first.
+func (ob Observer) fitBudget(text string, fields []zap.Field) []zap.Field {
+	if ob.EntryBudget <= 0 {
+		return fields
+	}
+
+	sizes := make([]int, len(fields))
+	total := EntryOverhead + len(text)
+	for i, field := range fields {
+		sizes[i] = estimateSize(budgetEncoder, field)
+		total += sizes[i]
+	}
+	if total <= ob.EntryBudget {
+		return fields
+	}
+
+	order := make([]int, len(fields))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		if c := cmp.Compare(ob.priority(fields[a].Key), ob.priority(fields[b].Key)); c != 0 {
+			return c
+		}
+		return cmp.Compare(b, a)
+	})
+
+	// fields_dropped is counted with the fields it names.
+	total += len(fFieldsDropped) + fieldOverhead + 2
+
+	dropped := make([]bool, len(fields))
+	var names []string
+	for _, i := range order {
+		if total <= ob.EntryBudget {
+			break
+		}
+		dropped[i] = true
+		names = append(names, fields[i].Key)
+		total += len(fields[i].Key) + 3 - sizes[i]
+	}
+
+	kept := fields[:0]
+	for i, field := range fields {
+		if !dropped[i] {
+			kept = append(kept, field)
+		}
+	}
+	return append(kept, zap.Strings(fFieldsDropped, names))
+}
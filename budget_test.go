@@ -0,0 +1,246 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// overBudget returns a message whose payload, metadata and headers are each
+// a few kilobytes.
+func overBudget() wrp.Message {
+	msg := wrp.Message{
+		Type:            wrp.SimpleEventMessageType,
+		Source:          "mac:112233445566",
+		Destination:     "event:device-status/mac:112233445566/online",
+		TransactionUUID: "c07ee5e1-70be-444c-a156-097c767ad8aa",
+		Metadata:        make(map[string]string),
+		Payload:         bytes.Repeat([]byte("p"), 8000),
+	}
+	for i := 0; i < 20; i++ {
+		msg.Metadata[fmt.Sprintf("/key-%02d", i)] = strings.Repeat("m", 100)
+		msg.Headers = append(msg.Headers, fmt.Sprintf("X-Header-%02d: %s", i, strings.Repeat("h", 100)))
+	}
+	return msg
+}
+
+func TestWithEntryBudget(t *testing.T) {
+	fields := []FieldOpt{
+		LogSource(),
+		LogDestination(),
+		LogTransactionUUID(),
+		LogMetadata(),
+		LogHeaders(),
+		LogPayload(),
+	}
+
+	tests := []struct {
+		name       string
+		budget     int
+		priorities map[string]Priority
+		expected   []string
+	}{
+		{name: "no budget"},
+		{name: "within budget", budget: 32 * 1024},
+		{name: "payload", budget: 6000, expected: []string{KeyPayload}},
+		{
+			name:     "later fields first",
+			budget:   4000,
+			expected: []string{KeyPayload, KeyHeaders},
+		}, {
+			name:       "priorities",
+			budget:     4000,
+			priorities: map[string]Priority{KeyHeaders: PriorityHigh},
+			expected:   []string{KeyPayload, KeyMetadata},
+		}, {
+			name:       "lower than the payload",
+			budget:     6000,
+			priorities: map[string]Priority{KeyMetadata: PriorityLow - 1},
+			expected:   []string{KeyMetadata, KeyPayload},
+		}, {
+			name:   "everything",
+			budget: 100,
+			expected: []string{
+				KeyPayload,
+				KeyHeaders,
+				KeyMetadata,
+				KeyTransactionUUID,
+				KeyDestination,
+				KeySource,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zap.InfoLevel)
+			ob, err := NewObserver(zap.New(core),
+				WithLevel(zap.InfoLevel),
+				WithMessage("wrp"),
+				WithFields(fields...),
+				WithEntryBudget(tt.budget),
+				WithFieldPriorities(tt.priorities),
+			)
+			require.NoError(t, err)
+			ob.ObserveWRP(context.Background(), overBudget())
+
+			entries := recorded.AllUntimed()
+			require.Len(t, entries, 1)
+			logged := entries[0].ContextMap()
+
+			if tt.expected == nil {
+				assert.Len(t, logged, len(fields))
+				assert.NotContains(t, logged, KeyFieldsDropped)
+				return
+			}
+
+			var dropped []string
+			for _, key := range logged[KeyFieldsDropped].([]any) {
+				dropped = append(dropped, key.(string))
+			}
+			assert.Equal(t, tt.expected, dropped)
+			assert.Len(t, logged, len(fields)-len(tt.expected)+1)
+			for _, key := range tt.expected {
+				assert.NotContains(t, logged, key)
+			}
+		})
+	}
+}
+
+func TestWithEntryBudget_Lazy(t *testing.T) {
+	var calls int
+	counted := func(wrp.Message) zap.Field {
+		return lazy("calls", scalarFieldSize("calls"), func(enc zapcore.ObjectEncoder) {
+			calls++
+			enc.AddInt("calls", calls)
+		})
+	}
+
+	core, recorded := observer.New(zap.InfoLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithMessage("wrp"),
+		WithFields(LogSource(), LogPayloadPreviewLines(50), LogPath(), LogPayloadIsValidJSON(), counted),
+		WithEntryBudget(600),
+	)
+	require.NoError(t, err)
+	ob.ObserveWRP(context.Background(), wrp.Message{
+		Source:      "mac:112233445566",
+		Path:        "/config/wifi",
+		ContentType: "application/json",
+		Payload:     []byte("[\n" + strings.Repeat("  \"value\",\n", 50) + "  \"value\"\n]"),
+	})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{
+		KeySource:             "mac:112233445566",
+		KeyPath:               "/config/wifi",
+		KeyPayloadIsValidJSON: true,
+		"calls":               1,
+		KeyFieldsDropped:      []any{KeyPayloadPreview},
+	}, entries[0].ContextMap())
+	assert.Equal(t, 1, calls, "the budget must not compute lazy fields")
+}
+
+func TestWithEntryBudget_Encoded(t *testing.T) {
+	for _, budget := range []int{1000, 2000, 4000, 8000, 12000} {
+		t.Run(fmt.Sprint(budget), func(t *testing.T) {
+			var buf bytes.Buffer
+			core := zapcore.NewCore(
+				zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+				zapcore.AddSync(&buf),
+				zap.InfoLevel,
+			)
+			ob, err := NewObserver(zap.New(core, zap.AddCaller()),
+				WithLevel(zap.InfoLevel),
+				WithMessage("wrp"),
+				WithFields(AllFields()...),
+				WithEntryBudget(budget),
+			)
+			require.NoError(t, err)
+			ob.ObserveWRP(context.Background(), overBudget())
+
+			assert.LessOrEqual(t, buf.Len(), budget)
+			assert.Contains(t, buf.String(), `"`+KeyFieldsDropped+`":[`)
+		})
+	}
+}
+
+func TestWithEntryBudget_Debug(t *testing.T) {
+	core, recorded := observer.New(zap.DebugLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithFields(LogSource()),
+		WithDebugFields(LogPayload()),
+		WithEntryBudget(4000),
+	)
+	require.NoError(t, err)
+	ob.ObserveWRP(context.Background(), overBudget())
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.NotContains(t, entries[0].ContextMap(), KeyFieldsDropped)
+	assert.Equal(t, []any{KeyPayload}, entries[1].ContextMap()[KeyFieldsDropped])
+}
+
+func TestWithEntryBudget_Invalid(t *testing.T) {
+	_, err := NewObserver(zap.NewNop(), WithEntryBudget(-1))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestWithEntryBudget_String(t *testing.T) {
+	ob, err := NewObserver(zap.NewNop(),
+		WithLevel(zap.InfoLevel),
+		WithFields(LogSource()),
+		WithEntryBudget(16*1024),
+		WithFieldPriorities(map[string]Priority{KeyHeaders: PriorityHigh, KeyMetadata: PriorityLow}),
+	)
+	require.NoError(t, err)
+	assert.Contains(t, ob.String(), " entry_budget=16384 field_priorities=[headers=1 metadata=-1]")
+}
+
+func TestEstimateSize(t *testing.T) {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	fields := []zap.Field{
+		zap.String("string", "value"),
+		zap.Binary("binary", []byte("binary value")),
+		zap.ByteString("bytes", []byte("byte string")),
+		zap.Bool("bool", true),
+		zap.Int64("int", -1234567890),
+		zap.Uint8("uint", 255),
+		zap.Float64("float", 1.5),
+		zap.Duration("duration", time.Hour),
+		zap.Time("time", time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)),
+		zap.Strings("strings", []string{"a", "b"}),
+		LogMetadata()(wrp.Message{Metadata: map[string]string{"/a": "1", "/b": "2"}}),
+		LogPayloadIsValidJSON()(wrp.Message{ContentType: "application/json", Payload: []byte("{}")}),
+		LogPayloadPreviewLines(2)(wrp.Message{Payload: []byte("one\ntwo\nthree")}),
+		MustLogSessionIDHashed([]byte("salt"))(wrp.Message{SessionID: "session"}),
+		LogUnknownMetadataKeys("/a").AppendFields(wrp.Message{Metadata: map[string]string{"/a": "1", "/b": "2"}}, nil)[0],
+	}
+
+	for _, field := range fields {
+		t.Run(field.Key, func(t *testing.T) {
+			buf, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{field})
+			require.NoError(t, err)
+
+			// The encoded field without the braces and newline, with a comma.
+			actual := buf.Len() - 3 + 1
+			assert.GreaterOrEqual(t, estimateSize(enc, field), actual)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// LoggerBuilder builds a logger from configuration, like the Build method of
+// zap.Config or sallust.Config.
+type LoggerBuilder func() (*zap.Logger, error)
+
+// NewObserverFromBuilder builds the logger and creates an Observer that logs
+// to it, so that an Observer can be created from logging configuration in one
+// call.  Errors building the logger are returned.  For example, with a zap
+// configuration:
+//
+//	ob, err := wrpzap.NewObserverFromBuilder(
+//		func() (*zap.Logger, error) { return cfg.Build() },
+//		wrpzap.WithLevel(zapcore.DebugLevel),
+//		wrpzap.WithDefaultFields(),
+//	)
+//
+// See NewObserverFromSallust for sallust configuration.
+func NewObserverFromBuilder(build LoggerBuilder, opts ...Option) (Observer, error) {
+	if build == nil {
+		return Observer{}, fmt.Errorf("%w: builder is nil", ErrInvalidInput)
+	}
+
+	logger, err := build()
+	if err != nil {
+		return Observer{}, fmt.Errorf("building the logger: %w", err)
+	}
+
+	return NewObserver(logger, opts...)
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// memorySink is a zap.Sink that keeps what is written to it.
+type memorySink struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (s *memorySink) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *memorySink) Bytes() []byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return bytes.Clone(s.buf.Bytes())
+}
+
+func (*memorySink) Sync() error  { return nil }
+func (*memorySink) Close() error { return nil }
+
+var (
+	registerSinkOnce sync.Once
+	memorySinksLock  sync.Mutex
+	memorySinks      = map[string]*memorySink{}
+	memorySinkCount  int
+)
+
+// newMemorySink returns a fresh memorySink along with the zap output path that
+// refers to it.  The "wrpzap-memory" scheme is registered only once per
+// process; each sink is looked up by the host of the output path, so tests
+// never share output, even with -count.
+func newMemorySink(t *testing.T) (*memorySink, string) {
+	t.Helper()
+	registerSinkOnce.Do(func() {
+		require.NoError(t, zap.RegisterSink("wrpzap-memory", func(u *url.URL) (zap.Sink, error) {
+			memorySinksLock.Lock()
+			defer memorySinksLock.Unlock()
+			if s, ok := memorySinks[u.Host]; ok {
+				return s, nil
+			}
+			return nil, fmt.Errorf("no memory sink named %q", u.Host)
+		}))
+	})
+
+	memorySinksLock.Lock()
+	defer memorySinksLock.Unlock()
+	memorySinkCount++
+	host := fmt.Sprintf("sink%d", memorySinkCount)
+	sink := &memorySink{}
+	memorySinks[host] = sink
+	t.Cleanup(func() {
+		memorySinksLock.Lock()
+		defer memorySinksLock.Unlock()
+		delete(memorySinks, host)
+	})
+
+	return sink, "wrpzap-memory://" + host
+}
+
+func TestNewObserverFromBuilder(t *testing.T) {
+	sink, path := newMemorySink(t)
+
+	cfg := zap.NewProductionConfig()
+	cfg.OutputPaths = []string{path}
+	cfg.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+
+	ob, err := NewObserverFromBuilder(func() (*zap.Logger, error) { return cfg.Build() },
+		WithLevel(zapcore.DebugLevel),
+		WithMessage("wrp received"),
+		WithFields(LogSource()),
+	)
+	require.NoError(t, err)
+
+	ob.ObserveWRP(context.Background(), wrp.Message{Source: "mac:112233445566"})
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(sink.Bytes(), &entry))
+	assert.Equal(t, "debug", entry["level"])
+	assert.Equal(t, "wrp received", entry["msg"])
+	assert.Equal(t, "mac:112233445566", entry[KeySource])
+}
+
+func TestNewObserverFromBuilder_Errors(t *testing.T) {
+	_, err := NewObserverFromBuilder(nil)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	errBuild := errors.New("bad config")
+	_, err = NewObserverFromBuilder(func() (*zap.Logger, error) { return nil, errBuild })
+	assert.ErrorIs(t, err, errBuild)
+
+	// A bad output path is reported by zap.Config.Build.
+	cfg := zap.NewProductionConfig()
+	cfg.OutputPaths = []string{"unregistered-scheme://"}
+	_, err = NewObserverFromBuilder(func() (*zap.Logger, error) { return cfg.Build() })
+	assert.Error(t, err)
+
+	// Option errors are returned too.
+	_, err = NewObserverFromBuilder(func() (*zap.Logger, error) { return zap.NewNop(), nil }, WithLevel(zap.InfoLevel), WithBatchCap(-1))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
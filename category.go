@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// MessageCategory groups message types that are handled alike.
+type MessageCategory string
+
+// The categories CategoryOf puts the message types in.
+const (
+	CategoryRequest       MessageCategory = "request"       // SimpleRequestResponse
+	CategoryEvent         MessageCategory = "event"         // SimpleEvent
+	CategoryCRUD          MessageCategory = "crud"          // Create, Retrieve, Update and Delete
+	CategoryAuthorization MessageCategory = "authorization" // Authorization
+	CategoryService       MessageCategory = "service"       // ServiceRegistration and ServiceAlive
+	CategoryUnknown       MessageCategory = "unknown"       // any other type
+)
+
+// CategoryOf returns the category of the message type.
+func CategoryOf(mt wrp.MessageType) MessageCategory {
+	switch mt {
+	case wrp.SimpleRequestResponseMessageType:
+		return CategoryRequest
+	case wrp.SimpleEventMessageType:
+		return CategoryEvent
+	case wrp.CreateMessageType, wrp.RetrieveMessageType, wrp.UpdateMessageType, wrp.DeleteMessageType:
+		return CategoryCRUD
+	case wrp.AuthorizationMessageType:
+		return CategoryAuthorization
+	case wrp.ServiceRegistrationMessageType, wrp.ServiceAliveMessageType:
+		return CategoryService
+	default:
+		return CategoryUnknown
+	}
+}
+
+// ExpectsResponse reports whether messages of the category are requests that
+// get a response.
+func (c MessageCategory) ExpectsResponse() bool {
+	return c == CategoryRequest || c == CategoryCRUD
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+func TestCategoryOf(t *testing.T) {
+	tests := []struct {
+		mt       wrp.MessageType
+		expected MessageCategory
+		response bool
+	}{
+		{mt: wrp.Invalid0MessageType, expected: CategoryUnknown},
+		{mt: wrp.Invalid1MessageType, expected: CategoryUnknown},
+		{mt: wrp.AuthorizationMessageType, expected: CategoryAuthorization},
+		{mt: wrp.SimpleRequestResponseMessageType, expected: CategoryRequest, response: true},
+		{mt: wrp.SimpleEventMessageType, expected: CategoryEvent},
+		{mt: wrp.CreateMessageType, expected: CategoryCRUD, response: true},
+		{mt: wrp.RetrieveMessageType, expected: CategoryCRUD, response: true},
+		{mt: wrp.UpdateMessageType, expected: CategoryCRUD, response: true},
+		{mt: wrp.DeleteMessageType, expected: CategoryCRUD, response: true},
+		{mt: wrp.ServiceRegistrationMessageType, expected: CategoryService},
+		{mt: wrp.ServiceAliveMessageType, expected: CategoryService},
+		{mt: wrp.UnknownMessageType, expected: CategoryUnknown},
+		{mt: wrp.LastMessageType, expected: CategoryUnknown},
+		{mt: -1, expected: CategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mt.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, CategoryOf(tt.mt))
+			assert.Equal(t, tt.response, CategoryOf(tt.mt).ExpectsResponse())
+
+			// The response expectation matches the types wrp requires a
+			// transaction for.
+			assert.Equal(t, tt.mt.RequiresTransaction(), CategoryOf(tt.mt).ExpectsResponse())
+		})
+	}
+}
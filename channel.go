@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// ObserveChannel taps a channel of messages: every message received from in
+// is logged by the Observer and then sent, unchanged and in order, on the
+// returned channel.  The returned channel is unbuffered and the next message
+// isn't received until the last one was sent, so the tap applies the same
+// backpressure as a plain loop copying from one channel to the other.
+//
+// The returned channel is closed when in is closed or ctx is cancelled.  A
+// message that was received but not yet sent when ctx is cancelled is not
+// sent.
+func ObserveChannel(ctx context.Context, in <-chan wrp.Message, ob Observer) <-chan wrp.Message {
+	out := make(chan wrp.Message)
+
+	go func() {
+		defer close(out)
+
+		for {
+			var msg wrp.Message
+			select {
+			case <-ctx.Done():
+				return
+			case m, ok := <-in:
+				if !ok {
+					return
+				}
+				msg = m
+			}
+
+			ob.ObserveWRPPtr(ctx, &msg)
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- msg:
+			}
+		}
+	}()
+
+	return out
+}
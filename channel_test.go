@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func channelObserver() (Observer, *observer.ObservedLogs) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	return Observer{
+		Logger: zap.New(core),
+		Fields: []FieldOpt{LogTransactionUUID()},
+	}, recorded
+}
+
+// drain receives from the channel until it is closed, failing the test if that
+// takes too long.
+func drain(t *testing.T, ch <-chan wrp.Message) []wrp.Message {
+	var msgs []wrp.Message
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return msgs
+			}
+			msgs = append(msgs, msg)
+		case <-timeout:
+			require.FailNow(t, "the output channel was not closed")
+		}
+	}
+}
+
+func TestObserveChannel(t *testing.T) {
+	ob, recorded := channelObserver()
+	in := make(chan wrp.Message)
+	out := ObserveChannel(context.Background(), in, ob)
+
+	var sent []wrp.Message
+	go func() {
+		defer close(in)
+		for i := 0; i < 100; i++ {
+			msg := wrp.Message{
+				TransactionUUID: strconv.Itoa(i),
+				Metadata:        map[string]string{"i": strconv.Itoa(i)},
+			}
+			sent = append(sent, msg)
+			in <- msg
+		}
+	}()
+
+	got := drain(t, out)
+	assert.Equal(t, sent, got, "messages must be forwarded unchanged and in order")
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 100)
+	for i, entry := range entries {
+		assert.Equal(t, strconv.Itoa(i), entry.ContextMap()[KeyTransactionUUID])
+	}
+}
+
+func TestObserveChannel_Backpressure(t *testing.T) {
+	ob, recorded := channelObserver()
+	in := make(chan wrp.Message, 10)
+	for i := 0; i < 10; i++ {
+		in <- wrp.Message{TransactionUUID: strconv.Itoa(i)}
+	}
+	close(in)
+
+	out := ObserveChannel(context.Background(), in, ob)
+
+	// Without a reader, the tap holds one message and waits: it doesn't drain
+	// the input.
+	assert.Eventually(t, func() bool { return len(in) == 9 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, in, 9)
+	assert.Len(t, recorded.AllUntimed(), 1)
+
+	msgs := drain(t, out)
+	require.Len(t, msgs, 10)
+	for i, msg := range msgs {
+		assert.Equal(t, strconv.Itoa(i), msg.TransactionUUID)
+	}
+}
+
+func TestObserveChannel_Cancel(t *testing.T) {
+	ob, _ := channelObserver()
+	in := make(chan wrp.Message)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := ObserveChannel(ctx, in, ob)
+
+	in <- wrp.Message{TransactionUUID: "1"}
+	msg := <-out
+	assert.Equal(t, "1", msg.TransactionUUID)
+
+	// Cancel mid-stream, with a message waiting to be sent on the output.
+	in <- wrp.Message{TransactionUUID: "2"}
+	cancel()
+
+	drain(t, out)
+
+	// The tap no longer receives from the input.
+	select {
+	case in <- wrp.Message{TransactionUUID: "3"}:
+		assert.Fail(t, "the tap received a message after it was cancelled")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestObserveChannel_CancelWhileWaiting(t *testing.T) {
+	ob, recorded := channelObserver()
+	in := make(chan wrp.Message)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := ObserveChannel(ctx, in, ob)
+
+	cancel()
+	assert.Empty(t, drain(t, out))
+	assert.Empty(t, recorded.AllUntimed())
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import "time"
+
+// Clock provides the current time.  It allows time based behavior to be
+// tested deterministically.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc is a function that implements Clock.
+type ClockFunc func() time.Time
+
+// Now returns the current time.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// Timers creates timers and tickers.  When the Observer's Clock also
+// implements Timers, it is used for the AsyncObserver's block timeout and
+// drop warnings and for the periodic entries of the TopNObserver and the
+// PartnerSummaryObserver, so they can be tested deterministically too.
+// Otherwise the system's timers are used.
+type Timers interface {
+	// NewTimer creates a Timer that fires once, after d.
+	NewTimer(d time.Duration) Timer
+
+	// NewTicker creates a Timer that fires every d.
+	NewTicker(d time.Duration) Timer
+}
+
+// Timer is a timer or ticker created by Timers.
+type Timer interface {
+	// C returns the channel the time is sent on when the Timer fires.
+	C() <-chan time.Time
+
+	// Stop stops the Timer.  It does not close the channel.
+	Stop()
+}
+
+// systemTime is the Clock and Timers of the system.
+type systemTime struct{}
+
+func (systemTime) Now() time.Time {
+	return time.Now()
+}
+
+func (systemTime) NewTimer(d time.Duration) Timer {
+	return systemTimer{t: time.NewTimer(d)}
+}
+
+func (systemTime) NewTicker(d time.Duration) Timer {
+	return systemTicker{t: time.NewTicker(d)}
+}
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (s systemTimer) C() <-chan time.Time { return s.t.C }
+func (s systemTimer) Stop()               { s.t.Stop() }
+
+type systemTicker struct {
+	t *time.Ticker
+}
+
+func (s systemTicker) C() <-chan time.Time { return s.t.C }
+func (s systemTicker) Stop()               { s.t.Stop() }
+
+// systemClock is the Clock used when none is provided.
+var systemClock Clock = systemTime{}
+
+// clock returns the Observer's Clock, or the system clock if it has none.
+func (ob Observer) clock() Clock {
+	return clockOrDefault(ob.Clock)
+}
+
+// timers returns the Observer's Clock when it implements Timers, or the
+// system's timers.
+func (ob Observer) timers() Timers {
+	if t, ok := ob.Clock.(Timers); ok {
+		return t
+	}
+	return systemTime{}
+}
+
+// clockOrDefault returns the clock, or the system clock if it is nil.
+func clockOrDefault(c Clock) Clock {
+	if c == nil {
+		return systemClock
+	}
+	return c
+}
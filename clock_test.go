@@ -0,0 +1,232 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeClock is a Clock that only moves when told to.  Its timers and tickers
+// fire when Add moves it past them.
+type fakeClock struct {
+	lock    sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	timers  []*fakeTimer
+}
+
+// fakeTimer is a timer, or a ticker when period is set, of a fakeClock.
+type fakeTimer struct {
+	clock  *fakeClock
+	c      chan time.Time
+	next   time.Time
+	period time.Duration
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	c := &fakeClock{now: now}
+	c.changed = sync.NewCond(&c.lock)
+	return c
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// Add moves the clock forward, firing the timers and tickers it passes.  Like
+// the system's, a ticker fires once however many periods pass.
+func (c *fakeClock) Add(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+
+	running := c.timers[:0]
+	for _, t := range c.timers {
+		if t.next.After(c.now) {
+			running = append(running, t)
+			continue
+		}
+
+		select {
+		case t.c <- c.now:
+		default:
+		}
+
+		if t.period > 0 {
+			for !t.next.After(c.now) {
+				t.next = t.next.Add(t.period)
+			}
+			running = append(running, t)
+		}
+	}
+	clear(c.timers[len(running):])
+	c.timers = running
+	c.changed.Broadcast()
+}
+
+// awaitTimers waits until n timers and tickers are running, so a test can
+// move the clock once a goroutine is waiting on one.
+func (c *fakeClock) awaitTimers(n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for len(c.timers) < n {
+		c.changed.Wait()
+	}
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	return c.start(d, 0)
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) Timer {
+	return c.start(d, d)
+}
+
+func (c *fakeClock) start(d, period time.Duration) *fakeTimer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	t := &fakeTimer{
+		clock:  c,
+		c:      make(chan time.Time, 1),
+		next:   c.now.Add(d),
+		period: period,
+	}
+	c.timers = append(c.timers, t)
+	c.changed.Broadcast()
+	return t
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() {
+	c := t.clock
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.timers = slices.DeleteFunc(c.timers, func(other *fakeTimer) bool {
+		return other == t
+	})
+	c.changed.Broadcast()
+}
+
+// written returns a zap.Option sending each entry the logger writes to the
+// channel, so a test can wait for the entries written by other goroutines.
+func written() (zap.Option, <-chan zapcore.Entry) {
+	entries := make(chan zapcore.Entry, 100)
+	return zap.Hooks(func(entry zapcore.Entry) error {
+		entries <- entry
+		return nil
+	}), entries
+}
+
+func TestFakeClock(t *testing.T) {
+	clock := newFakeClock(time.UnixMilli(0))
+	timer := clock.NewTimer(time.Second)
+	ticker := clock.NewTicker(time.Second)
+
+	clock.Add(999 * time.Millisecond)
+	assert.Empty(t, timer.C())
+	assert.Empty(t, ticker.C())
+
+	clock.Add(time.Millisecond)
+	assert.Equal(t, time.UnixMilli(1000), <-timer.C())
+	assert.Equal(t, time.UnixMilli(1000), <-ticker.C())
+
+	clock.Add(2500 * time.Millisecond)
+	assert.Empty(t, timer.C(), "a timer only fires once")
+	assert.Equal(t, time.UnixMilli(3500), <-ticker.C())
+	assert.Empty(t, ticker.C(), "a ticker fires once however many periods pass")
+
+	ticker.Stop()
+	clock.Add(time.Hour)
+	assert.Empty(t, ticker.C())
+}
+
+func TestSystemTimers(t *testing.T) {
+	timers := Observer{}.timers()
+	assert.Equal(t, systemTime{}, timers)
+
+	timer := timers.NewTimer(time.Millisecond)
+	<-timer.C()
+	timer.Stop()
+
+	ticker := timers.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+
+	clock := newFakeClock(time.UnixMilli(0))
+	assert.Equal(t, clock, Observer{Clock: clock}.timers())
+	assert.Equal(t, systemTime{}, Observer{Clock: ClockFunc(time.Now)}.timers(), "a Clock without Timers uses the system's")
+}
+
+func TestClockOrDefault(t *testing.T) {
+	before := time.Now()
+	now := clockOrDefault(nil).Now()
+	assert.False(t, now.Before(before))
+
+	fixed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := ClockFunc(func() time.Time { return fixed })
+	assert.Equal(t, fixed, clockOrDefault(clock).Now())
+}
+
+// TestNoDirectTime makes sure the time is only read, and timers are only
+// created, through a Clock, so that all time based behavior can be tested
+// with a fake clock.
+func TestNoDirectTime(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != "clock.go"
+	}, 0)
+	require.NoError(t, err)
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				sel, ok := n.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "time" {
+					switch sel.Sel.Name {
+					case "Now", "Since", "Until", "NewTimer", "NewTicker", "After", "AfterFunc", "Tick", "Sleep":
+						t.Errorf("%s: time.%s is used instead of a Clock", fset.Position(sel.Pos()), sel.Sel.Name)
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	clock := newFakeClock(time.UnixMilli(1000))
+	ob, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithClock(clock))
+	require.NoError(t, err)
+	assert.Equal(t, clock, ob.Clock)
+	assert.Equal(t, time.UnixMilli(1000), ob.clock().Now())
+	assert.Contains(t, ob.String(), " clock=true")
+
+	_, err = NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithClock(nil))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	before := time.UnixMilli(0)
+	assert.True(t, Observer{}.clock().Now().After(before), "the system clock is used by default")
+}
@@ -0,0 +1,25 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+)
+
+// Closer is implemented by the observers that keep state between messages.
+// Close flushes what they hold, like buffered messages or a final summary,
+// and logs it before returning.  Once Close is called the messages observed
+// are not logged; they are reported to the Observer's OnError as ErrClosed.
+// Calling Close again does nothing and returns nil.  An error is only
+// returned when the context is done before the flush finishes.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+var (
+	_ Closer = (*AsyncObserver)(nil)
+	_ Closer = (*DeltaObserver)(nil)
+	_ Closer = (*PartnerSummaryObserver)(nil)
+	_ Closer = (*TopNObserver)(nil)
+)
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// closingObserver is an observer implementing Closer.
+type closingObserver interface {
+	Closer
+	ObserveWRP(context.Context, wrp.Message)
+}
+
+func TestCloser(t *testing.T) {
+	const burst = 500
+
+	tests := []struct {
+		name string
+		new  func(ob Observer) (closingObserver, error)
+
+		// logged returns the number of messages the entries account for.
+		logged func(entries []observer.LoggedEntry) int
+	}{
+		{
+			name: "async",
+			new: func(ob Observer) (closingObserver, error) {
+				return NewAsyncObserver(ob, burst)
+			},
+			logged: func(entries []observer.LoggedEntry) int { return len(entries) },
+		}, {
+			name: "delta",
+			new: func(ob Observer) (closingObserver, error) {
+				return NewDeltaObserver(ob, 10)
+			},
+			logged: func(entries []observer.LoggedEntry) int { return len(entries) },
+		}, {
+			name: "partner summary",
+			new: func(ob Observer) (closingObserver, error) {
+				return NewPartnerSummaryObserver(ob, 10, time.Hour)
+			},
+			logged: func(entries []observer.LoggedEntry) int {
+				var total int
+				for _, entry := range entries {
+					total += int(entry.ContextMap()[KeyMessageCount].(uint64))
+				}
+				return total
+			},
+		}, {
+			name: "top n",
+			new: func(ob Observer) (closingObserver, error) {
+				return NewTopNObserver(ob, 1, 10, time.Hour)
+			},
+			logged: func(entries []observer.LoggedEntry) int {
+				var total int
+				for _, entry := range entries {
+					total += int(entry.ContextMap()[KeyMessageCount].(uint64))
+				}
+				return total
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zap.InfoLevel)
+			var lock sync.Mutex
+			var reported []error
+			c, err := tt.new(Observer{
+				Logger: zap.New(core),
+				Fields: []FieldOpt{LogSource()},
+				OnError: func(err error) {
+					lock.Lock()
+					defer lock.Unlock()
+					reported = append(reported, err)
+				},
+			})
+			require.NoError(t, err)
+
+			// Nothing is lost when Close follows a burst right away.
+			for i := 0; i < burst; i++ {
+				c.ObserveWRP(context.Background(), wrp.Message{
+					Source:    strconv.Itoa(i),
+					SessionID: "session",
+				})
+			}
+			require.NoError(t, c.Close(context.Background()))
+			assert.Equal(t, burst, tt.logged(recorded.AllUntimed()))
+
+			// Once closed, messages are not logged but reported, and closing
+			// again does nothing.
+			c.ObserveWRP(context.Background(), wrp.Message{Source: "late"})
+			require.NoError(t, c.Close(context.Background()))
+			assert.Equal(t, burst, tt.logged(recorded.AllUntimed()))
+			require.Len(t, reported, 1)
+			assert.ErrorIs(t, reported[0], ErrClosed)
+		})
+	}
+}
+
+func TestCloser_Concurrent(t *testing.T) {
+	const goroutines, messages = 8, 100
+
+	tests := []struct {
+		name string
+		new  func(ob Observer) (closingObserver, error)
+	}{
+		{
+			name: "async",
+			new: func(ob Observer) (closingObserver, error) {
+				return NewAsyncObserver(ob, goroutines*messages)
+			},
+		}, {
+			name: "delta",
+			new: func(ob Observer) (closingObserver, error) {
+				return NewDeltaObserver(ob, 10)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zap.InfoLevel)
+			var lock sync.Mutex
+			var dropped int
+			c, err := tt.new(Observer{
+				Logger: zap.New(core),
+				Fields: []FieldOpt{LogSource()},
+				OnError: func(error) {
+					lock.Lock()
+					defer lock.Unlock()
+					dropped++
+				},
+			})
+			require.NoError(t, err)
+
+			// Closing while messages are observed loses none silently: each
+			// is either logged or reported.
+			var wg sync.WaitGroup
+			for i := 0; i < goroutines; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < messages; j++ {
+						c.ObserveWRP(context.Background(), wrp.Message{SessionID: strconv.Itoa(j % 20)})
+					}
+				}()
+			}
+			require.NoError(t, c.Close(context.Background()))
+			wg.Wait()
+
+			lock.Lock()
+			defer lock.Unlock()
+			assert.Equal(t, goroutines*messages, len(recorded.AllUntimed())+dropped)
+		})
+	}
+}
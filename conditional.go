@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+// LogIf wraps a FieldOpt so the wrapped field is only included for the
+// messages the predicate returns true for.  The wrapped FieldOpt is not
+// evaluated otherwise, and the returned FieldOpt produces zap.Skip(), which
+// the Observer leaves out of the entry.  A nil predicate or FieldOpt never
+// contributes a field.
+//
+//	LogIf(func(msg wrp.Message) bool { return msg.Type == wrp.SimpleEventMessageType },
+//		LogEventName())
+func LogIf(pred func(wrp.Message) bool, opt FieldOpt) FieldOpt {
+	return Named(opt.Name(), opt.Key(), func(msg wrp.Message) zap.Field {
+		if pred == nil || opt == nil || !pred(msg) {
+			return zap.Skip()
+		}
+		return opt(msg)
+	})
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogIf(t *testing.T) {
+	isEvent := func(msg wrp.Message) bool {
+		return msg.Type == wrp.SimpleEventMessageType
+	}
+
+	event := wrp.Message{
+		Type:        wrp.SimpleEventMessageType,
+		Source:      "mac:112233445566",
+		Destination: "event:device-status/mac:112233445566/online",
+	}
+	request := wrp.Message{
+		Type:        wrp.SimpleRequestResponseMessageType,
+		Source:      "dns:talaria.example.net",
+		Destination: "mac:112233445566/config",
+	}
+
+	tests := []struct {
+		name     string
+		pred     func(wrp.Message) bool
+		opt      FieldOpt
+		msg      wrp.Message
+		expected map[string]any
+	}{
+		{
+			name:     "true",
+			pred:     isEvent,
+			opt:      LogEventName(),
+			msg:      event,
+			expected: map[string]any{KeyEventName: "device-status"},
+		}, {
+			name:     "false",
+			pred:     isEvent,
+			opt:      LogEventName(),
+			msg:      request,
+			expected: map[string]any{},
+		}, {
+			name: "appender",
+			pred: isEvent,
+			opt:  LogLocatorSchemes(),
+			msg:  event,
+			expected: map[string]any{
+				KeySourceScheme:      "mac",
+				KeyDestinationScheme: "event",
+			},
+		}, {
+			name:     "appender false",
+			pred:     isEvent,
+			opt:      LogLocatorSchemes(),
+			msg:      request,
+			expected: map[string]any{},
+		}, {
+			name:     "nil predicate",
+			opt:      LogEventName(),
+			msg:      event,
+			expected: map[string]any{},
+		}, {
+			name:     "nil FieldOpt",
+			pred:     isEvent,
+			msg:      event,
+			expected: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, fieldMap(LogIf(tt.pred, tt.opt), tt.msg))
+
+			core, recorded := observer.New(zap.InfoLevel)
+			ob, err := NewObserver(zap.New(core),
+				WithLevel(zap.InfoLevel),
+				WithFields(LogSource(), LogIf(tt.pred, tt.opt)),
+			)
+			require.NoError(t, err)
+			ob.ObserveWRP(context.Background(), tt.msg)
+
+			entries := recorded.AllUntimed()
+			require.Len(t, entries, 1)
+			assert.Len(t, entries[0].Context, len(tt.expected)+1)
+
+			expected := map[string]any{KeySource: tt.msg.Source}
+			for k, v := range tt.expected {
+				expected[k] = v
+			}
+			assert.Equal(t, expected, entries[0].ContextMap())
+		})
+	}
+}
+
+func TestLogIf_NotEvaluated(t *testing.T) {
+	var calls int
+	opt := LogIf(
+		func(wrp.Message) bool { return false },
+		func(wrp.Message) zap.Field {
+			calls++
+			return LogSource()(wrp.Message{})
+		},
+	)
+
+	assert.Equal(t, zap.Skip(), opt(wrp.Message{}))
+	assert.Empty(t, opt.AppendFields(wrp.Message{}, nil))
+	assert.Zero(t, calls)
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Convertible is a value that can be converted to a wrp.Message, like the
+// wrappers applications put around the typed wrp structs.
+type Convertible interface {
+	// To fills in the message from the value.
+	To(*wrp.Message) error
+}
+
+// ObserveConvertible converts the value to a wrp.Message and logs it like
+// ObserveWRP.  When the conversion fails, an Error level entry with the
+// Message text is logged instead, with the value's type as convertible_type
+// and the conversion error as error.  A nil value is ignored.
+func (ob Observer) ObserveConvertible(_ context.Context, m Convertible) {
+	if m == nil {
+		return
+	}
+
+	var msg wrp.Message
+	if err := m.To(&msg); err != nil {
+		if ob.Logger == nil {
+			return
+		}
+
+		level := max(ob.baseLevel(), zapcore.ErrorLevel)
+		if ce := ob.Logger.Check(level, ob.Message); ce != nil {
+			ce.Write(ob.withSeq(
+				zap.String(fConvertibleType, fmt.Sprintf("%T", m)),
+				zap.Error(err),
+			)...)
+			ob.emitted(level)
+		}
+		return
+	}
+
+	ob.observe(&msg, nil)
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var errConvert = errors.New("conversion failed")
+
+// event wraps a typed wrp struct the way applications do.
+type event struct {
+	wrp.SimpleEvent
+}
+
+func (e *event) To(msg *wrp.Message) error {
+	*msg = wrp.Message{
+		Type:        wrp.SimpleEventMessageType,
+		Source:      e.Source,
+		Destination: e.Destination,
+		Payload:     e.Payload,
+	}
+	return nil
+}
+
+// broken always fails to convert.
+type broken struct{}
+
+func (broken) To(*wrp.Message) error {
+	return errConvert
+}
+
+func TestObserver_ObserveConvertible(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Convertible
+		level    zapcore.Level
+		expected []zap.Field
+	}{
+		{
+			name: "typed struct",
+			m: &event{wrp.SimpleEvent{
+				Source:      "mac:112233445566",
+				Destination: "event:device-status",
+			}},
+			level: zap.InfoLevel,
+			expected: []zap.Field{
+				zap.String(fMsgType, wrp.SimpleEventMessageType.String()),
+				zap.String(fDestination, "event:device-status"),
+			},
+		}, {
+			name:  "conversion error",
+			m:     broken{},
+			level: zap.ErrorLevel,
+			expected: []zap.Field{
+				zap.String(fConvertibleType, "wrpzap.broken"),
+				zap.Error(errConvert),
+			},
+		}, {
+			name: "nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zap.DebugLevel)
+			ob := Observer{
+				Logger:  zap.New(core),
+				Level:   AtLevel(zap.InfoLevel),
+				Message: "wrp received",
+				Fields:  []FieldOpt{LogMessageTypeAsString(), LogDestination()},
+			}
+
+			ob.ObserveConvertible(context.Background(), tt.m)
+
+			entries := recorded.AllUntimed()
+			if tt.expected == nil {
+				assert.Empty(t, entries)
+				return
+			}
+
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.level, entries[0].Level)
+			assert.Equal(t, "wrp received", entries[0].Message)
+			assert.Equal(t, tt.expected, entries[0].Context)
+		})
+	}
+}
+
+func TestObserver_ObserveConvertible_NoLogger(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Observer{}.ObserveConvertible(context.Background(), broken{})
+	})
+}
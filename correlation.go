@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// TransactionRecord is a transaction a device sent, as remembered by a
+// CorrelationIndex.
+type TransactionRecord struct {
+	// TransactionUUID is the transaction UUID of the message.
+	TransactionUUID string
+
+	// Observed is when the Observer observed the message.
+	Observed time.Time
+}
+
+// CorrelationIndex remembers the last transactions each device sent, to
+// answer "what did this device last send?" during triage.  Devices are kept
+// in a least recently used cache of bounded size, and each device keeps a
+// bounded number of transactions, so the memory used is bounded too.
+//
+// Devices are identified by the canonical form of their source, as
+// NormalizeLocator produces it without the service: mac:112233445566 for
+// MAC:11-22-33-44-55-66/config.  Messages from sources that are not devices,
+// or without a transaction UUID, are not indexed.
+//
+// A CorrelationIndex is safe for concurrent use and can be shared by several
+// Observers.
+type CorrelationIndex struct {
+	maxDevices      int
+	maxTransactions int
+
+	lock    sync.Mutex
+	devices map[string]*list.Element
+	order   *list.List
+}
+
+// correlatedDevice is the transactions of a device, oldest first.
+type correlatedDevice struct {
+	id           string
+	transactions []TransactionRecord
+}
+
+// NewCorrelationIndex creates a CorrelationIndex remembering the last
+// maxTransactions transactions of at most maxDevices devices.  Both must be
+// positive.
+func NewCorrelationIndex(maxDevices, maxTransactions int) (*CorrelationIndex, error) {
+	if maxDevices < 1 {
+		return nil, fmt.Errorf("%w: maxDevices must be positive", ErrInvalidInput)
+	}
+	if maxTransactions < 1 {
+		return nil, fmt.Errorf("%w: maxTransactions must be positive", ErrInvalidInput)
+	}
+
+	return &CorrelationIndex{
+		maxDevices:      maxDevices,
+		maxTransactions: maxTransactions,
+		devices:         make(map[string]*list.Element, maxDevices),
+		order:           list.New(),
+	}, nil
+}
+
+// record remembers the message's transaction for its source device.
+func (c *CorrelationIndex) record(msg *wrp.Message, when time.Time) {
+	if msg.TransactionUUID == "" {
+		return
+	}
+
+	id, ok := canonicalDeviceID(msg.Source)
+	if !ok {
+		return
+	}
+
+	rec := TransactionRecord{
+		TransactionUUID: msg.TransactionUUID,
+		Observed:        when,
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if e, found := c.devices[id]; found {
+		c.order.MoveToFront(e)
+		d := e.Value.(*correlatedDevice)
+		if len(d.transactions) == c.maxTransactions {
+			copy(d.transactions, d.transactions[1:])
+			d.transactions = d.transactions[:len(d.transactions)-1]
+		}
+		d.transactions = append(d.transactions, rec)
+		return
+	}
+
+	if c.order.Len() == c.maxDevices {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.devices, oldest.Value.(*correlatedDevice).id)
+	}
+
+	d := &correlatedDevice{
+		id:           id,
+		transactions: make([]TransactionRecord, 0, c.maxTransactions),
+	}
+	d.transactions = append(d.transactions, rec)
+	c.devices[id] = c.order.PushFront(d)
+}
+
+// LastTransactions returns the last transactions the device sent, the most
+// recent first.  The device ID is a locator, canonicalized the same way the
+// sources are, so mac:AA-BB-CC-DD-EE-FF finds mac:aabbccddeeff.  Nil is
+// returned when the device is not in the index.  Looking a device up doesn't
+// keep it from being evicted.
+func (c *CorrelationIndex) LastTransactions(deviceID string) []TransactionRecord {
+	id, ok := canonicalDeviceID(deviceID)
+	if !ok {
+		return nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, found := c.devices[id]
+	if !found {
+		return nil
+	}
+
+	transactions := e.Value.(*correlatedDevice).transactions
+	last := make([]TransactionRecord, len(transactions))
+	for i, rec := range transactions {
+		last[len(last)-1-i] = rec
+	}
+	return last
+}
+
+// Len returns the number of devices in the index.
+func (c *CorrelationIndex) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.order.Len()
+}
+
+// canonicalDeviceID returns the device ID of the locator, in the form
+// NormalizeLocator uses.  ok is false when the locator is not a device.
+func canonicalDeviceID(locator string) (id string, ok bool) {
+	l, err := wrp.ParseLocator(locator)
+	if err != nil || l.Scheme == wrp.SchemeSelf || !l.HasDeviceID() {
+		return "", false
+	}
+
+	if l.Scheme == wrp.SchemeMAC {
+		return string(l.ID), true
+	}
+	return l.Scheme + ":" + strings.ToLower(l.Authority), true
+}
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCorrelationIndex(t *testing.T) {
+	start := time.UnixMilli(1000)
+	clock := newFakeClock(start)
+
+	index, err := NewCorrelationIndex(10, 3)
+	require.NoError(t, err)
+
+	core, recorded := observer.New(zap.InfoLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithFields(LogSource()),
+		WithClock(clock),
+		WithFilter(func(wrp.Message) bool { return false }),
+		WithCorrelationIndex(index),
+	)
+	require.NoError(t, err)
+
+	for i := 1; i <= 5; i++ {
+		ob.ObserveWRP(context.Background(), wrp.Message{
+			Source:          "MAC:11-22-33-44-55-66/config",
+			TransactionUUID: fmt.Sprintf("uuid-%d", i),
+		})
+		clock.Add(time.Second)
+	}
+
+	// Messages that are not indexed.
+	for _, msg := range []wrp.Message{
+		{Source: "mac:112233445566"},
+		{Source: "dns:talaria.example.net", TransactionUUID: "cloud"},
+		{Source: "event:device-status", TransactionUUID: "event"},
+		{Source: "self:", TransactionUUID: "self"},
+		{Source: "not a locator", TransactionUUID: "invalid"},
+	} {
+		ob.ObserveWRP(context.Background(), msg)
+	}
+
+	// The messages are indexed even though none was logged.
+	assert.Empty(t, recorded.All())
+	assert.Equal(t, 1, index.Len())
+
+	expected := []TransactionRecord{
+		{TransactionUUID: "uuid-5", Observed: start.Add(4 * time.Second)},
+		{TransactionUUID: "uuid-4", Observed: start.Add(3 * time.Second)},
+		{TransactionUUID: "uuid-3", Observed: start.Add(2 * time.Second)},
+	}
+	for _, id := range []string{"mac:112233445566", "MAC:11:22:33:44:55:66", "mac:112233445566/iot"} {
+		assert.Equal(t, expected, index.LastTransactions(id), id)
+	}
+
+	assert.Nil(t, index.LastTransactions("mac:665544332211"))
+	assert.Nil(t, index.LastTransactions("dns:talaria.example.net"))
+	assert.Nil(t, index.LastTransactions("not a locator"))
+
+	// The result is a copy.
+	index.LastTransactions("mac:112233445566")[0].TransactionUUID = "changed"
+	assert.Equal(t, expected, index.LastTransactions("mac:112233445566"))
+}
+
+func TestCorrelationIndex_CanonicalID(t *testing.T) {
+	tests := []struct {
+		source string
+		query  string
+	}{
+		{source: "mac:AABBCCDDEEFF", query: "mac:aa-bb-cc-dd-ee-ff"},
+		{source: "uuid:ABC-123/config", query: "UUID:abc-123"},
+		{source: "serial:XYZ", query: "serial:xyz/service"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			index, err := NewCorrelationIndex(1, 1)
+			require.NoError(t, err)
+
+			index.record(&wrp.Message{Source: tt.source, TransactionUUID: "uuid"}, time.Time{})
+			assert.Equal(t, []TransactionRecord{{TransactionUUID: "uuid"}}, index.LastTransactions(tt.query))
+		})
+	}
+}
+
+func TestCorrelationIndex_Eviction(t *testing.T) {
+	index, err := NewCorrelationIndex(2, 1)
+	require.NoError(t, err)
+
+	send := func(device, uuid string) {
+		index.record(&wrp.Message{Source: device, TransactionUUID: uuid}, time.Time{})
+	}
+	last := func(device string) string {
+		if recs := index.LastTransactions(device); len(recs) > 0 {
+			return recs[0].TransactionUUID
+		}
+		return ""
+	}
+
+	a, b, c := "mac:aaaaaaaaaaaa", "mac:bbbbbbbbbbbb", "mac:cccccccccccc"
+
+	send(a, "a1")
+	send(b, "b1")
+	send(a, "a2") // a is the most recently used again
+	send(c, "c1") // b is evicted
+
+	assert.Equal(t, 2, index.Len())
+	assert.Equal(t, "a2", last(a))
+	assert.Empty(t, last(b))
+	assert.Equal(t, "c1", last(c))
+
+	// Looking a device up doesn't make it recently used.
+	assert.Equal(t, "a2", last(a))
+	send(b, "b2") // a is evicted
+
+	assert.Empty(t, last(a))
+	assert.Equal(t, "b2", last(b))
+	assert.Equal(t, "c1", last(c))
+}
+
+func TestCorrelationIndex_Concurrent(t *testing.T) {
+	const (
+		devices = 50
+		writers = 8
+		sends   = 500
+	)
+
+	index, err := NewCorrelationIndex(devices/2, 4)
+	require.NoError(t, err)
+
+	ob, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithFields(LogSource()), WithCorrelationIndex(index))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < sends; i++ {
+				ob.ObserveWRP(context.Background(), wrp.Message{
+					Source:          fmt.Sprintf("mac:%012x", (w*sends+i)%devices),
+					TransactionUUID: fmt.Sprintf("uuid-%d-%d", w, i),
+				})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < sends; i++ {
+				recs := index.LastTransactions(fmt.Sprintf("mac:%012x", i%devices))
+				assert.LessOrEqual(t, len(recs), 4)
+				assert.LessOrEqual(t, index.Len(), devices/2)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, devices/2, index.Len())
+}
+
+func TestNewCorrelationIndex_Invalid(t *testing.T) {
+	_, err := NewCorrelationIndex(0, 1)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = NewCorrelationIndex(1, 0)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = NewObserver(zap.NewNop(), WithCorrelationIndex(nil))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestWithCorrelationIndex_String(t *testing.T) {
+	index, err := NewCorrelationIndex(1, 1)
+	require.NoError(t, err)
+
+	ob, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithFields(LogSource()), WithCorrelationIndex(index))
+	require.NoError(t, err)
+	assert.Contains(t, ob.String(), " correlation_index=true")
+}
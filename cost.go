@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"time"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// observeCost is the marker of the placeholder field LogObserveCost produces.
+type observeCost struct{}
+
+// LogObserveCost logs the time spent evaluating the FieldOpts that follow it
+// for the message, in microseconds, as observe_cost_us.  It shows what the
+// expensive fields, like hashing or JSON validation, cost per message.
+//
+// Only the FieldOpts after it are measured, so it should be the first
+// FieldOpt; measuring starts when the Observer reaches it, so Observers
+// without it pay nothing.  Fields that are computed when the entry is
+// encoded, like LogPayloadIsValidJSON, are not included.  The time comes
+// from the Observer's Clock.
+//
+// Used without an Observer, the FieldOpt logs nothing.
+func LogObserveCost() FieldOpt {
+	field := zap.Field{Type: zapcore.SkipType, Interface: observeCost{}}
+
+	return Named("observe_cost", KeyObserveCost, func(wrp.Message) zap.Field {
+		return field
+	})
+}
+
+// isObserveCost reports whether the field is the LogObserveCost placeholder.
+func isObserveCost(field zap.Field) bool {
+	if field.Type != zapcore.SkipType {
+		return false
+	}
+
+	_, ok := field.Interface.(observeCost)
+	return ok
+}
+
+// observeCostField is the field that replaces the LogObserveCost
+// placeholder.
+func observeCostField(d time.Duration) zap.Field {
+	return zap.Float64(fObserveCost, float64(d)/float64(time.Microsecond))
+}
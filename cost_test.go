@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogObserveCost(t *testing.T) {
+	clock := newFakeClock(time.UnixMilli(1000))
+
+	// slow is a FieldOpt that takes the time it is given.
+	slow := func(d time.Duration) FieldOpt {
+		return func(wrp.Message) zap.Field {
+			clock.Add(d)
+			return zap.Skip()
+		}
+	}
+
+	tests := []struct {
+		name      string
+		coreLevel zapcore.Level
+		fields    []FieldOpt
+		expected  any
+	}{
+		{
+			name:     "first",
+			fields:   []FieldOpt{LogObserveCost(), slow(250 * time.Microsecond), LogSource()},
+			expected: 250.0,
+		}, {
+			name:     "fractional",
+			fields:   []FieldOpt{LogObserveCost(), slow(1500 * time.Nanosecond)},
+			expected: 1.5,
+		}, {
+			name:     "only later fields are measured",
+			fields:   []FieldOpt{slow(time.Millisecond), LogObserveCost(), slow(40 * time.Microsecond)},
+			expected: 40.0,
+		}, {
+			name:     "repeated",
+			fields:   []FieldOpt{LogObserveCost(), slow(10 * time.Microsecond), LogObserveCost()},
+			expected: 10.0,
+		}, {
+			name:     "absent",
+			fields:   []FieldOpt{slow(time.Millisecond), LogSource()},
+			expected: nil,
+		}, {
+			name:      "leveled and enabled",
+			coreLevel: zap.DebugLevel,
+			fields:    []FieldOpt{FieldOptAtLevel(zap.DebugLevel, LogObserveCost()), slow(time.Microsecond)},
+			expected:  1.0,
+		}, {
+			name:      "leveled and disabled",
+			coreLevel: zap.InfoLevel,
+			fields:    []FieldOpt{FieldOptAtLevel(zap.DebugLevel, LogObserveCost()), slow(time.Microsecond)},
+			expected:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(tt.coreLevel)
+			ob := Observer{
+				Logger: zap.New(core),
+				Level:  AtLevel(zap.InfoLevel),
+				Fields: tt.fields,
+				Clock:  clock,
+			}
+
+			ob.ObserveWRP(context.Background(), wrp.Message{Source: "mac:112233445566"})
+
+			entries := recorded.All()
+			require.Len(t, entries, 1)
+			logged := entries[0].ContextMap()
+			if tt.expected == nil {
+				assert.NotContains(t, logged, KeyObserveCost)
+				return
+			}
+			assert.Equal(t, tt.expected, logged[KeyObserveCost])
+		})
+	}
+}
+
+func TestLogObserveCost_WithoutObserver(t *testing.T) {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range LogObserveCost().AppendFields(wrp.Message{}, nil) {
+		f.AddTo(enc)
+	}
+	assert.Empty(t, enc.Fields)
+}
+
+func BenchmarkLogObserveCost(b *testing.B) {
+	msg := wrp.Message{
+		Type:        wrp.SimpleEventMessageType,
+		Source:      "mac:112233445566",
+		Destination: "event:device-status/mac:112233445566/online",
+	}
+
+	benchmarks := []struct {
+		name   string
+		fields []FieldOpt
+	}{
+		{name: "absent", fields: DefaultFields()},
+		{name: "present", fields: append([]FieldOpt{LogObserveCost()}, DefaultFields()...)},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			core := zapcore.NewCore(
+				zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+				zapcore.AddSync(io.Discard),
+				zap.InfoLevel,
+			)
+			ob := Observer{
+				Logger:  zap.New(core),
+				Level:   AtLevel(zap.InfoLevel),
+				Message: "wrp",
+				Fields:  bm.fields,
+			}
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ob.ObserveWRP(context.Background(), msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DeltaObserver logs only the fields that changed since the previous message
+// logged for the same session.  Devices that send near identical messages
+// every few seconds produce much smaller entries this way.
+//
+// The routing fields are always logged.  The first message seen for a session
+// logs every field, as does the first message after a session has been
+// evicted.  Sessions are tracked in a least recently used cache of bounded
+// size, so when more sessions are active than the cache holds the least
+// recently logged session is forgotten.  Messages without a SessionID are not
+// tracked and always log every field.
+//
+// Values are compared with zap.Field.Equals, so pointer fields are compared
+// by the value they point to and slices and maps by their contents.  Fields
+// that add several keys, like LogLocatorSchemes, are compared by the keys and
+// values they add.
+type DeltaObserver struct {
+	ob       Observer
+	routing  []FieldOpt
+	max      int
+	lock     sync.Mutex
+	sessions map[string]*list.Element
+	order    *list.List
+	closed   bool
+}
+
+// deltaSession is the last set of fields logged for a session.
+type deltaSession struct {
+	id     string
+	fields map[string]zap.Field
+}
+
+// NewDeltaObserver creates a DeltaObserver that logs using the Observer's
+// Logger, Level, message and Fields.  The Observer's DebugFields are not
+// used.  maxSessions is the number of sessions remembered and must be
+// positive.  If no routing FieldOpts are provided the message type, source,
+// destination, transaction UUID and session ID are used.
+func NewDeltaObserver(ob Observer, maxSessions int, routing ...FieldOpt) (*DeltaObserver, error) {
+	if ob.Logger == nil {
+		return nil, fmt.Errorf("%w: logger is nil", ErrInvalidInput)
+	}
+	if maxSessions < 1 {
+		return nil, fmt.Errorf("%w: maxSessions must be positive", ErrInvalidInput)
+	}
+
+	if len(routing) == 0 {
+		routing = []FieldOpt{
+			LogMessageType(),
+			LogSource(),
+			LogDestination(),
+			LogTransactionUUID(),
+			LogSessionID(),
+		}
+	}
+
+	return &DeltaObserver{
+		ob:       ob,
+		routing:  routing,
+		max:      maxSessions,
+		sessions: make(map[string]*list.Element, maxSessions),
+		order:    list.New(),
+	}, nil
+}
+
+// ObserveWRP logs the routing fields and the fields that changed since the
+// last message of the same session.  Messages observed after Close are
+// dropped and reported to the Observer's OnError as ErrClosed.
+func (d *DeltaObserver) ObserveWRP(_ context.Context, msg wrp.Message) {
+	if d.isClosed() {
+		d.ob.reportError(fmt.Errorf("%w: delta message dropped", ErrClosed))
+		return
+	}
+
+	d.ob.observed(&msg)
+
+	if d.ob.Filter != nil && !d.ob.Filter(msg) {
+		return
+	}
+
+	ce := d.ob.Logger.Check(d.ob.baseLevel(), d.ob.message(&msg))
+	if ce == nil {
+		return
+	}
+
+	// The remembered fields must not alias memory the pipeline may change.
+	msg = cloneMessage(msg)
+
+	fields := d.ob.fields(d.routing, &msg)
+	routed := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		routed[deltaField(field).Key] = struct{}{}
+	}
+
+	changing := d.ob.fields(d.ob.fieldOpts(&msg), &msg)
+	remembered := make([]zap.Field, len(changing))
+	current := make(map[string]zap.Field, len(changing))
+	for i, field := range changing {
+		remembered[i] = deltaField(field)
+		if _, ok := routed[remembered[i].Key]; !ok {
+			current[remembered[i].Key] = remembered[i]
+		}
+	}
+
+	previous := d.swap(msg.SessionID, current)
+	for i, field := range changing {
+		key := remembered[i].Key
+		if _, ok := routed[key]; ok {
+			continue
+		}
+		if prev, ok := previous[key]; ok && prev.Equals(remembered[i]) {
+			continue
+		}
+		fields = append(fields, field)
+	}
+
+	ce.Write(d.ob.withSeq(fields...)...)
+	d.ob.emitted(d.ob.baseLevel())
+}
+
+// deltaField returns the field as it is remembered.  Inline fields have no
+// key of their own and their marshalers may not be comparable, so they are
+// remembered by the keys and values they add.
+func deltaField(field zap.Field) zap.Field {
+	if field.Type != zapcore.InlineMarshalerType {
+		return field
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	field.AddTo(enc)
+	keys := slices.Sorted(maps.Keys(enc.Fields))
+
+	return zap.Reflect(strings.Join(keys, ","), enc.Fields)
+}
+
+// swap stores the fields for the session and returns the previously stored
+// fields, if any.
+func (d *DeltaObserver) swap(id string, fields map[string]zap.Field) map[string]zap.Field {
+	if id == "" {
+		return nil
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.closed {
+		return nil
+	}
+
+	if elem, ok := d.sessions[id]; ok {
+		d.order.MoveToFront(elem)
+		s := elem.Value.(*deltaSession)
+		previous := s.fields
+		s.fields = fields
+		return previous
+	}
+
+	if d.order.Len() >= d.max {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.sessions, oldest.Value.(*deltaSession).id)
+	}
+
+	d.sessions[id] = d.order.PushFront(&deltaSession{id: id, fields: fields})
+	return nil
+}
+
+// Close forgets the sessions.  Messages observed after Close are dropped.
+// Every entry is written as its message is observed, so there is nothing to
+// flush and the context is not used.
+func (d *DeltaObserver) Close(context.Context) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.closed = true
+	clear(d.sessions)
+	d.order.Init()
+	return nil
+}
+
+func (d *DeltaObserver) isClosed() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.closed
+}
@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func keys(fields []zap.Field) []string {
+	list := make([]string, 0, len(fields))
+	for _, field := range fields {
+		list = append(list, field.Key)
+	}
+	return list
+}
+
+func TestNewDeltaObserver(t *testing.T) {
+	_, err := NewDeltaObserver(Observer{}, 10)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = NewDeltaObserver(Observer{Logger: zap.NewNop()}, 0)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	d, err := NewDeltaObserver(Observer{Logger: zap.NewNop()}, 1)
+	require.NoError(t, err)
+	assert.Len(t, d.routing, 5)
+}
+
+func TestDeltaObserver_ObserveWRP(t *testing.T) {
+	one, two := int64(1), int64(2)
+	alsoOne := int64(1)
+
+	msgs := []struct {
+		msg      wrp.Message
+		expected []string
+	}{
+		{
+			// The first message of a session logs everything.
+			msg: wrp.Message{
+				SessionID:  "a",
+				Status:     &one,
+				Headers:    []string{"h1"},
+				Metadata:   map[string]string{"k": "v"},
+				Payload:    []byte("p1"),
+				PartnerIDs: []string{"p"},
+			},
+			expected: []string{fSessionID, fStatus, fHeaders, fMetadata, fPayload},
+		}, {
+			// Pointers are compared by value and slices by content.
+			msg: wrp.Message{
+				SessionID:  "a",
+				Status:     &alsoOne,
+				Headers:    []string{"h1"},
+				Metadata:   map[string]string{"k": "v"},
+				Payload:    []byte("p1"),
+				PartnerIDs: []string{"p"},
+			},
+			expected: []string{fSessionID},
+		}, {
+			msg: wrp.Message{
+				SessionID:  "a",
+				Status:     &two,
+				Headers:    []string{"h1", "h2"},
+				Metadata:   map[string]string{"k": "v"},
+				Payload:    []byte("p2"),
+				PartnerIDs: []string{"p"},
+			},
+			expected: []string{fSessionID, fStatus, fHeaders, fPayload},
+		}, {
+			// A nil pointer is a change too.
+			msg: wrp.Message{
+				SessionID:  "a",
+				Headers:    []string{"h1", "h2"},
+				Metadata:   map[string]string{"k": "v2"},
+				Payload:    []byte("p2"),
+				PartnerIDs: []string{"p"},
+			},
+			expected: []string{fSessionID, fStatus, fMetadata},
+		}, {
+			// Other sessions are independent.
+			msg: wrp.Message{
+				SessionID: "b",
+			},
+			expected: []string{fSessionID, fStatus, fHeaders, fMetadata, fPayload},
+		}, {
+			// Messages without a session always log everything.
+			msg:      wrp.Message{},
+			expected: []string{fSessionID, fStatus, fHeaders, fMetadata, fPayload},
+		}, {
+			msg:      wrp.Message{},
+			expected: []string{fSessionID, fStatus, fHeaders, fMetadata, fPayload},
+		},
+	}
+
+	core, recorded := observer.New(zap.InfoLevel)
+	d, err := NewDeltaObserver(Observer{
+		Logger:  zap.New(core),
+		Level:   AtLevel(zap.InfoLevel),
+		Message: "test message",
+		Fields: []FieldOpt{
+			LogSessionID(),
+			LogStatus(),
+			LogHeaders(),
+			LogMetadata(),
+			LogPayload(),
+		},
+	}, 10, LogSessionID())
+	require.NoError(t, err)
+
+	for _, m := range msgs {
+		d.ObserveWRP(context.Background(), m.msg)
+	}
+
+	entries := recorded.All()
+	require.Len(t, entries, len(msgs))
+	for i, entry := range entries {
+		assert.Equal(t, msgs[i].expected, keys(entry.Context), "entry %d", i)
+	}
+}
+
+func TestDeltaObserver_PipelineMutation(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	d, err := NewDeltaObserver(Observer{
+		Logger: zap.New(core),
+		Fields: []FieldOpt{LogPayload()},
+	}, 10, LogSessionID())
+	require.NoError(t, err)
+
+	msg := wrp.Message{SessionID: "a", Payload: []byte("before")}
+	d.ObserveWRP(context.Background(), msg)
+
+	// Reusing the buffer must still be seen as a change.
+	copy(msg.Payload, "after!")
+	d.ObserveWRP(context.Background(), msg)
+
+	entries := recorded.All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, []string{fSessionID, fPayload}, keys(entries[1].Context))
+}
+
+func TestDeltaObserver_InlineFields(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	d, err := NewDeltaObserver(Observer{
+		Logger: zap.New(core),
+		Fields: []FieldOpt{
+			LogLocatorSchemes(),
+			LogUnknownMetadataKeys(),
+		},
+	}, 10, LogSessionID())
+	require.NoError(t, err)
+
+	msgs := []wrp.Message{
+		{SessionID: "a", Source: "mac:112233445566", Metadata: map[string]string{"k": "v"}},
+		{SessionID: "a", Source: "mac:112233445566", Metadata: map[string]string{"k": "v"}},
+		{SessionID: "a", Source: "dns:talaria", Metadata: map[string]string{"k": "v"}},
+		{SessionID: "a", Source: "dns:talaria", Metadata: map[string]string{"k": "v", "j": "w"}},
+	}
+	for _, msg := range msgs {
+		d.ObserveWRP(context.Background(), msg)
+	}
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, len(msgs))
+
+	expected := []int{4, 1, 2, 2}
+	for i, entry := range entries {
+		assert.Len(t, entry.Context, expected[i], "entry %d", i)
+	}
+	assert.Contains(t, entries[2].ContextMap(), KeySourceScheme)
+	assert.Contains(t, entries[3].ContextMap(), KeyUnknownMetadataKeyCount)
+}
+
+func TestDeltaObserver_Eviction(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	d, err := NewDeltaObserver(Observer{
+		Logger: zap.New(core),
+		Fields: []FieldOpt{LogSource()},
+	}, 2, LogSessionID())
+	require.NoError(t, err)
+
+	observe := func(session string) []string {
+		d.ObserveWRP(context.Background(), wrp.Message{SessionID: session, Source: "src"})
+		entries := recorded.TakeAll()
+		require.Len(t, entries, 1)
+		return keys(entries[0].Context)
+	}
+
+	full := []string{fSessionID, fSource}
+	routing := []string{fSessionID}
+
+	assert.Equal(t, full, observe("a"))
+	assert.Equal(t, full, observe("b"))
+	assert.Equal(t, routing, observe("a"))
+
+	// b is now the least recently used and is evicted by c.
+	assert.Equal(t, full, observe("c"))
+	assert.Equal(t, routing, observe("a"))
+	assert.Equal(t, full, observe("b"))
+	assert.Len(t, d.sessions, 2)
+	assert.Equal(t, 2, d.order.Len())
+}
+
+func TestDeltaObserver_Disabled(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	d, err := NewDeltaObserver(Observer{
+		Logger: zap.New(core),
+		Level:  AtLevel(zap.DebugLevel),
+	}, 2)
+	require.NoError(t, err)
+
+	d.ObserveWRP(context.Background(), wrp.Message{SessionID: "a"})
+	assert.Empty(t, recorded.All())
+	assert.Empty(t, d.sessions)
+}
+
+func TestDeltaObserver_Concurrent(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	d, err := NewDeltaObserver(Observer{
+		Logger: zap.New(core),
+		Fields: []FieldOpt{LogSource()},
+	}, 4)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for _, session := range []string{"a", "b", "c", "d", "e", "f"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				d.ObserveWRP(context.Background(), wrp.Message{SessionID: session})
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, recorded.All(), 600)
+	assert.Len(t, d.sessions, 4)
+}
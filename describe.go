@@ -0,0 +1,317 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap/zapcore"
+)
+
+// description is the summary of an Observer's configuration.  Only the names
+// of the FieldOpts are included, so nothing they were configured with, like
+// salts or lists of redacted values, can leak.
+type description struct {
+	Level       string              `json:"level"`
+	AtomicLevel bool                `json:"atomic_level,omitempty"`
+	Message     string              `json:"message,omitempty"`
+	Messages    map[string]string   `json:"messages,omitempty"`
+	MessageFunc bool                `json:"message_func,omitempty"`
+	Filter      bool                `json:"filter,omitempty"`
+	ByType      bool                `json:"sample_by_type,omitempty"`
+	Fields      []string            `json:"fields"`
+	Defaults    bool                `json:"use_default_fields,omitempty"`
+	DebugFields []string            `json:"debug_fields,omitempty"`
+	Escalations []string            `json:"escalations,omitempty"`
+	Canonical   bool                `json:"canonicalize_locators,omitempty"`
+	NormalizeMD bool                `json:"normalize_metadata_keys,omitempty"`
+	Stack       bool                `json:"stack_on_error,omitempty"`
+	KeepLast    bool                `json:"keep_last,omitempty"`
+	BatchCap    int                 `json:"batch_cap,omitempty"`
+	Clock       bool                `json:"clock,omitempty"`
+	OnError     bool                `json:"on_error,omitempty"`
+	EncodedSize bool                `json:"encoded_size,omitempty"`
+	Tee         bool                `json:"tee,omitempty"`
+	Metrics     bool                `json:"metrics,omitempty"`
+	Seq         bool                `json:"sequence_numbers,omitempty"`
+	TypeCounts  bool                `json:"type_counters,omitempty"`
+	FieldErrors bool                `json:"field_errors,omitempty"`
+	Correlation bool                `json:"correlation_index,omitempty"`
+	EntryBudget int                 `json:"entry_budget,omitempty"`
+	Priorities  map[string]Priority `json:"field_priorities,omitempty"`
+	Partners    map[string][]string `json:"partner_overrides,omitempty"`
+}
+
+func (ob Observer) describe() description {
+	return description{
+		Level:       levelName(ob.baseLevelPtr()),
+		AtomicLevel: ob.AtomicLevel != nil,
+		Message:     ob.Message,
+		Messages:    typeMessages(ob.Messages),
+		MessageFunc: ob.MessageFunc != nil,
+		Filter:      ob.Filter != nil,
+		ByType:      ob.SampleByType,
+		Fields:      fieldOptNames(ob.Fields),
+		Defaults:    ob.UseDefaultFields,
+		DebugFields: fieldOptNames(ob.DebugFields),
+		Escalations: escalationNames(ob.Escalations),
+		Canonical:   ob.CanonicalizeLocators,
+		NormalizeMD: ob.NormalizeMetadataKeys,
+		Stack:       ob.StackOnError,
+		KeepLast:    ob.KeepLast,
+		BatchCap:    ob.BatchCap,
+		Clock:       ob.Clock != nil,
+		OnError:     ob.OnError != nil,
+		EncodedSize: ob.LogEncodedSize,
+		Tee:         ob.Tee != nil,
+		Metrics:     ob.Metrics != nil,
+		Seq:         ob.SequenceNumbers,
+		TypeCounts:  ob.TypeCounters,
+		FieldErrors: ob.FieldErrors,
+		Correlation: ob.Correlation != nil,
+		EntryBudget: ob.EntryBudget,
+		Priorities:  ob.FieldPriorities,
+		Partners:    partnerOverrideNames(ob.PartnerOverrides),
+	}
+}
+
+// String describes the Observer's configuration.
+func (ob Observer) String() string {
+	d := ob.describe()
+
+	var b strings.Builder
+	b.WriteString("level=")
+	b.WriteString(d.Level)
+	if d.AtomicLevel {
+		b.WriteString(" atomic_level=true")
+	}
+	b.WriteString(" message=")
+	b.WriteString(strconv.Quote(d.Message))
+	if len(d.Messages) > 0 {
+		b.WriteString(" messages=[")
+		for i, name := range slices.Sorted(maps.Keys(d.Messages)) {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(name)
+			b.WriteString("=")
+			b.WriteString(strconv.Quote(d.Messages[name]))
+		}
+		b.WriteString("]")
+	}
+	if d.MessageFunc {
+		b.WriteString(" message_func=true")
+	}
+	if d.Filter {
+		b.WriteString(" filter=true")
+	}
+	if d.ByType {
+		b.WriteString(" sample_by_type=true")
+	}
+	b.WriteString(" fields=[")
+	b.WriteString(strings.Join(d.Fields, " "))
+	b.WriteString("]")
+	if d.Defaults {
+		b.WriteString(" use_default_fields=true")
+	}
+	if len(d.DebugFields) > 0 {
+		b.WriteString(" debug_fields=[")
+		b.WriteString(strings.Join(d.DebugFields, " "))
+		b.WriteString("]")
+	}
+	if len(d.Escalations) > 0 {
+		b.WriteString(" escalations=[")
+		b.WriteString(strings.Join(d.Escalations, " "))
+		b.WriteString("]")
+	}
+	if d.Canonical {
+		b.WriteString(" canonicalize_locators=true")
+	}
+	if d.NormalizeMD {
+		b.WriteString(" normalize_metadata_keys=true")
+	}
+	if d.Stack {
+		b.WriteString(" stack_on_error=true")
+	}
+	if d.KeepLast {
+		b.WriteString(" keep_last=true")
+	}
+	if d.BatchCap > 0 {
+		b.WriteString(" batch_cap=")
+		b.WriteString(strconv.Itoa(d.BatchCap))
+	}
+	if d.Clock {
+		b.WriteString(" clock=true")
+	}
+	if d.OnError {
+		b.WriteString(" on_error=true")
+	}
+	if d.EncodedSize {
+		b.WriteString(" encoded_size=true")
+	}
+	if d.Seq {
+		b.WriteString(" sequence_numbers=true")
+	}
+	if d.TypeCounts {
+		b.WriteString(" type_counters=true")
+	}
+	if d.FieldErrors {
+		b.WriteString(" field_errors=true")
+	}
+	if d.Correlation {
+		b.WriteString(" correlation_index=true")
+	}
+	if d.EntryBudget > 0 {
+		b.WriteString(" entry_budget=")
+		b.WriteString(strconv.Itoa(d.EntryBudget))
+	}
+	if len(d.Priorities) > 0 {
+		b.WriteString(" field_priorities=[")
+		for i, key := range slices.Sorted(maps.Keys(d.Priorities)) {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(key)
+			b.WriteString("=")
+			b.WriteString(strconv.Itoa(int(d.Priorities[key])))
+		}
+		b.WriteString("]")
+	}
+	if d.Tee {
+		b.WriteString(" tee=true")
+	}
+	if d.Metrics {
+		b.WriteString(" metrics=true")
+	}
+	if len(d.Partners) > 0 {
+		b.WriteString(" partner_overrides=[")
+		for i, id := range slices.Sorted(maps.Keys(d.Partners)) {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(id)
+			b.WriteString("=[")
+			b.WriteString(strings.Join(d.Partners[id], " "))
+			b.WriteString("]")
+		}
+		b.WriteString("]")
+	}
+
+	return b.String()
+}
+
+// MarshalJSON describes the Observer's configuration as JSON.
+func (ob Observer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ob.describe())
+}
+
+func typeMessages(messages map[wrp.MessageType]string) map[string]string {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	named := make(map[string]string, len(messages))
+	for mt, text := range messages {
+		named[typeName(mt)] = text
+	}
+	return named
+}
+
+func fieldOptNames(opts []FieldOpt) []string {
+	if opts == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(opts))
+	for _, opt := range opts {
+		names = append(names, fieldOptName(opt))
+	}
+	return names
+}
+
+func partnerOverrideNames(overrides map[string][]FieldOpt) map[string][]string {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	names := make(map[string][]string, len(overrides))
+	for id, opts := range overrides {
+		names[id] = append([]string{}, fieldOptNames(opts)...)
+	}
+	return names
+}
+
+func escalationNames(escs []Escalation) []string {
+	if escs == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(escs))
+	for _, esc := range escs {
+		if esc == nil {
+			names = append(names, "nil")
+			continue
+		}
+		names = append(names, funcName(esc))
+	}
+	return names
+}
+
+// levelName names the level, or "unset" when there is none.
+// baseLevelPtr returns the level the entries are logged at before any
+// escalation, or nil when it is not set.
+func (ob Observer) baseLevelPtr() *zapcore.Level {
+	if ob.AtomicLevel != nil {
+		return AtLevel(ob.AtomicLevel.Level())
+	}
+	return ob.Level
+}
+
+func levelName(level *zapcore.Level) string {
+	if level == nil {
+		return "unset"
+	}
+	return level.String()
+}
+
+// fieldOptName names a FieldOpt: by the name given to Named, the
+// FieldAppender it adapts or the function that created it.
+func fieldOptName(opt FieldOpt) string {
+	if opt == nil {
+		return "nil"
+	}
+
+	if info, ok := lookupOpt(opt); ok {
+		if a, isAppender := info.marker.(*appended); isAppender && info.name == "" {
+			return appenderName(a.a)
+		}
+		return info.name
+	}
+
+	return funcName(opt)
+}
+
+// funcName returns the name of the function without the package path, with
+// the suffix added to closures removed so a closure is named after the
+// function that created it.
+func funcName(fn any) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+
+	name := f.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	if i := strings.Index(name, ".func"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+func TestObserver_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		ob       Observer
+		expected string
+	}{
+		{
+			name:     "empty",
+			expected: `level=unset message="" fields=[]`,
+		}, {
+			name: "configured",
+			ob: Observer{
+				Logger:  zap.NewNop(),
+				Level:   AtLevel(zap.DebugLevel),
+				Message: "wrp received",
+				Fields: []FieldOpt{
+					LogMessageType(),
+					LogMessageTypeAsString(),
+					LogDestination(),
+					FieldOptAtLevel(zap.DebugLevel, LogPayload()),
+				},
+				DebugFields: []FieldOpt{LogMetadata()},
+				Messages: map[wrp.MessageType]string{
+					wrp.SimpleEventMessageType: "wrp event",
+					wrp.CreateMessageType:      "wrp crud",
+				},
+				MessageFunc: func(wrp.Message) string { return "" },
+				Escalations: []Escalation{EscalateOnRDRFailure()},
+				KeepLast:    true,
+				BatchCap:    10,
+			},
+			expected: `level=debug message="wrp received" ` +
+				`messages=[Create="wrp crud" SimpleEvent="wrp event"] message_func=true ` +
+				`fields=[msg_type msg_type_string dest payload] ` +
+				`debug_fields=[metadata] escalations=[wrpzap.EscalateOnRDRLevels] ` +
+				`keep_last=true batch_cap=10`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.ob.String())
+			assert.Equal(t, tt.expected, fmt.Sprint(tt.ob))
+		})
+	}
+}
+
+func TestObserver_MarshalJSON(t *testing.T) {
+	custom := func(msg wrp.Message) zap.Field {
+		return zap.String("custom", msg.Source)
+	}
+
+	ob := Observer{
+		Logger:  zap.NewNop(),
+		Level:   AtLevel(zap.WarnLevel),
+		Message: "wrp received",
+		Messages: map[wrp.MessageType]string{
+			wrp.SimpleEventMessageType: "wrp event",
+		},
+		Fields: []FieldOpt{LogSource(), custom, nil},
+	}
+
+	b, err := json.Marshal(ob)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"level": "warn",
+		"message": "wrp received",
+		"messages": {"SimpleEvent": "wrp event"},
+		"fields": ["source", "wrpzap.TestObserver_MarshalJSON", "nil"]
+	}`, string(b))
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap/zapcore"
+)
+
+// dumpPrefix is the number of bytes of a byte slice DumpFields shows.
+const dumpPrefix = 16
+
+// DumpFields returns what the FieldOpts log for the message as key=value
+// lines, without a logger, to show what an Observer would log.  The lines are
+// in the order of the fields; an inline field that logs several keys has them
+// sorted, and a FieldOpt that logs nothing has no line.  As with
+// FieldOpt.AppendFields, fields from FieldOptAtLevel are not included.
+//
+// The values are rendered to be read rather than parsed: strings are quoted,
+// nil pointers are nil, byte slices are shown as their length and up to the
+// first 16 bytes, objects as {key=value ...} with the keys sorted and arrays
+// as [value ...].  The format is stable, so it can be compared in tests.
+func DumpFields(msg wrp.Message, opts ...FieldOpt) string {
+	var b strings.Builder
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		for _, field := range opt.AppendFields(msg, nil) {
+			enc := zapcore.NewMapObjectEncoder()
+			field.AddTo(enc)
+			for _, key := range slices.Sorted(maps.Keys(enc.Fields)) {
+				b.WriteString(key)
+				b.WriteByte('=')
+				dumpValue(&b, enc.Fields[key])
+				b.WriteByte('\n')
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// dumpValue writes the value, as stored by a zapcore.MapObjectEncoder.
+func dumpValue(b *strings.Builder, v any) {
+	switch v := v.(type) {
+	case nil:
+		b.WriteString("nil")
+	case string:
+		b.WriteString(strconv.Quote(v))
+	case []byte:
+		prefix := v[:min(len(v), dumpPrefix)]
+		fmt.Fprintf(b, "%d bytes %q", len(v), prefix)
+		if len(v) > dumpPrefix {
+			b.WriteString("...")
+		}
+	case map[string]any:
+		b.WriteByte('{')
+		for i, key := range slices.Sorted(maps.Keys(v)) {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(key)
+			b.WriteByte('=')
+			dumpValue(b, v[key])
+		}
+		b.WriteByte('}')
+	case []any:
+		b.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			dumpValue(b, item)
+		}
+		b.WriteByte(']')
+	case time.Duration:
+		b.WriteString(v.String())
+	case time.Time:
+		b.WriteString(v.UTC().Format(time.RFC3339Nano))
+	case fmt.Stringer:
+		b.WriteString(strconv.Quote(v.String()))
+	default:
+		// Values logged with zap.Reflect may be pointers.
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				b.WriteString("nil")
+				return
+			}
+			dumpValue(b, rv.Elem().Interface())
+			return
+		}
+		fmt.Fprint(b, v)
+	}
+}
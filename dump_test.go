@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestDumpFields(t *testing.T) {
+	msg := wrp.Message{
+		Type:                    wrp.SimpleRequestResponseMessageType,
+		Source:                  "dns:talaria.example.net",
+		Destination:             "mac:112233445566/config",
+		Status:                  int64p(200),
+		Headers:                 []string{"X-Test: value"},
+		Metadata:                map[string]string{"/hw-model": "TG1682", "/boot-time": "1700000000"},
+		Payload:                 []byte(`{"online":true,"reason":"boot"}`),
+		PartnerIDs:              []string{"comcast", "sky"},
+		QualityOfService:        24,
+		RequestDeliveryResponse: nil,
+	}
+
+	tests := []struct {
+		name   string
+		opts   []FieldOpt
+		golden string
+	}{
+		{
+			name: "none",
+		}, {
+			name: "scalars",
+			opts: []FieldOpt{LogMessageType(), LogSource(), LogQualityOfService()},
+			golden: `msg_type=3
+source="dns:talaria.example.net"
+qos=24
+`,
+		}, {
+			name: "pointers",
+			opts: []FieldOpt{LogStatus(), LogRequestDeliveryResponse()},
+			golden: `status=200
+rdr=nil
+`,
+		}, {
+			name: "byte slices",
+			opts: []FieldOpt{
+				LogPayload(),
+				func(wrp.Message) zap.Field { return zap.Binary("short", []byte("ab\x00")) },
+				func(wrp.Message) zap.Field { return zap.Binary("empty", nil) },
+			},
+			golden: `payload=31 bytes "{\"online\":true,\""...
+short=3 bytes "ab\x00"
+empty=0 bytes ""
+`,
+		}, {
+			name: "objects and arrays",
+			opts: []FieldOpt{LogMetadata(), LogHeaders(), LogPartnerIDs(), LogQualityOfServiceDetailed()},
+			golden: `metadata={/boot-time="1700000000" /hw-model="TG1682"}
+headers=["X-Test: value"]
+partner_ids=["comcast" "sky"]
+qos_detailed={level="low" value=24}
+`,
+		}, {
+			name: "appender",
+			opts: []FieldOpt{LogLocatorSchemes()},
+			golden: `source_scheme="dns"
+dest_scheme="mac"
+`,
+		}, {
+			name: "inline keys, sorted",
+			opts: []FieldOpt{func(wrp.Message) zap.Field {
+				return zap.Inline(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+					enc.AddString("b", "2")
+					enc.AddString("a", "1")
+					return nil
+				}))
+			}},
+			golden: `a="1"
+b="2"
+`,
+		}, {
+			name: "leveled",
+			opts: []FieldOpt{FieldOptAtLevel(zap.DebugLevel, LogSource()), LogQualityOfService()},
+			golden: `qos=24
+`,
+		}, {
+			name: "skipped and nil",
+			opts: []FieldOpt{nil, func(wrp.Message) zap.Field { return zap.Skip() }, LogSource()},
+			golden: `source="dns:talaria.example.net"
+`,
+		}, {
+			name: "other types",
+			opts: []FieldOpt{
+				func(wrp.Message) zap.Field { return zap.Duration("duration", 1500*time.Millisecond) },
+				func(wrp.Message) zap.Field { return zap.Time("time", time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)) },
+				func(wrp.Message) zap.Field { return zap.Error(errors.New("failed")) },
+				func(wrp.Message) zap.Field { return zap.Bool("bool", true) },
+				func(wrp.Message) zap.Field { return zap.Stringer("stringer", wrp.SimpleEventMessageType) },
+				func(wrp.Message) zap.Field { return zap.Reflect("pointer", int64p(7)) },
+				func(wrp.Message) zap.Field { return zap.Reflect("nil_pointer", (*int64)(nil)) },
+			},
+			golden: `duration=1.5s
+time=2025-01-02T03:04:05.000000006Z
+error="failed"
+bool=true
+stringer="SimpleEventMessageType"
+pointer=7
+nil_pointer=nil
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.golden, DumpFields(msg, tt.opts...))
+		})
+	}
+}
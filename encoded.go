@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ObserveEncoded decodes the message from the data in the format and logs it
+// like ObserveWRP, for the transport boundary where only the encoded message
+// is at hand.  With LogEncodedSize set, the size of the data is logged as
+// encoded_size along with the fields.
+//
+// When the data can't be decoded, an Error level entry with the Message text
+// is logged instead, with the format, the size of the data as encoded_size
+// and the decoding error as error.  An unknown format is a decoding error.
+func (ob Observer) ObserveEncoded(_ context.Context, data []byte, format wrp.Format) {
+	var msg wrp.Message
+	if err := decodeMessage(&msg, data, format); err != nil {
+		if ob.Logger == nil {
+			return
+		}
+
+		level := max(ob.baseLevel(), zapcore.ErrorLevel)
+		if ce := ob.Logger.Check(level, ob.Message); ce != nil {
+			ce.Write(ob.withSeq(
+				zap.Stringer(fFormat, format),
+				zap.Int(fEncodedSize, len(data)),
+				zap.Error(err),
+			)...)
+			ob.emitted(level)
+		}
+		return
+	}
+
+	var extra []zap.Field
+	if ob.LogEncodedSize {
+		extra = []zap.Field{zap.Int(fEncodedSize, len(data))}
+	}
+
+	ob.observe(&msg, extra)
+}
+
+// decodeMessage decodes the message from the data, reporting an unknown
+// format as an error rather than letting the wrp package panic.
+func decodeMessage(msg *wrp.Message, data []byte, format wrp.Format) error {
+	if !slices.Contains(wrp.AllFormats(), format) {
+		return fmt.Errorf("%w: unknown format %s", ErrInvalidInput, format)
+	}
+
+	return wrp.NewDecoderBytes(data, format).Decode(msg)
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestObserver_ObserveEncoded(t *testing.T) {
+	msg := wrp.Message{
+		Type:        wrp.SimpleEventMessageType,
+		Source:      "mac:112233445566",
+		Destination: "event:device-status",
+	}
+	msgpack := wrp.MustEncode(&msg, wrp.Msgpack)
+	json := wrp.MustEncode(&msg, wrp.JSON)
+
+	tests := []struct {
+		name        string
+		data        []byte
+		format      wrp.Format
+		encodedSize bool
+		expected    map[string]any
+		failed      bool
+		err         string
+	}{
+		{
+			name:   "msgpack",
+			data:   msgpack,
+			format: wrp.Msgpack,
+			expected: map[string]any{
+				KeySource:      "mac:112233445566",
+				KeyDestination: "event:device-status",
+			},
+		}, {
+			name:        "json with encoded size",
+			data:        json,
+			format:      wrp.JSON,
+			encodedSize: true,
+			expected: map[string]any{
+				KeySource:      "mac:112233445566",
+				KeyDestination: "event:device-status",
+				KeyEncodedSize: int64(len(json)),
+			},
+		}, {
+			name:   "wrong format",
+			data:   json,
+			format: wrp.Msgpack,
+			expected: map[string]any{
+				KeyFormat:      "Msgpack",
+				KeyEncodedSize: int64(len(json)),
+			},
+			failed: true,
+		}, {
+			name:   "truncated",
+			data:   msgpack[:len(msgpack)/2],
+			format: wrp.Msgpack,
+			expected: map[string]any{
+				KeyFormat:      "Msgpack",
+				KeyEncodedSize: int64(len(msgpack) / 2),
+			},
+			failed: true,
+		}, {
+			name:   "unknown format",
+			data:   msgpack,
+			format: wrp.Format(42),
+			expected: map[string]any{
+				KeyFormat:      "Format(42)",
+				KeyEncodedSize: int64(len(msgpack)),
+			},
+			failed: true,
+			err:    "invalid input: unknown format Format(42)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zap.DebugLevel)
+			ob := Observer{
+				Logger:         zap.New(core),
+				Level:          AtLevel(zap.InfoLevel),
+				Message:        "wrp received",
+				Fields:         []FieldOpt{LogSource(), LogDestination()},
+				LogEncodedSize: tt.encodedSize,
+			}
+
+			ob.ObserveEncoded(context.Background(), tt.data, tt.format)
+
+			entries := recorded.AllUntimed()
+			require.Len(t, entries, 1)
+			assert.Equal(t, "wrp received", entries[0].Message)
+
+			fields := entries[0].ContextMap()
+			if tt.failed {
+				assert.Equal(t, zap.ErrorLevel, entries[0].Level)
+				require.Contains(t, fields, "error")
+				if tt.err != "" {
+					assert.Equal(t, tt.err, fields["error"])
+				}
+				delete(fields, "error")
+			} else {
+				assert.Equal(t, zap.InfoLevel, entries[0].Level)
+			}
+			assert.Equal(t, tt.expected, fields)
+		})
+	}
+}
+
+func TestWithEncodedSize(t *testing.T) {
+	ob, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithEncodedSize())
+	require.NoError(t, err)
+	assert.True(t, ob.LogEncodedSize)
+	assert.Contains(t, ob.String(), " encoded_size=true")
+}
+
+func TestObserver_ObserveEncoded_NoLogger(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Observer{}.ObserveEncoded(context.Background(), []byte("bogus"), wrp.Msgpack)
+	})
+}
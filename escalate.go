@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"maps"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap/zapcore"
+)
+
+// Escalation chooses the level a message's entry is logged at, given the
+// level chosen so far.  An Observer never logs an entry below its Level, so
+// an Escalation can raise the level but not lower it.
+type Escalation func(msg wrp.Message, level zapcore.Level) zapcore.Level
+
+// level returns the level the message's entry is logged at.
+func (ob Observer) level(msg *wrp.Message) zapcore.Level {
+	level := ob.baseLevel()
+	for _, esc := range ob.Escalations {
+		if esc == nil {
+			continue
+		}
+		level = max(level, esc(*msg, level))
+	}
+
+	return level
+}
+
+// The request delivery response codes.
+const (
+	rdrDelivered    = 0 // the message was delivered
+	rdrFailed       = 1 // the delivery failed for an unknown reason
+	rdrExpired      = 2 // the message expired before it was delivered
+	rdrQueueFull    = 3 // the device's queue was full
+	rdrOffline      = 4 // the device was offline
+	rdrInvalid      = 5 // the message was invalid
+	rdrUnauthorized = 6 // the message was not authorized
+)
+
+// rdrLevels are the levels of the request delivery response codes that
+// indicate a failure.  Codes that are not listed, including rdrDelivered,
+// keep the configured level.
+var rdrLevels = map[int64]zapcore.Level{
+	rdrFailed:       zapcore.ErrorLevel,
+	rdrExpired:      zapcore.WarnLevel,
+	rdrQueueFull:    zapcore.WarnLevel,
+	rdrOffline:      zapcore.WarnLevel,
+	rdrInvalid:      zapcore.ErrorLevel,
+	rdrUnauthorized: zapcore.ErrorLevel,
+}
+
+// RDRLevels returns a copy of the levels EscalateOnRDRFailure uses for the
+// request delivery response codes.  Transient failures, which may succeed if
+// retried, are Warn and permanent failures are Error.  Codes that are not
+// listed, including 0 for a successful delivery, keep the configured level.
+//
+// To override individual codes, change the returned map and pass it to
+// EscalateOnRDRLevels.
+func RDRLevels() map[int64]zapcore.Level {
+	return maps.Clone(rdrLevels)
+}
+
+// EscalateOnRDRFailure escalates the entries of messages whose request
+// delivery response is a failure, using the levels from RDRLevels.
+func EscalateOnRDRFailure() Escalation {
+	return EscalateOnRDRLevels(rdrLevels)
+}
+
+// EscalateOnRDRLevels escalates the entries of messages whose request
+// delivery response code is in levels to the code's level.  Messages without
+// a request delivery response, or with a code that is not in levels, keep the
+// level.  The map is copied.
+func EscalateOnRDRLevels(levels map[int64]zapcore.Level) Escalation {
+	levels = maps.Clone(levels)
+
+	return func(msg wrp.Message, level zapcore.Level) zapcore.Level {
+		if msg.RequestDeliveryResponse == nil {
+			return level
+		}
+
+		if l, found := levels[*msg.RequestDeliveryResponse]; found {
+			return l
+		}
+
+		return level
+	}
+}
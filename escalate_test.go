@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func rdr(code int64) *int64 {
+	return &code
+}
+
+func TestEscalateOnRDRFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		rdr      *int64
+		level    zapcore.Level
+		expected zapcore.Level
+	}{
+		{name: "no rdr", level: zapcore.InfoLevel, expected: zapcore.InfoLevel},
+		{name: "delivered", rdr: rdr(0), level: zapcore.DebugLevel, expected: zapcore.DebugLevel},
+		{name: "transient", rdr: rdr(4), level: zapcore.InfoLevel, expected: zapcore.WarnLevel},
+		{name: "permanent", rdr: rdr(5), level: zapcore.InfoLevel, expected: zapcore.ErrorLevel},
+		{name: "unknown code", rdr: rdr(1234), level: zapcore.InfoLevel, expected: zapcore.InfoLevel},
+	}
+
+	esc := EscalateOnRDRFailure()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := wrp.Message{RequestDeliveryResponse: tt.rdr}
+			assert.Equal(t, tt.expected, esc(msg, tt.level))
+		})
+	}
+}
+
+func TestEscalateOnRDRLevels(t *testing.T) {
+	levels := RDRLevels()
+	levels[4] = zapcore.ErrorLevel
+	levels[1234] = zapcore.DPanicLevel
+	esc := EscalateOnRDRLevels(levels)
+
+	// Changes after construction have no effect.
+	levels[4] = zapcore.DebugLevel
+
+	assert.Equal(t, zapcore.ErrorLevel, esc(wrp.Message{RequestDeliveryResponse: rdr(4)}, zapcore.InfoLevel))
+	assert.Equal(t, zapcore.DPanicLevel, esc(wrp.Message{RequestDeliveryResponse: rdr(1234)}, zapcore.InfoLevel))
+
+	// The defaults are not changed by overriding a copy.
+	assert.Equal(t, zapcore.WarnLevel, RDRLevels()[4])
+	assert.Equal(t, zapcore.WarnLevel, EscalateOnRDRFailure()(wrp.Message{RequestDeliveryResponse: rdr(4)}, zapcore.InfoLevel))
+}
+
+func TestObserver_Escalations(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	lower := func(wrp.Message, zapcore.Level) zapcore.Level {
+		return zapcore.DebugLevel
+	}
+
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zapcore.InfoLevel),
+		WithFields(LogRequestDeliveryResponse()),
+		WithEscalations(nil, lower, EscalateOnRDRFailure()),
+	)
+	require.NoError(t, err)
+
+	ob.ObserveWRP(context.Background(), wrp.Message{})
+	ob.ObserveWRP(context.Background(), wrp.Message{RequestDeliveryResponse: rdr(0)})
+	ob.ObserveWRP(context.Background(), wrp.Message{RequestDeliveryResponse: rdr(4)})
+	ob.ObserveWRP(context.Background(), wrp.Message{RequestDeliveryResponse: rdr(5)})
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 4)
+	assert.Equal(t, zapcore.InfoLevel, entries[0].Level, "an escalation can't lower the level")
+	assert.Equal(t, zapcore.InfoLevel, entries[1].Level)
+	assert.Equal(t, zapcore.WarnLevel, entries[2].Level)
+	assert.Equal(t, zapcore.ErrorLevel, entries[3].Level)
+}
+
+func TestObserver_EscalationsEnableEntries(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+
+	ob := Observer{
+		Logger:      zap.New(core),
+		Level:       AtLevel(zapcore.InfoLevel),
+		Fields:      []FieldOpt{LogRequestDeliveryResponse()},
+		Escalations: []Escalation{EscalateOnRDRFailure()},
+	}
+
+	ob.ObserveWRP(context.Background(), wrp.Message{RequestDeliveryResponse: rdr(0)})
+	ob.ObserveWRP(context.Background(), wrp.Message{RequestDeliveryResponse: rdr(5)})
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.ErrorLevel, entries[0].Level)
+}
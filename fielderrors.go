@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fieldError is the marker carried by the fields produced by FieldError.
+type fieldError struct {
+	key string
+	err error
+}
+
+func (fe *fieldError) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("key", fe.key)
+	enc.AddString("error", fe.err.Error())
+	return nil
+}
+
+// FieldError reports that the FieldOpt logging the key ran into err, like a
+// value that couldn't be parsed.  A FieldAppender appends it next to the
+// fields it logs anyway, and a FieldOpt can return it in place of its field.
+// The field is left out of the entry; with WithFieldErrors the errors of all
+// the FieldOpts are logged together as field_errors.  A nil err produces
+// zap.Skip().
+func FieldError(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Field{Type: zapcore.SkipType, Interface: &fieldError{key: key, err: err}}
+}
+
+// fieldErrors is the field_errors array, each error as {"key": ..., "error": ...}.
+type fieldErrors []*fieldError
+
+func (errs fieldErrors) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, fe := range errs {
+		if err := enc.AppendObject(fe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectFieldErrors removes the skipped fields from fields, in place,
+// including those a FieldAppender appended, and appends the FieldError fields
+// among them as field_errors when the Observer logs them.
+func (ob Observer) collectFieldErrors(fields []zap.Field) []zap.Field {
+	var errs fieldErrors
+	kept := fields[:0]
+	for _, field := range fields {
+		if field.Type != zapcore.SkipType {
+			kept = append(kept, field)
+		} else if fe, ok := field.Interface.(*fieldError); ok {
+			errs = append(errs, fe)
+		}
+	}
+
+	if ob.FieldErrors && len(errs) > 0 {
+		kept = append(kept, zap.Array(fFieldErrors, errs))
+	}
+	return kept
+}
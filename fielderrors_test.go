@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFieldError(t *testing.T) {
+	assert.Equal(t, zap.Skip(), FieldError("key", nil))
+
+	field := FieldError("key", errors.New("failed"))
+	assert.Equal(t, zapcore.SkipType, field.Type)
+
+	enc := zapcore.NewMapObjectEncoder()
+	field.AddTo(enc)
+	assert.Empty(t, enc.Fields)
+}
+
+func TestWithFieldErrors(t *testing.T) {
+	clock := newFakeClock(time.UnixMilli(5000))
+	failing := func(wrp.Message) zap.Field {
+		return FieldError("custom", errors.New("custom failure"))
+	}
+
+	valid := wrp.Message{
+		Source:      "mac:112233445566",
+		Destination: "event:device-status",
+		Metadata:    map[string]string{DefaultSendTimeMetadataKey: "4000"},
+	}
+	invalid := wrp.Message{
+		Source:      "mac:112233445566",
+		Destination: "not a locator",
+		Metadata:    map[string]string{DefaultSendTimeMetadataKey: "yesterday"},
+	}
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		fields   []FieldOpt
+		msg      wrp.Message
+		expected []string
+	}{
+		{
+			name:    "locator and timestamp",
+			enabled: true,
+			fields: []FieldOpt{
+				LogDestinationNormalized(),
+				LogMessageAgeWithClock(DefaultSendTimeMetadataKey, clock),
+			},
+			msg:      invalid,
+			expected: []string{KeyDestinationNormalized, KeyMessageAge},
+		}, {
+			name:     "FieldOpt",
+			enabled:  true,
+			fields:   []FieldOpt{LogSource(), failing, LogSourceNormalized()},
+			msg:      invalid,
+			expected: []string{"custom"},
+		}, {
+			name:    "no errors",
+			enabled: true,
+			fields: []FieldOpt{
+				LogDestinationNormalized(),
+				LogMessageAgeWithClock(DefaultSendTimeMetadataKey, clock),
+			},
+			msg: valid,
+		}, {
+			name:    "missing timestamp",
+			enabled: true,
+			fields:  []FieldOpt{LogMessageAgeWithClock("/missing", clock)},
+			msg:     invalid,
+		}, {
+			name: "disabled",
+			fields: []FieldOpt{
+				LogDestinationNormalized(),
+				LogMessageAgeWithClock(DefaultSendTimeMetadataKey, clock),
+				failing,
+			},
+			msg: invalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option{WithLevel(zap.InfoLevel), WithFields(tt.fields...)}
+			if tt.enabled {
+				opts = append(opts, WithFieldErrors())
+			}
+
+			core, recorded := observer.New(zap.InfoLevel)
+			ob, err := NewObserver(zap.New(core), opts...)
+			require.NoError(t, err)
+			ob.ObserveWRP(context.Background(), tt.msg)
+
+			entries := recorded.AllUntimed()
+			require.Len(t, entries, 1)
+
+			for _, field := range entries[0].Context {
+				assert.NotEqual(t, zapcore.SkipType, field.Type, field.Key)
+			}
+
+			got, found := entries[0].ContextMap()[KeyFieldErrors]
+			if len(tt.expected) == 0 {
+				assert.False(t, found)
+				return
+			}
+
+			require.True(t, found)
+			errs, ok := got.([]any)
+			require.True(t, ok)
+
+			var keys []string
+			for _, e := range errs {
+				obj, ok := e.(map[string]any)
+				require.True(t, ok)
+				assert.NotEmpty(t, obj["error"])
+				keys = append(keys, obj["key"].(string))
+			}
+			assert.Equal(t, tt.expected, keys)
+		})
+	}
+}
+
+func TestWithFieldErrors_String(t *testing.T) {
+	ob, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithFields(LogSource()), WithFieldErrors())
+	require.NoError(t, err)
+	assert.Contains(t, ob.String(), " field_errors=true")
+}
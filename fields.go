@@ -3,23 +3,201 @@
 
 package wrpzap
 
+// The keys of the fields describing the wrp.Message.  The keys match the JSON
+// tags of the wrp.Message fields.
 const (
-	fMsgType                 = "msg_type"
-	fSource                  = "source"
-	fDestination             = "dest"
-	fTransactionUUID         = "transaction_uuid"
-	fContentType             = "content_type"
-	fAccept                  = "accept"
-	fStatus                  = "status"
-	fRequestDeliveryResponse = "rdr"
-	fHeaders                 = "headers"
-	fMetadata                = "metadata"
-	fPath                    = "path"
-	fPayload                 = "payload"
-	fPayloadSize             = "payload_size"
-	fServiceName             = "service_name"
-	fURL                     = "url"
-	fPartnerIDs              = "partner_ids"
-	fSessionID               = "session_id"
-	fQualityOfService        = "qos"
+	KeyMsgType                 = "msg_type"
+	KeySource                  = "source"
+	KeyDestination             = "dest"
+	KeyTransactionUUID         = "transaction_uuid"
+	KeyContentType             = "content_type"
+	KeyAccept                  = "accept"
+	KeyStatus                  = "status"
+	KeyRequestDeliveryResponse = "rdr"
+	KeyHeaders                 = "headers"
+	KeyMetadata                = "metadata"
+	KeyPath                    = "path"
+	KeyPayload                 = "payload"
+	KeyServiceName             = "service_name"
+	KeyURL                     = "url"
+	KeyPartnerIDs              = "partner_ids"
+	KeySessionID               = "session_id"
+	KeyQualityOfService        = "qos"
+	KeySpans                   = "spans"
+	KeyIncludeSpans            = "include_spans"
+)
+
+// The keys of the fields that are derived from the wrp.Message or describe the
+// entry itself.
+const (
+	KeyPayloadSize   = "payload_size"
+	KeyDetail        = "detail"
+	KeyMessages      = "messages"
+	KeyBatchSize     = "batch_size"
+	KeyPayloadBytes  = "payload_bytes"
+	KeyRetryCount    = "retry_count"
+	KeyRetryCountRaw = "retry_count_raw"
+
+	KeyDestinationPartnerMismatch = "dest_partner_mismatch"
+	KeyQOSBucket                  = "qos_bucket"
+	KeyAcceptMissing              = "accept_missing"
+	KeyPayloadIsValidJSON         = "payload_valid_json"
+	KeyPayloadDecompressedSize    = "payload_decompressed_size"
+	KeyHeadersBytes               = "headers_bytes"
+	KeyMetadataBytes              = "metadata_bytes"
+	KeySourceIsCloud              = "source_is_cloud"
+	KeySourceUnknownScheme        = "source_unknown_scheme"
+	KeyDestinationIsBroadcast     = "dest_is_broadcast"
+	KeyEventName                  = "event_name"
+	KeySourceScheme               = "source_scheme"
+	KeyDestinationScheme          = "dest_scheme"
+	KeyMessageAge                 = "message_age_ms"
+	KeyMessageAgeRaw              = "message_age_raw"
+	KeyUnknownMetadataKeyCount    = "unknown_metadata_key_count"
+	KeyUnknownMetadataKeys        = "unknown_metadata_keys"
+	KeySessionHash                = "session_hash"
+	KeyTransactionUUIDValid       = "transaction_uuid_valid"
+	KeyStacktrace                 = "stacktrace"
+	KeyPayloadPreview             = "payload_preview"
+	KeyPayloadLines               = "payload_lines"
+	KeyPayloadIsBinary            = "payload_is_binary"
+	KeyHeaderDuplicates           = "header_duplicates"
+	KeyHeaderDuplicateNames       = "header_duplicate_names"
+	KeyMissingMetadataKeys        = "missing_metadata_keys"
+	KeyHasMissingMetadataKeys     = "has_missing_metadata_keys"
+	KeyPartnerIDsNormalized       = "partner_ids_normalized"
+	KeyPartnerIDsChanged          = "partner_ids_changed"
+	KeyDestinationNormalized      = "dest_normalized"
+	KeyDestinationParseFailed     = "dest_parse_failed"
+	KeySourceNormalized           = "source_normalized"
+	KeySourceParseFailed          = "source_parse_failed"
+	KeyCRUDPathValid              = "crud_path_valid"
+	KeyDestinationServiceName     = "dest_service_name"
+	KeyMessageCount               = "message_count"
+	KeyTopDestinations            = "top_destinations"
+	KeyCount                      = "count"
+	KeyCountError                 = "count_error"
+	KeyDuration                   = "duration"
+	KeyNotHandled                 = "not_handled"
+	KeyConvertibleType            = "convertible_type"
+	KeyObserveCost                = "observe_cost_us"
+	KeySuppressedErrors           = "suppressed_errors"
+	KeyWebpaHeaders               = "webpa_headers"
+	KeyQualityOfServiceDetailed   = "qos_detailed"
+	KeyFormat                     = "format"
+	KeyEncodedSize                = "encoded_size"
+	KeyDeliveryOutcome            = "delivery_outcome"
+	KeySeq                        = "seq"
+	KeyHostname                   = "hostname"
+	KeyInstanceID                 = "instance_id"
+	KeyMsgTypeCount               = "msg_type_count"
+	KeyDropped                    = "dropped"
+	KeyBackpressure               = "backpressure"
+	KeyParentTransactionUUID      = "parent_transaction_uuid"
+	KeyDestinationLocator         = "dest_locator"
+	KeySourceLocator              = "source_locator"
+	KeyValidationFailures         = "validation_failures"
+	KeyValidationFailureCount     = "validation_failure_count"
+	KeyPayloadSampled             = "payload_sampled"
+	KeyFieldErrors                = "field_errors"
+	KeyFieldsDropped              = "fields_dropped"
+	KeyMetadataKeyCollisions      = "metadata_key_collisions"
+	KeyPartnerID                  = "partner_id"
+	KeyMsgTypes                   = "msg_types"
+)
+
+const (
+	fMsgType                 = KeyMsgType
+	fSource                  = KeySource
+	fDestination             = KeyDestination
+	fTransactionUUID         = KeyTransactionUUID
+	fContentType             = KeyContentType
+	fAccept                  = KeyAccept
+	fStatus                  = KeyStatus
+	fRequestDeliveryResponse = KeyRequestDeliveryResponse
+	fHeaders                 = KeyHeaders
+	fMetadata                = KeyMetadata
+	fPath                    = KeyPath
+	fPayload                 = KeyPayload
+	fPayloadSize             = KeyPayloadSize
+	fServiceName             = KeyServiceName
+	fURL                     = KeyURL
+	fPartnerIDs              = KeyPartnerIDs
+	fSessionID               = KeySessionID
+	fQualityOfService        = KeyQualityOfService
+	fSpans                   = KeySpans
+	fIncludeSpans            = KeyIncludeSpans
+	fDetail                  = KeyDetail
+	fMessages                = KeyMessages
+	fBatchSize               = KeyBatchSize
+	fPayloadBytes            = KeyPayloadBytes
+	fRetryCount              = KeyRetryCount
+	fRetryCountRaw           = KeyRetryCountRaw
+
+	fDestinationPartnerMismatch = KeyDestinationPartnerMismatch
+	fQOSBucket                  = KeyQOSBucket
+	fAcceptMissing              = KeyAcceptMissing
+	fPayloadIsValidJSON         = KeyPayloadIsValidJSON
+	fPayloadDecompressedSize    = KeyPayloadDecompressedSize
+	fHeadersBytes               = KeyHeadersBytes
+	fMetadataBytes              = KeyMetadataBytes
+	fSourceIsCloud              = KeySourceIsCloud
+	fSourceUnknownScheme        = KeySourceUnknownScheme
+	fDestinationIsBroadcast     = KeyDestinationIsBroadcast
+	fEventName                  = KeyEventName
+	fSourceScheme               = KeySourceScheme
+	fDestinationScheme          = KeyDestinationScheme
+	fMessageAge                 = KeyMessageAge
+	fMessageAgeRaw              = KeyMessageAgeRaw
+	fUnknownMetadataKeyCount    = KeyUnknownMetadataKeyCount
+	fUnknownMetadataKeys        = KeyUnknownMetadataKeys
+	fSessionHash                = KeySessionHash
+	fTransactionUUIDValid       = KeyTransactionUUIDValid
+	fStacktrace                 = KeyStacktrace
+	fPayloadPreview             = KeyPayloadPreview
+	fPayloadLines               = KeyPayloadLines
+	fPayloadIsBinary            = KeyPayloadIsBinary
+	fHeaderDuplicates           = KeyHeaderDuplicates
+	fHeaderDuplicateNames       = KeyHeaderDuplicateNames
+	fMissingMetadataKeys        = KeyMissingMetadataKeys
+	fHasMissingMetadataKeys     = KeyHasMissingMetadataKeys
+	fPartnerIDsNormalized       = KeyPartnerIDsNormalized
+	fPartnerIDsChanged          = KeyPartnerIDsChanged
+	fDestinationNormalized      = KeyDestinationNormalized
+	fDestinationParseFailed     = KeyDestinationParseFailed
+	fSourceNormalized           = KeySourceNormalized
+	fSourceParseFailed          = KeySourceParseFailed
+	fCRUDPathValid              = KeyCRUDPathValid
+	fDestinationServiceName     = KeyDestinationServiceName
+	fMessageCount               = KeyMessageCount
+	fTopDestinations            = KeyTopDestinations
+	fCount                      = KeyCount
+	fCountError                 = KeyCountError
+	fDuration                   = KeyDuration
+	fNotHandled                 = KeyNotHandled
+	fConvertibleType            = KeyConvertibleType
+	fObserveCost                = KeyObserveCost
+	fSuppressedErrors           = KeySuppressedErrors
+	fWebpaHeaders               = KeyWebpaHeaders
+	fQualityOfServiceDetailed   = KeyQualityOfServiceDetailed
+	fFormat                     = KeyFormat
+	fEncodedSize                = KeyEncodedSize
+	fDeliveryOutcome            = KeyDeliveryOutcome
+	fSeq                        = KeySeq
+	fHostname                   = KeyHostname
+	fInstanceID                 = KeyInstanceID
+	fMsgTypeCount               = KeyMsgTypeCount
+	fDropped                    = KeyDropped
+	fBackpressure               = KeyBackpressure
+	fParentTransactionUUID      = KeyParentTransactionUUID
+	fDestinationLocator         = KeyDestinationLocator
+	fSourceLocator              = KeySourceLocator
+	fValidationFailures         = KeyValidationFailures
+	fValidationFailureCount     = KeyValidationFailureCount
+	fPayloadSampled             = KeyPayloadSampled
+	fFieldErrors                = KeyFieldErrors
+	fFieldsDropped              = KeyFieldsDropped
+	fMetadataKeyCollisions      = KeyMetadataKeyCollisions
+	fPartnerID                  = KeyPartnerID
+	fMsgTypes                   = KeyMsgTypes
 )
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"slices"
+	"strings"
+)
+
+// FieldSet is a set of FieldOpts chosen by their registered names.  It
+// implements flag.Value, so a command line flag can choose the fields, and
+// encoding.TextUnmarshaler, so configuration can, as in
+// fields: "msg_type,dest,transaction_uuid":
+//
+//	var fields wrpzap.FieldSet
+//	flag.Var(&fields, "wrp-log-fields", "the WRP fields to log")
+//	...
+//	ob, err := wrpzap.NewObserver(logger, wrpzap.WithFields(fields.Opts()...))
+//
+// The zero value is an empty set.
+type FieldSet struct {
+	names []string
+	opts  []FieldOpt
+}
+
+// Set replaces the set with the comma-separated field names, as in
+// "msg_type,dest,qos".  The names are parsed by ParseFieldNames, so
+// whitespace around them and empty names are ignored, and an error naming
+// every unknown name is returned, leaving the set unchanged.
+func (s *FieldSet) Set(value string) error {
+	names := strings.Split(value, ",")
+	opts, err := ParseFieldNames(names)
+	if err != nil {
+		return err
+	}
+
+	// A new slice, so that a copy of the set keeps its names.
+	kept := make([]string, 0, len(opts))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			kept = append(kept, name)
+		}
+	}
+	s.names = kept
+	s.opts = opts
+	return nil
+}
+
+// String returns the field names in the set, comma-separated, in the form
+// Set accepts.
+func (s *FieldSet) String() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(s.names, ",")
+}
+
+// Names returns the field names in the set, in the order they were given.
+func (s *FieldSet) Names() []string {
+	if s == nil {
+		return nil
+	}
+	return slices.Clone(s.names)
+}
+
+// Opts returns the FieldOpts for the field names in the set, in the same
+// order.
+func (s *FieldSet) Opts() []FieldOpt {
+	if s == nil {
+		return nil
+	}
+	return slices.Clone(s.opts)
+}
+
+// MarshalText returns the field names in the set in their canonical form:
+// sorted, without duplicates and comma-separated.  The canonical form of two
+// sets with the same names is the same, whatever order they were given in.
+// Like the other methods it has a pointer receiver, so a FieldSet in a struct
+// is encoded as text when the struct is encoded through a pointer.
+func (s *FieldSet) MarshalText() ([]byte, error) {
+	if s == nil {
+		return []byte{}, nil
+	}
+
+	names := slices.Clone(s.names)
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	return []byte(strings.Join(names, ",")), nil
+}
+
+// UnmarshalText replaces the set with the comma-separated field names, like
+// Set.
+func (s *FieldSet) UnmarshalText(text []byte) error {
+	return s.Set(string(text))
+}
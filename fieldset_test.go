@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldSet_Set(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		err      error
+	}{
+		{
+			name: "empty",
+		}, {
+			name:     "one",
+			value:    "msg_type",
+			expected: "msg_type",
+		}, {
+			name:     "several",
+			value:    "msg_type,dest,qos",
+			expected: "msg_type,dest,qos",
+		}, {
+			name:     "whitespace and empty names",
+			value:    " msg_type, ,dest ,",
+			expected: "msg_type,dest",
+		}, {
+			name:  "unknown",
+			value: "msg_type,nope,dset",
+			err:   ErrUnknownField,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fs FieldSet
+			err := fs.Set(tt.value)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.ErrorContains(t, err, "nope, dset")
+				assert.Empty(t, fs.String())
+				assert.Empty(t, fs.Opts())
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, fs.String())
+			assert.Len(t, fs.Opts(), len(fs.Names()))
+
+			// The string form parses back to the same set.
+			var again FieldSet
+			require.NoError(t, again.Set(fs.String()))
+			assert.Equal(t, fs.Names(), again.Names())
+			assert.Equal(t, fs.String(), again.String())
+		})
+	}
+}
+
+func TestFieldSet_Replace(t *testing.T) {
+	var fs FieldSet
+	require.NoError(t, fs.Set("msg_type,dest"))
+	require.NoError(t, fs.Set("qos"))
+	assert.Equal(t, "qos", fs.String())
+
+	// A failed Set leaves the set as it was.
+	require.Error(t, fs.Set("nope"))
+	assert.Equal(t, "qos", fs.String())
+	assert.Len(t, fs.Opts(), 1)
+}
+
+func TestFieldSet_Nil(t *testing.T) {
+	var fs *FieldSet
+	assert.Empty(t, fs.String())
+	text, err := fs.MarshalText()
+	require.NoError(t, err)
+	assert.Empty(t, text)
+	assert.Nil(t, fs.Names())
+	assert.Nil(t, fs.Opts())
+}
+
+func TestFieldSet_Copy(t *testing.T) {
+	var fs FieldSet
+	require.NoError(t, fs.Set("msg_type,dest,qos"))
+
+	// Setting a copy leaves the original's names alone.
+	cp := fs
+	require.NoError(t, cp.Set("source"))
+	assert.Equal(t, []string{"msg_type", "dest", "qos"}, fs.Names())
+	assert.Equal(t, "msg_type,dest,qos", fs.String())
+	assert.Equal(t, []string{"source"}, cp.Names())
+}
+
+func TestFieldSet_Flag(t *testing.T) {
+	var fs FieldSet
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Var(&fs, "wrp-log-fields", "the WRP fields to log")
+
+	require.NoError(t, flags.Parse([]string{"-wrp-log-fields=msg_type,dest,qos"}))
+	assert.Equal(t, []string{"msg_type", "dest", "qos"}, fs.Names())
+	assert.Len(t, fs.Opts(), 3)
+
+	err := flags.Parse([]string{"-wrp-log-fields=msg_type,bogus"})
+	assert.ErrorContains(t, err, "unknown field: bogus")
+}
+
+func TestFieldSet_Text(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		canonical string
+		err       error
+	}{
+		{
+			name: "empty",
+		}, {
+			name:      "sorted",
+			text:      "msg_type,dest,transaction_uuid",
+			canonical: "dest,msg_type,transaction_uuid",
+		}, {
+			name:      "duplicates",
+			text:      "qos, dest,qos",
+			canonical: "dest,qos",
+		}, {
+			name: "unknown",
+			text: "dest,nope,qos,dset",
+			err:  ErrUnknownField,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fs FieldSet
+			err := fs.UnmarshalText([]byte(tt.text))
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.ErrorContains(t, err, "nope, dset")
+				return
+			}
+			require.NoError(t, err)
+
+			text, err := fs.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, tt.canonical, string(text))
+
+			// The canonical form is stable across round trips.
+			var again FieldSet
+			require.NoError(t, again.UnmarshalText(text))
+			text2, err := again.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, string(text), string(text2))
+		})
+	}
+}
+
+func TestFieldSet_JSON(t *testing.T) {
+	var config struct {
+		Fields FieldSet `json:"fields"`
+	}
+
+	err := json.Unmarshal([]byte(`{"fields": "msg_type,dest,transaction_uuid"}`), &config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"msg_type", "dest", "transaction_uuid"}, config.Fields.Names())
+	assert.Len(t, config.Fields.Opts(), 3)
+
+	data, err := json.Marshal(&config)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"fields": "dest,msg_type,transaction_uuid"}`, string(data))
+
+	err = json.Unmarshal([]byte(`{"fields": "msg_type,bogus,nope"}`), &config)
+	assert.ErrorIs(t, err, ErrUnknownField)
+	assert.ErrorContains(t, err, "bogus, nope")
+}
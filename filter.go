@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// FilterMetadataKeyPresent returns a Filter that accepts the messages with
+// the metadata key, whatever its value.
+func FilterMetadataKeyPresent(key string) func(wrp.Message) bool {
+	return func(msg wrp.Message) bool {
+		_, found := msg.Metadata[key]
+		return found
+	}
+}
+
+// FilterMetadataEquals returns a Filter that accepts the messages with the
+// metadata key set to the value.
+func FilterMetadataEquals(key, value string) func(wrp.Message) bool {
+	return func(msg wrp.Message) bool {
+		v, found := msg.Metadata[key]
+		return found && v == value
+	}
+}
+
+// FilterPayloadLargerThan returns a Filter that accepts the messages with a
+// payload of more than n bytes.
+func FilterPayloadLargerThan(n int) func(wrp.Message) bool {
+	return func(msg wrp.Message) bool {
+		return len(msg.Payload) > n
+	}
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFilterMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		present  bool
+		equals   bool
+	}{
+		{name: "nil metadata"},
+		{name: "empty metadata", metadata: map[string]string{}},
+		{name: "other key", metadata: map[string]string{"/other": "a"}},
+		{name: "matching value", metadata: map[string]string{"/debug-cohort": "a"}, present: true, equals: true},
+		{name: "other value", metadata: map[string]string{"/debug-cohort": "b"}, present: true},
+		{name: "empty value", metadata: map[string]string{"/debug-cohort": ""}, present: true},
+	}
+
+	present := FilterMetadataKeyPresent("/debug-cohort")
+	equals := FilterMetadataEquals("/debug-cohort", "a")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := wrp.Message{Metadata: tt.metadata}
+			assert.Equal(t, tt.present, present(msg))
+			assert.Equal(t, tt.equals, equals(msg))
+		})
+	}
+}
+
+func TestFilterMetadata_Allocations(t *testing.T) {
+	msg := wrp.Message{Metadata: map[string]string{"/debug-cohort": "a"}}
+	present := FilterMetadataKeyPresent("/debug-cohort")
+	equals := FilterMetadataEquals("/debug-cohort", "a")
+
+	assert.Zero(t, testing.AllocsPerRun(100, func() { present(msg) }))
+	assert.Zero(t, testing.AllocsPerRun(100, func() { equals(msg) }))
+}
+
+func TestObserver_Filter(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithFields(LogSource()),
+		WithFilter(FilterMetadataKeyPresent("/debug-cohort")),
+		WithKeepLast(),
+	)
+	require.NoError(t, err)
+
+	ob.ObserveWRP(context.Background(), wrp.Message{Source: "mac:112233445566"})
+	ob.ObserveWRP(context.Background(), wrp.Message{
+		Source:   "mac:665544332211",
+		Metadata: map[string]string{"/debug-cohort": "1"},
+	})
+	ob.ObserveWRP(context.Background(), wrp.Message{Source: "mac:aabbccddeeff"})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "mac:665544332211", entries[0].ContextMap()[KeySource])
+
+	// Filtered messages are still observed.
+	last, _, ok := ob.LastObserved()
+	require.True(t, ok)
+	assert.Equal(t, "mac:aabbccddeeff", last.Source)
+}
+
+func TestDeltaObserver_Filter(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	d, err := NewDeltaObserver(Observer{
+		Logger: zap.New(core),
+		Fields: []FieldOpt{LogPayload()},
+		Filter: FilterMetadataEquals("/debug-cohort", "yes"),
+	}, 10, LogSessionID())
+	require.NoError(t, err)
+
+	cohort := map[string]string{"/debug-cohort": "yes"}
+	d.ObserveWRP(context.Background(), wrp.Message{SessionID: "a", Payload: []byte("1"), Metadata: cohort})
+	d.ObserveWRP(context.Background(), wrp.Message{SessionID: "a", Payload: []byte("2")})
+	d.ObserveWRP(context.Background(), wrp.Message{SessionID: "a", Payload: []byte("1"), Metadata: cohort})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+
+	// The filtered message didn't change what the session last logged.
+	assert.Equal(t, []string{KeySessionID}, keys(entries[1].Context))
+}
+
+func TestFilterPayloadLargerThan(t *testing.T) {
+	filter := FilterPayloadLargerThan(4)
+
+	assert.False(t, filter(wrp.Message{}))
+	assert.False(t, filter(wrp.Message{Payload: []byte("1234")}))
+	assert.True(t, filter(wrp.Message{Payload: []byte("12345")}))
+
+	assert.True(t, FilterPayloadLargerThan(-1)(wrp.Message{}))
+}
+
+func TestObserver_FilterPayloadLargerThan(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithFields(LogDestination(), LogPayloadSize()),
+		WithFilter(FilterPayloadLargerThan(1024)),
+	)
+	require.NoError(t, err)
+
+	ob.ObserveWRP(context.Background(), wrp.Message{Destination: "heartbeat", Payload: []byte("ping")})
+	ob.ObserveWRP(context.Background(), wrp.Message{Destination: "large", Payload: make([]byte, 4096)})
+	ob.ObserveWRP(context.Background(), wrp.Message{Destination: "limit", Payload: make([]byte, 1024)})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{
+		KeyDestination: "large",
+		KeyPayloadSize: int64(4096),
+	}, entries[0].ContextMap())
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrUnknownGroup is returned when a field group name is not registered.
+	ErrUnknownGroup = errors.New("unknown field group")
+
+	// ErrDuplicateGroup is returned when a field group name is registered
+	// twice.
+	ErrDuplicateGroup = errors.New("duplicate field group")
+)
+
+// GroupPrefix marks a name given to ParseFieldNames as the name of a field
+// group, as in group:routing.
+const GroupPrefix = "group:"
+
+func init() {
+	builtins := map[string][]FieldOpt{
+		// routing is where the message came from and is going.
+		"routing": {
+			LogMessageType(),
+			LogSource(),
+			LogDestination(),
+			LogTransactionUUID(),
+			LogSessionID(),
+		},
+
+		// delivery is how the delivery of the message went.
+		"delivery": {
+			LogStatus(),
+			LogRequestDeliveryResponse(),
+			LogDeliveryOutcome(),
+			LogQualityOfService(),
+		},
+
+		// content describes what the message carries.
+		"content": {
+			LogContentType(),
+			LogAccept(),
+			LogPayloadSize(),
+			LogHeaders(),
+			LogMetadata(),
+		},
+	}
+
+	for name, opts := range builtins {
+		registry.groups[name] = opts
+	}
+}
+
+// RegisterFieldGroup makes a group of FieldOpts available to ParseFieldNames
+// by name, so configuration can refer to several fields at once as
+// group:name.  The built-in groups are:
+//
+//   - routing: msg_type, source, dest, transaction_uuid and session_id
+//   - delivery: status, rdr, delivery_outcome and qos
+//   - content: content_type, accept, payload_size, headers and metadata
+//
+// The name must not already be registered and the group must not be empty.
+// The FieldOpts are copied.
+func RegisterFieldGroup(name string, opts ...FieldOpt) error {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, ",:") {
+		return fmt.Errorf("%w: invalid field group name '%s'", ErrInvalidInput, name)
+	}
+	if len(opts) == 0 {
+		return fmt.Errorf("%w: field group '%s' is empty", ErrInvalidInput, name)
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			return fmt.Errorf("%w: nil FieldOpt in field group '%s'", ErrInvalidInput, name)
+		}
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	if _, found := registry.groups[name]; found {
+		return fmt.Errorf("%w: '%s'", ErrDuplicateGroup, name)
+	}
+
+	registry.groups[name] = slices.Clone(opts)
+	return nil
+}
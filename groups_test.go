@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+// groupMessage sets every field the built-in groups log.
+var groupMessage = wrp.Message{
+	Type:                    wrp.SimpleRequestResponseMessageType,
+	Source:                  "dns:talaria.example.net",
+	Destination:             "mac:112233445566/config",
+	TransactionUUID:         "c07ee5e1-70be-444c-a156-097c767ad8aa",
+	SessionID:               "session-1",
+	Status:                  int64p(200),
+	RequestDeliveryResponse: int64p(0),
+	QualityOfService:        24,
+	ContentType:             "application/json",
+	Accept:                  "application/msgpack",
+	Payload:                 []byte("{}"),
+	Headers:                 []string{"X-Test: value"},
+	Metadata:                map[string]string{"/boot-time": "1700000000"},
+}
+
+func TestFieldGroups_Builtins(t *testing.T) {
+	tests := []struct {
+		group    string
+		expected []FieldOpt
+	}{
+		{
+			group: "routing",
+			expected: []FieldOpt{
+				LogMessageType(),
+				LogSource(),
+				LogDestination(),
+				LogTransactionUUID(),
+				LogSessionID(),
+			},
+		}, {
+			group: "delivery",
+			expected: []FieldOpt{
+				LogStatus(),
+				LogRequestDeliveryResponse(),
+				LogDeliveryOutcome(),
+				LogQualityOfService(),
+			},
+		}, {
+			group: "content",
+			expected: []FieldOpt{
+				LogContentType(),
+				LogAccept(),
+				LogPayloadSize(),
+				LogHeaders(),
+				LogMetadata(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.group, func(t *testing.T) {
+			opts, err := ParseFieldNames([]string{"group:" + tt.group})
+			require.NoError(t, err)
+			require.Len(t, opts, len(tt.expected))
+
+			for i := range opts {
+				assert.Equal(t, encode(tt.expected[i](groupMessage)), encode(opts[i](groupMessage)))
+			}
+		})
+	}
+}
+
+func TestParseFieldNames_Groups(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    []string
+		expected []string
+		err      []error
+		errText  string
+	}{
+		{
+			name:     "group",
+			names:    []string{"group:delivery"},
+			expected: []string{KeyStatus, KeyRequestDeliveryResponse, KeyDeliveryOutcome, KeyQualityOfService},
+		}, {
+			name:     "groups and fields in order",
+			names:    []string{"payload", " group:routing ", "qos"},
+			expected: []string{KeyPayload, KeyMsgType, KeySource, KeyDestination, KeyTransactionUUID, KeySessionID, KeyQualityOfService},
+		}, {
+			name:     "space after the prefix",
+			names:    []string{"group: content"},
+			expected: []string{KeyContentType, KeyAccept, KeyPayloadSize, KeyHeaders, KeyMetadata},
+		}, {
+			name:    "unknown group",
+			names:   []string{"group:routing", "group:bogus", "group:"},
+			err:     []error{ErrUnknownGroup},
+			errText: "unknown field group: group:bogus, group:",
+		}, {
+			name:    "unknown fields and groups",
+			names:   []string{"bogus", "group:bogus"},
+			err:     []error{ErrUnknownField, ErrUnknownGroup},
+			errText: "unknown field: bogus\nunknown field group: group:bogus",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := ParseFieldNames(tt.names)
+			if tt.err != nil {
+				for _, e := range tt.err {
+					assert.ErrorIs(t, err, e)
+				}
+				assert.EqualError(t, err, tt.errText)
+				assert.Nil(t, opts)
+				return
+			}
+
+			require.NoError(t, err)
+			got := make([]string, 0, len(opts))
+			for _, opt := range opts {
+				got = append(got, opt(groupMessage).Key)
+			}
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestRegisterFieldGroup(t *testing.T) {
+	custom := func(msg wrp.Message) zap.Field {
+		return zap.String("custom", msg.Source)
+	}
+
+	require.NoError(t, RegisterFieldGroup(" test_group ", custom, LogDestination()))
+	t.Cleanup(func() {
+		registry.lock.Lock()
+		delete(registry.groups, "test_group")
+		registry.lock.Unlock()
+	})
+
+	opts, err := ParseFieldNames([]string{"group:test_group"})
+	require.NoError(t, err)
+	require.Len(t, opts, 2)
+	assert.Equal(t, zap.String("custom", "src"), opts[0](wrp.Message{Source: "src"}))
+
+	tests := []struct {
+		name      string
+		groupName string
+		opts      []FieldOpt
+		err       error
+	}{
+		{name: "registered", groupName: "test_group", opts: []FieldOpt{custom}, err: ErrDuplicateGroup},
+		{name: "built-in", groupName: "routing", opts: []FieldOpt{custom}, err: ErrDuplicateGroup},
+		{name: "empty name", groupName: " ", opts: []FieldOpt{custom}, err: ErrInvalidInput},
+		{name: "comma", groupName: "a,b", opts: []FieldOpt{custom}, err: ErrInvalidInput},
+		{name: "colon", groupName: "a:b", opts: []FieldOpt{custom}, err: ErrInvalidInput},
+		{name: "empty group", groupName: "empty", err: ErrInvalidInput},
+		{name: "nil FieldOpt", groupName: "nil_opt", opts: []FieldOpt{custom, nil}, err: ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, RegisterFieldGroup(tt.groupName, tt.opts...), tt.err)
+		})
+	}
+
+	// A field can't be named like a group reference.
+	assert.ErrorIs(t, RegisterFieldOpt(FieldDescription{Name: "group:x"}, LogSource), ErrInvalidInput)
+}
+
+func TestRegisterFieldGroup_Copied(t *testing.T) {
+	opts := []FieldOpt{LogSource()}
+	require.NoError(t, RegisterFieldGroup("test_copied", opts...))
+	t.Cleanup(func() {
+		registry.lock.Lock()
+		delete(registry.groups, "test_copied")
+		registry.lock.Unlock()
+	})
+
+	opts[0] = LogDestination()
+	parsed, err := ParseFieldNames([]string{"group:test_copied"})
+	require.NoError(t, err)
+	assert.Equal(t, KeySource, parsed[0](groupMessage).Key)
+}
+
+func TestFieldSet_Groups(t *testing.T) {
+	var s FieldSet
+	require.NoError(t, s.Set("group:routing,payload"))
+	assert.Len(t, s.Opts(), 6)
+	assert.Equal(t, "group:routing,payload", s.String())
+}
@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+// DefaultRetryCountHeader is the header LogRetryCount reads the retry count
+// from.
+const DefaultRetryCountHeader = "X-Xmidt-Retry-Count"
+
+// splitHeader splits a header in the "Name: value" form into the name and
+// value, with surrounding whitespace removed.  ok is false when there is no
+// colon.
+func splitHeader(header string) (name, value string, ok bool) {
+	name, value, ok = strings.Cut(header, ":")
+	return strings.TrimSpace(name), strings.TrimSpace(value), ok
+}
+
+// findHeader returns the value of the first header with the name, compared
+// case-insensitively.
+func findHeader(headers []string, name string) (string, bool) {
+	for _, header := range headers {
+		n, v, ok := splitHeader(header)
+		if ok && strings.EqualFold(n, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// LogHeader logs the value of the first header with the name, compared
+// case-insensitively, using the name as the key.  Headers are expected in the
+// "Name: value" form.  An empty string is logged when the header is absent.
+func LogHeader(name string) FieldOpt {
+	return Named("header", name, func(msg wrp.Message) zap.Field {
+		value, _ := findHeader(msg.Headers, name)
+		return zap.String(name, value)
+	})
+}
+
+// LogRetryCount logs the retry count from the DefaultRetryCountHeader header.
+// See LogRetryCountFrom.
+func LogRetryCount() FieldOpt {
+	return LogRetryCountFrom(DefaultRetryCountHeader)
+}
+
+// LogRetryCountFrom logs the retry count from the named header as an int.
+// Zero is logged when the header is absent.  When the value is not a
+// non-negative integer, -1 is logged along with the raw value under
+// retry_count_raw.
+func LogRetryCountFrom(name string) FieldOpt {
+	return Named("retry_count", KeyRetryCount+","+KeyRetryCountRaw, Appender(FieldAppenderFunc(func(msg wrp.Message, fields []zap.Field) []zap.Field {
+		value, found := findHeader(msg.Headers, name)
+		if !found {
+			return append(fields, zap.Int(fRetryCount, 0))
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return append(fields,
+				zap.Int(fRetryCount, -1),
+				zap.String(fRetryCountRaw, value),
+			)
+		}
+		return append(fields, zap.Int(fRetryCount, n))
+	})))
+}
+
+// LogHeadersBytes logs the total size in bytes of the headers, without
+// logging their contents.
+func LogHeadersBytes() FieldOpt {
+	return Named("headers_bytes", KeyHeadersBytes, func(msg wrp.Message) zap.Field {
+		var size int
+		for _, header := range msg.Headers {
+			size += len(header)
+		}
+		return zap.Int(fHeadersBytes, size)
+	})
+}
+
+// MalformedHeaderName is the name LogHeaderDuplicates counts headers without
+// a colon under, so that repeated malformed headers are reported too.
+const MalformedHeaderName = "(malformed)"
+
+// LogHeaderDuplicates logs whether any header name appears more than once as
+// header_duplicates.  Names are compared case-insensitively.  When there are
+// duplicates, the lowercase duplicated names are logged, sorted, as
+// header_duplicate_names.  Headers without a colon are counted under
+// MalformedHeaderName.
+func LogHeaderDuplicates() FieldOpt {
+	return Named("header_duplicates", KeyHeaderDuplicates, Appender(FieldAppenderFunc(func(msg wrp.Message, fields []zap.Field) []zap.Field {
+		names := getStrings()
+		defer putStrings(names)
+
+		for _, header := range msg.Headers {
+			name, _, ok := splitHeader(header)
+			if !ok {
+				name = MalformedHeaderName
+			}
+			*names = append(*names, name)
+		}
+		slices.SortFunc(*names, compareFold)
+
+		// Only the duplicated names are copied out of the scratch slice.
+		var dups []string
+		for i := 1; i < len(*names); i++ {
+			name := (*names)[i]
+			if compareFold(name, (*names)[i-1]) != 0 {
+				continue
+			}
+			if len(dups) == 0 || compareFold(name, dups[len(dups)-1]) != 0 {
+				dups = append(dups, strings.ToLower(name))
+			}
+		}
+
+		if len(dups) == 0 {
+			return append(fields, zap.Bool(fHeaderDuplicates, false))
+		}
+
+		return append(fields,
+			zap.Bool(fHeaderDuplicates, true),
+			zap.Strings(fHeaderDuplicateNames, dups),
+		)
+	})))
+}
+
+// compareFold compares the strings as if they were lowercased, without
+// lowercasing them.
+func compareFold(a, b string) int {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if c := cmp.Compare(unicode.ToLower(ra), unicode.ToLower(rb)); c != 0 {
+			return c
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return cmp.Compare(len(a), len(b))
+}
@@ -0,0 +1,244 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fieldMap encodes the field the FieldOpt produces for the message.
+func fieldMap(opt FieldOpt, msg wrp.Message) map[string]any {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range opt.AppendFields(msg, nil) {
+		f.AddTo(enc)
+	}
+	return enc.Fields
+}
+
+func TestLogHeader(t *testing.T) {
+	headers := []string{
+		"Content-Length: 12",
+		"malformed",
+		"X-Test:  first ",
+		"x-test: second",
+	}
+
+	tests := []struct {
+		name     string
+		header   string
+		headers  []string
+		expected string
+	}{
+		{
+			name:     "found",
+			header:   "X-Test",
+			headers:  headers,
+			expected: "first",
+		}, {
+			name:     "case insensitive",
+			header:   "content-length",
+			headers:  headers,
+			expected: "12",
+		}, {
+			name:    "absent",
+			header:  "X-Missing",
+			headers: headers,
+		}, {
+			name:    "no colon is not a name",
+			header:  "malformed",
+			headers: headers,
+		}, {
+			name:   "nil headers",
+			header: "X-Test",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldMap(LogHeader(tt.header), wrp.Message{Headers: tt.headers})
+			assert.Equal(t, map[string]any{tt.header: tt.expected}, got)
+		})
+	}
+}
+
+func TestLogRetryCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		opt      FieldOpt
+		headers  []string
+		expected map[string]any
+	}{
+		{
+			name:     "absent",
+			opt:      LogRetryCount(),
+			expected: map[string]any{KeyRetryCount: int64(0)},
+		}, {
+			name:     "present",
+			opt:      LogRetryCount(),
+			headers:  []string{"Accept: */*", "X-Xmidt-Retry-Count: 2"},
+			expected: map[string]any{KeyRetryCount: int64(2)},
+		}, {
+			name:     "case insensitive",
+			opt:      LogRetryCount(),
+			headers:  []string{"x-xmidt-retry-count:3"},
+			expected: map[string]any{KeyRetryCount: int64(3)},
+		}, {
+			name:    "unparseable",
+			opt:     LogRetryCount(),
+			headers: []string{"X-Xmidt-Retry-Count: two"},
+			expected: map[string]any{
+				KeyRetryCount:    int64(-1),
+				KeyRetryCountRaw: "two",
+			},
+		}, {
+			name:    "negative",
+			opt:     LogRetryCount(),
+			headers: []string{"X-Xmidt-Retry-Count: -2"},
+			expected: map[string]any{
+				KeyRetryCount:    int64(-1),
+				KeyRetryCountRaw: "-2",
+			},
+		}, {
+			name:     "custom header",
+			opt:      LogRetryCountFrom("X-Retries"),
+			headers:  []string{"X-Xmidt-Retry-Count: 2", "X-Retries: 5"},
+			expected: map[string]any{KeyRetryCount: int64(5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, fieldMap(tt.opt, wrp.Message{Headers: tt.headers}))
+		})
+	}
+}
+
+func TestLogHeadersBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  []string
+		expected int
+	}{
+		{
+			name: "nil",
+		}, {
+			name:    "empty headers",
+			headers: []string{"", ""},
+		}, {
+			name:     "headers",
+			headers:  []string{"Accept: */*", "X-Test: ünïcode"},
+			expected: 11 + 17,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LogHeadersBytes()(wrp.Message{Headers: tt.headers})
+			assert.Equal(t, zap.Int(KeyHeadersBytes, tt.expected), got)
+		})
+	}
+}
+
+func TestLogHeaderDuplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  []string
+		expected map[string]any
+	}{
+		{
+			name:     "no headers",
+			expected: map[string]any{KeyHeaderDuplicates: false},
+		}, {
+			name:     "unique",
+			headers:  []string{"Accept: a", "Content-Type: b", "malformed"},
+			expected: map[string]any{KeyHeaderDuplicates: false},
+		}, {
+			name:    "case-insensitive",
+			headers: []string{"Accept: a", "ACCEPT: b", " accept : c"},
+			expected: map[string]any{
+				KeyHeaderDuplicates:     true,
+				KeyHeaderDuplicateNames: []any{"accept"},
+			},
+		}, {
+			name:    "sorted",
+			headers: []string{"X-B: 1", "X-A: 1", "x-b: 2", "x-a: 2", "X-C: 1"},
+			expected: map[string]any{
+				KeyHeaderDuplicates:     true,
+				KeyHeaderDuplicateNames: []any{"x-a", "x-b"},
+			},
+		}, {
+			name:    "prefixes",
+			headers: []string{"X-A: 1", "X-AB: 1", "x-ab: 2"},
+			expected: map[string]any{
+				KeyHeaderDuplicates:     true,
+				KeyHeaderDuplicateNames: []any{"x-ab"},
+			},
+		}, {
+			name:    "non-ASCII",
+			headers: []string{"Ärger: 1", "ärger: 2", "Zeit: 1"},
+			expected: map[string]any{
+				KeyHeaderDuplicates:     true,
+				KeyHeaderDuplicateNames: []any{"ärger"},
+			},
+		}, {
+			name:    "malformed",
+			headers: []string{"first", "second", "Accept: a"},
+			expected: map[string]any{
+				KeyHeaderDuplicates:     true,
+				KeyHeaderDuplicateNames: []any{MalformedHeaderName},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldMap(LogHeaderDuplicates(), wrp.Message{Headers: tt.headers})
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestLogHeaderDuplicates_Allocs(t *testing.T) {
+	msg := wrp.Message{Headers: []string{
+		"Accept: application/json",
+		"Content-Type: application/msgpack",
+		"X-Xmidt-Retry-Count: 1",
+		"X-Webpa-Device-Name: mac:112233445566",
+		"malformed",
+	}}
+
+	opt := LogHeaderDuplicates()
+	fields := make([]zap.Field, 0, 2)
+	opt.AppendFields(msg, fields)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		opt.AppendFields(msg, fields)
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestCompareFold(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "", b: "", expected: 0},
+		{a: "accept", b: "ACCEPT", expected: 0},
+		{a: "Accept", b: "b", expected: -1},
+		{a: "x-a", b: "X-AB", expected: -1},
+		{a: "X-AB", b: "x-a", expected: 1},
+		{a: "Ärger", b: "äRGER", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, compareFold(tt.a, tt.b))
+		})
+	}
+}
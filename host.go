@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"os"
+	"strings"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+// UnknownHostname is logged by LogHostname when the hostname can't be
+// resolved.
+const UnknownHostname = "unknown"
+
+// osHostname resolves the hostname; it is replaced by tests.
+var osHostname = os.Hostname
+
+// resolveHostname returns the hostname, or UnknownHostname when it can't be
+// resolved or is empty.
+func resolveHostname() string {
+	name, err := osHostname()
+	name = strings.TrimSpace(name)
+	if err != nil || name == "" {
+		return UnknownHostname
+	}
+	return name
+}
+
+// LogHostname logs the hostname of the machine as hostname.  The hostname is
+// resolved once, when LogHostname is called, and the same field is logged
+// for every message.  When it can't be resolved UnknownHostname is logged.
+func LogHostname() FieldOpt {
+	field := zap.String(fHostname, resolveHostname())
+	return Named("hostname", KeyHostname, func(wrp.Message) zap.Field {
+		return field
+	})
+}
+
+// LogHostInfo returns the FieldOpts that log the identity of the instance
+// logging the entries: the hostname, see LogHostname, and, when it isn't
+// empty, the instanceID as instance_id.  Both are static: nothing is resolved
+// per message.
+//
+//	wrpzap.WithFields(wrpzap.LogHostInfo(os.Getenv("INSTANCE_ID"))...)
+func LogHostInfo(instanceID string) []FieldOpt {
+	opts := []FieldOpt{LogHostname()}
+	if instanceID != "" {
+		field := zap.String(fInstanceID, instanceID)
+		opts = append(opts, func(wrp.Message) zap.Field {
+			return field
+		})
+	}
+	return opts
+}
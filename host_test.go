@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+// stubHostname replaces the hostname resolution for the test, counting the
+// calls.
+func stubHostname(t *testing.T, name string, err error) *int {
+	var calls int
+	saved := osHostname
+	osHostname = func() (string, error) {
+		calls++
+		return name, err
+	}
+	t.Cleanup(func() { osHostname = saved })
+	return &calls
+}
+
+func TestLogHostname(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostname string
+		err      error
+		expected string
+	}{
+		{name: "resolved", hostname: "talaria-7f9c", expected: "talaria-7f9c"},
+		{name: "error", err: errors.New("no hostname"), expected: UnknownHostname},
+		{name: "error with a name", hostname: "partial", err: errors.New("failed"), expected: UnknownHostname},
+		{name: "empty", hostname: " ", expected: UnknownHostname},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := stubHostname(t, tt.hostname, tt.err)
+
+			opt := LogHostname()
+			for i := 0; i < 3; i++ {
+				assert.Equal(t, zap.String(KeyHostname, tt.expected), opt(wrp.Message{Source: "mac:112233445566"}))
+			}
+			assert.Equal(t, 1, *calls, "the hostname must be resolved once")
+		})
+	}
+}
+
+func TestLogHostInfo(t *testing.T) {
+	calls := stubHostname(t, "talaria-7f9c", nil)
+
+	opts := LogHostInfo("instance-3")
+	require.Len(t, opts, 2)
+	msg := wrp.Message{}
+	assert.Equal(t, zap.String(KeyHostname, "talaria-7f9c"), opts[0](msg))
+	assert.Equal(t, zap.String(KeyInstanceID, "instance-3"), opts[1](msg))
+
+	opts = LogHostInfo("")
+	require.Len(t, opts, 1)
+	assert.Equal(t, zap.String(KeyHostname, "talaria-7f9c"), opts[0](msg))
+
+	assert.Equal(t, 2, *calls)
+}
+
+func TestLogHostname_System(t *testing.T) {
+	field := LogHostname()(wrp.Message{})
+	assert.Equal(t, KeyHostname, field.Key)
+	assert.NotEmpty(t, field.String)
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// lastObserved holds a private copy of the most recently observed message.
+type lastObserved struct {
+	lock sync.Mutex
+	msg  wrp.Message
+	when time.Time
+	ok   bool
+}
+
+func (l *lastObserved) store(m *wrp.Message, when time.Time) {
+	msg := cloneMessage(*m)
+
+	l.lock.Lock()
+	l.msg, l.when, l.ok = msg, when, true
+	l.lock.Unlock()
+}
+
+func (l *lastObserved) load() (wrp.Message, time.Time, bool) {
+	l.lock.Lock()
+	msg, when, ok := l.msg, l.when, l.ok
+	l.lock.Unlock()
+
+	return cloneMessage(msg), when, ok
+}
+
+// LastObserved returns a copy of the most recently observed message and the
+// time it was observed.  The boolean is false when KeepLast is not enabled,
+// the Observer was not created by NewObserver, or no message has been
+// observed yet.
+//
+// The returned message shares no memory with the message that was observed,
+// so it is safe to modify.
+func (ob Observer) LastObserved() (wrp.Message, time.Time, bool) {
+	if !ob.KeepLast || ob.state == nil {
+		return wrp.Message{}, time.Time{}, false
+	}
+
+	return ob.state.last.load()
+}
+
+// cloneMessage returns a deep copy of the message.
+func cloneMessage(msg wrp.Message) wrp.Message {
+	msg.Payload = slices.Clone(msg.Payload)
+	msg.Headers = slices.Clone(msg.Headers)
+	msg.Metadata = maps.Clone(msg.Metadata)
+	msg.PartnerIDs = slices.Clone(msg.PartnerIDs)
+
+	if msg.Spans != nil {
+		spans := make([][]string, len(msg.Spans))
+		for i, span := range msg.Spans {
+			spans[i] = slices.Clone(span)
+		}
+		msg.Spans = spans
+	}
+	if msg.Status != nil {
+		status := *msg.Status
+		msg.Status = &status
+	}
+	if msg.RequestDeliveryResponse != nil {
+		rdr := *msg.RequestDeliveryResponse
+		msg.RequestDeliveryResponse = &rdr
+	}
+	if msg.IncludeSpans != nil {
+		include := *msg.IncludeSpans
+		msg.IncludeSpans = &include
+	}
+
+	return msg
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+func TestObserver_LastObserved(t *testing.T) {
+	status := int64(200)
+	msg := wrp.Message{
+		Type:        wrp.SimpleEventMessageType,
+		Destination: "event:device-status",
+		Status:      &status,
+		Headers:     []string{"header1", "header2"},
+		Metadata:    map[string]string{"key": "value"},
+		PartnerIDs:  []string{"partner1"},
+		Payload:     []byte("test payload"),
+	}
+
+	clock := newFakeClock(time.UnixMilli(1000))
+	ob, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithKeepLast(), WithClock(clock))
+	require.NoError(t, err)
+
+	_, _, ok := ob.LastObserved()
+	assert.False(t, ok)
+
+	ob.ObserveWRP(context.Background(), msg)
+	clock.Add(time.Second)
+
+	got, when, ok := ob.LastObserved()
+	require.True(t, ok)
+	assert.Equal(t, msg, got)
+	assert.Equal(t, time.UnixMilli(1000), when)
+
+	// Changes made by the pipeline after observation must not be visible.
+	msg.Payload[0] = 'X'
+	msg.Headers[0] = "changed"
+	msg.Metadata["key"] = "changed"
+	msg.PartnerIDs[0] = "changed"
+	*msg.Status = 500
+
+	got, _, ok = ob.LastObserved()
+	require.True(t, ok)
+	assert.Equal(t, []byte("test payload"), got.Payload)
+	assert.Equal(t, "header1", got.Headers[0])
+	assert.Equal(t, "value", got.Metadata["key"])
+	assert.Equal(t, "partner1", got.PartnerIDs[0])
+	assert.Equal(t, int64(200), *got.Status)
+
+	// Neither can changes made by the caller.
+	got.Payload[0] = 'Y'
+	got.Metadata["key"] = "mutated"
+
+	again, _, _ := ob.LastObserved()
+	assert.Equal(t, []byte("test payload"), again.Payload)
+	assert.Equal(t, "value", again.Metadata["key"])
+}
+
+func TestObserver_LastObserved_Disabled(t *testing.T) {
+	tests := []struct {
+		name string
+		ob   func() Observer
+	}{
+		{
+			name: "not enabled",
+			ob: func() Observer {
+				ob, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel))
+				require.NoError(t, err)
+				return ob
+			},
+		}, {
+			name: "struct literal",
+			ob: func() Observer {
+				return Observer{Logger: zap.NewNop(), KeepLast: true}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ob := tt.ob()
+			ob.ObserveWRP(context.Background(), wrp.Message{Source: "test"})
+
+			_, _, ok := ob.LastObserved()
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestObserver_LastObserved_Concurrent(t *testing.T) {
+	ob, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithKeepLast())
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ob.ObserveWRP(context.Background(), wrp.Message{
+					Metadata: map[string]string{"key": "value"},
+				})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ob.LastObserved()
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, _, ok := ob.LastObserved()
+	require.True(t, ok)
+	assert.Equal(t, "value", got.Metadata["key"])
+}
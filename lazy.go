@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// lazyFunc adds fields to an entry when it is encoded.
+type lazyFunc func(zapcore.ObjectEncoder)
+
+func (fn lazyFunc) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	fn(enc)
+	return nil
+}
+
+// lazy returns a field whose keys and values are only computed when the
+// entry is encoded.  It is used by the FieldOpts whose values are expensive
+// to compute, so that entries dropped by a core after they are written, like
+// a filtering core, don't pay for them.
+//
+// The field is an inline field, so key is not logged; it names the field for
+// the entry budget's priorities and fields_dropped, and should be the key of
+// the main field fn adds.  size is an estimate of the encoded size of the
+// fields fn adds, see estimateSize, which the entry budget uses so it
+// doesn't compute them.  Both are carried in the field itself, since an
+// inline field leaves its Key and Integer unused.
+//
+// Because the computation happens later, it sees the message as it is when
+// the entry is encoded.  For the usual cores that is before ObserveWRP
+// returns, but cores that keep the fields, like zaptest's observer, compute
+// the value when it is read.
+func lazy(key string, size int, fn func(zapcore.ObjectEncoder)) zap.Field {
+	return zap.Field{
+		Key:       key,
+		Type:      zapcore.InlineMarshalerType,
+		Integer:   int64(size),
+		Interface: lazyFunc(fn),
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// encode returns the keys and values the field adds to an entry.
+func encode(field zap.Field) map[string]any {
+	enc := zapcore.NewMapObjectEncoder()
+	field.AddTo(enc)
+	return enc.Fields
+}
+
+// droppingCore accepts every entry but drops it when it is written, without
+// encoding it, like a filtering core.
+type droppingCore struct {
+	zapcore.LevelEnabler
+}
+
+func (c droppingCore) With([]zapcore.Field) zapcore.Core { return c }
+func (droppingCore) Sync() error                         { return nil }
+
+func (c droppingCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(e, c)
+}
+
+func (droppingCore) Write(zapcore.Entry, []zapcore.Field) error { return nil }
+
+func TestLazy(t *testing.T) {
+	var calls int
+	opt := func(wrp.Message) zap.Field {
+		return lazy("calls", scalarFieldSize("calls"), func(enc zapcore.ObjectEncoder) {
+			calls++
+			enc.AddInt("calls", calls)
+		})
+	}
+
+	dropped := Observer{
+		Logger: zap.New(droppingCore{zapcore.DebugLevel}),
+		Fields: []FieldOpt{opt},
+	}
+	dropped.ObserveWRP(context.Background(), wrp.Message{})
+	assert.Zero(t, calls, "a dropped entry must not compute the value")
+
+	core, recorded := observer.New(zapcore.DebugLevel)
+	kept := Observer{
+		Logger: zap.New(core),
+		Fields: []FieldOpt{opt},
+	}
+	kept.ObserveWRP(context.Background(), wrp.Message{})
+	assert.Zero(t, calls, "the value is computed when the entry is encoded")
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{"calls": 1}, entries[0].ContextMap())
+	assert.Equal(t, 1, calls)
+}
+
+func TestLazy_FieldOpts(t *testing.T) {
+	msg := wrp.Message{
+		ContentType: "application/json",
+		Payload:     []byte(`{"a": 1}`),
+		SessionID:   "session123",
+		Metadata:    map[string]string{"/extra": "value"},
+	}
+
+	tests := []struct {
+		name     string
+		opt      FieldOpt
+		expected map[string]any
+	}{
+		{
+			name:     "LogSessionIDHashed",
+			opt:      MustLogSessionIDHashed([]byte("salt")),
+			expected: map[string]any{KeySessionHash: hashSessionID([]byte("salt"), "session123")},
+		}, {
+			name:     "LogPayloadIsValidJSON",
+			opt:      LogPayloadIsValidJSON(),
+			expected: map[string]any{KeyPayloadIsValidJSON: true},
+		}, {
+			name: "LogUnknownMetadataKeys",
+			opt:  LogUnknownMetadataKeys(),
+			expected: map[string]any{
+				KeyUnknownMetadataKeyCount: 1,
+				KeyUnknownMetadataKeys:     []any{"/extra"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := tt.opt.AppendFields(msg, nil)
+			require.Len(t, fields, 1)
+			field := fields[0]
+			assert.Equal(t, zapcore.InlineMarshalerType, field.Type)
+			assert.Contains(t, tt.expected, field.Key, "a lazy field is named by a key it logs")
+			assert.Equal(t, tt.expected, encode(field))
+		})
+	}
+}
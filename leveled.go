@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// leveled is the marker carried by the fields produced by FieldOptAtLevel.
+type leveled struct {
+	min zapcore.Level
+	opt FieldOpt
+}
+
+// FieldOptAtLevel wraps a FieldOpt so the wrapped field is only included when
+// the Observer's logger core enables the min level.  This makes it possible
+// to log the payload only when the logger has been turned up to Debug, while
+// the entry itself is still written at the Observer's Level.
+//
+// The wrapped FieldOpt is not evaluated unless the level is enabled.  When the
+// returned FieldOpt is used outside of an Observer it produces a skipped
+// field.
+func FieldOptAtLevel(min zapcore.Level, opt FieldOpt) FieldOpt {
+	l := &leveled{
+		min: min,
+		opt: opt,
+	}
+
+	// The wrapped FieldOpt's name and key describe the field.
+	return Named(opt.Name(), opt.Key(), func(wrp.Message) zap.Field {
+		return zap.Field{Type: zapcore.SkipType, Interface: l}
+	})
+}
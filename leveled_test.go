@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFieldOptAtLevel(t *testing.T) {
+	payload := []byte("test payload")
+	msg := wrp.Message{
+		Source:  "test source",
+		Payload: payload,
+	}
+
+	tests := []struct {
+		name            string
+		coreLevel       zapcore.Level
+		fields          []FieldOpt
+		expected_fields []zap.Field
+	}{
+		{
+			name:      "info core leaves the payload out",
+			coreLevel: zap.InfoLevel,
+			fields: []FieldOpt{
+				LogSource(),
+				FieldOptAtLevel(zap.DebugLevel, LogPayload()),
+			},
+			expected_fields: []zap.Field{zap.String(fSource, "test source")},
+		}, {
+			name:      "debug core includes the payload",
+			coreLevel: zap.DebugLevel,
+			fields: []FieldOpt{
+				LogSource(),
+				FieldOptAtLevel(zap.DebugLevel, LogPayload()),
+			},
+			expected_fields: []zap.Field{
+				zap.String(fSource, "test source"),
+				zap.Binary(fPayload, payload),
+			},
+		}, {
+			name:      "nested levels all need to be enabled",
+			coreLevel: zap.InfoLevel,
+			fields: []FieldOpt{
+				FieldOptAtLevel(zap.InfoLevel,
+					FieldOptAtLevel(zap.DebugLevel, LogPayload())),
+			},
+			expected_fields: []zap.Field{},
+		}, {
+			name:      "nested levels that are enabled",
+			coreLevel: zap.DebugLevel,
+			fields: []FieldOpt{
+				FieldOptAtLevel(zap.InfoLevel,
+					FieldOptAtLevel(zap.DebugLevel, LogPayload())),
+			},
+			expected_fields: []zap.Field{zap.Binary(fPayload, payload)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(tt.coreLevel)
+
+			ob := Observer{
+				Logger:  zap.New(core),
+				Level:   AtLevel(zap.InfoLevel),
+				Message: "test message",
+				Fields:  tt.fields,
+			}
+
+			ob.ObserveWRP(context.Background(), msg)
+
+			entries := recorded.All()
+			require.Len(t, entries, 1)
+			assert.ElementsMatch(t, tt.expected_fields, entries[0].Context)
+		})
+	}
+}
+
+func TestFieldOptAtLevel_NotEvaluatedWhenDisabled(t *testing.T) {
+	var calls int
+	opt := func(wrp.Message) zap.Field {
+		calls++
+		return zap.Skip()
+	}
+
+	core, recorded := observer.New(zap.InfoLevel)
+	ob := Observer{
+		Logger: zap.New(core),
+		Level:  AtLevel(zap.InfoLevel),
+		Fields: []FieldOpt{FieldOptAtLevel(zap.DebugLevel, opt)},
+	}
+
+	ob.ObserveWRP(context.Background(), wrp.Message{})
+
+	require.Len(t, recorded.All(), 1)
+	assert.Zero(t, calls)
+}
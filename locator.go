@@ -0,0 +1,297 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"maps"
+	"net/url"
+	"strings"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SchemeClass describes what kind of party a locator scheme identifies.
+type SchemeClass string
+
+const (
+	// SchemeClassCloud is a cloud service, the dns scheme.
+	SchemeClassCloud SchemeClass = "cloud"
+
+	// SchemeClassDevice is a device, the mac, uuid, serial and self schemes.
+	SchemeClassDevice SchemeClass = "device"
+
+	// SchemeClassEvent is an event listener, the event scheme.
+	SchemeClassEvent SchemeClass = "event"
+
+	// SchemeClassUnknown is any other scheme.
+	SchemeClassUnknown SchemeClass = "unknown"
+)
+
+// LocatorScheme returns the lowercase scheme of the locator, the text before
+// the first colon.  An empty string is returned when there is no colon.
+func LocatorScheme(locator string) string {
+	scheme, _, found := strings.Cut(locator, ":")
+	if !found {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(scheme))
+}
+
+// CanonicalLocator returns the locator with the scheme lowercased, and for the
+// device and dns schemes the authority lowercased too, since device IDs and
+// host names are case-insensitive.  Event names, the service and anything
+// after it are left as they are, as is a locator without a colon.
+//
+//	MAC:AABBCCDDEEFF/Config/Path -> mac:aabbccddeeff/Config/Path
+func CanonicalLocator(locator string) string {
+	scheme, rest, found := strings.Cut(locator, ":")
+	if !found {
+		return locator
+	}
+
+	scheme = strings.ToLower(scheme)
+	switch ClassifyScheme(scheme) {
+	case SchemeClassDevice, SchemeClassCloud:
+		authority, tail, slash := strings.Cut(rest, "/")
+		rest = strings.ToLower(authority)
+		if slash {
+			rest += "/" + tail
+		}
+	}
+
+	return scheme + ":" + rest
+}
+
+// ClassifyScheme returns the class of the locator scheme.  The scheme is
+// compared case-insensitively.
+func ClassifyScheme(scheme string) SchemeClass {
+	switch strings.ToLower(scheme) {
+	case wrp.SchemeDNS:
+		return SchemeClassCloud
+	case wrp.SchemeMAC, wrp.SchemeUUID, wrp.SchemeSerial, wrp.SchemeSelf:
+		return SchemeClassDevice
+	case wrp.SchemeEvent:
+		return SchemeClassEvent
+	default:
+		return SchemeClassUnknown
+	}
+}
+
+// LogSourceIsCloud logs whether the source is a cloud service (the dns
+// scheme) rather than a device.  When the scheme is neither, false is logged
+// along with the scheme under source_unknown_scheme.
+func LogSourceIsCloud() FieldOpt {
+	return Named("source_is_cloud", KeySourceIsCloud, Appender(FieldAppenderFunc(func(msg wrp.Message, fields []zap.Field) []zap.Field {
+		scheme := LocatorScheme(msg.Source)
+		switch ClassifyScheme(scheme) {
+		case SchemeClassCloud:
+			return append(fields, zap.Bool(fSourceIsCloud, true))
+		case SchemeClassDevice:
+			return append(fields, zap.Bool(fSourceIsCloud, false))
+		default:
+			return append(fields,
+				zap.Bool(fSourceIsCloud, false),
+				zap.String(fSourceUnknownScheme, scheme),
+			)
+		}
+	})))
+}
+
+// LogDestinationIsBroadcast logs whether the destination fans out rather than
+// being delivered to a single device, which is the case for the event and dns
+// schemes.  Device and unknown schemes log false.
+func LogDestinationIsBroadcast() FieldOpt {
+	return Named("dest_is_broadcast", KeyDestinationIsBroadcast, func(msg wrp.Message) zap.Field {
+		switch ClassifyScheme(LocatorScheme(msg.Destination)) {
+		case SchemeClassEvent, SchemeClassCloud:
+			return zap.Bool(fDestinationIsBroadcast, true)
+		default:
+			return zap.Bool(fDestinationIsBroadcast, false)
+		}
+	})
+}
+
+// LogEventName logs the event name of an event destination, the authority
+// between "event:" and the first "/".  Percent-encoded characters in the
+// name are decoded; a name that can't be decoded is logged as is.  An empty
+// string is logged for destinations that are not events.
+func LogEventName() FieldOpt {
+	return Named("event_name", KeyEventName, func(msg wrp.Message) zap.Field {
+		if LocatorScheme(msg.Destination) != wrp.SchemeEvent {
+			return zap.String(fEventName, "")
+		}
+
+		_, rest, _ := strings.Cut(msg.Destination, ":")
+		name, _, _ := strings.Cut(rest, "/")
+		if decoded, err := url.PathUnescape(name); err == nil {
+			name = decoded
+		}
+		return zap.String(fEventName, name)
+	})
+}
+
+// LogLocatorSchemes logs the schemes of the source and destination as
+// source_scheme and dest_scheme, which together describe the kind of flow the
+// message is part of.  A locator without a colon has an empty scheme.
+func LogLocatorSchemes() FieldOpt {
+	return Named("locator_schemes", KeySourceScheme+","+KeyDestinationScheme, Appender(FieldAppenderFunc(func(msg wrp.Message, fields []zap.Field) []zap.Field {
+		return append(fields,
+			zap.String(fSourceScheme, LocatorScheme(msg.Source)),
+			zap.String(fDestinationScheme, LocatorScheme(msg.Destination)),
+		)
+	})))
+}
+
+// NormalizeLocator returns the canonical form of the locator used for
+// joining logs across services.  Unlike CanonicalLocator, which only changes
+// the case, it also rewrites MAC addresses and trailing slashes, and it only
+// accepts the locators wrp-go does.  The locator is parsed with wrp.ParseLocator,
+// and ok is false, with the locator returned unchanged, when it can't be
+// parsed.  The canonical form is:
+//
+//   - the scheme, lowercased
+//   - for mac, the device ID lowercased with the ':', '-' and '.' delimiters
+//     removed
+//   - for uuid and serial, the device ID lowercased
+//   - for dns, the host name lowercased
+//   - for event, the event name as it is
+//   - for self, no authority
+//   - the service and anything after it as they are, with trailing slashes
+//     removed
+//
+// For example:
+//
+//	MAC:AA-BB-CC-DD-EE-FF/Config/ -> mac:aabbccddeeff/Config
+//	DNS:Talaria.Example.NET/api   -> dns:talaria.example.net/api
+func NormalizeLocator(locator string) (normalized string, ok bool) {
+	normalized, err := normalizeLocator(locator)
+	return normalized, err == nil
+}
+
+// normalizeLocator is NormalizeLocator returning the parse error.
+func normalizeLocator(locator string) (string, error) {
+	l, err := wrp.ParseLocator(locator)
+	if err != nil {
+		return locator, err
+	}
+
+	authority := l.Authority
+	switch l.Scheme {
+	case wrp.SchemeMAC:
+		_, authority, _ = strings.Cut(string(l.ID), ":")
+	case wrp.SchemeUUID, wrp.SchemeSerial, wrp.SchemeDNS:
+		authority = strings.ToLower(authority)
+	}
+
+	var b strings.Builder
+	b.WriteString(l.Scheme)
+	b.WriteString(":")
+	b.WriteString(authority)
+	if l.Service != "" {
+		b.WriteString("/")
+		b.WriteString(l.Service)
+	}
+	b.WriteString(l.Ignored)
+
+	return strings.TrimRight(b.String(), "/"), nil
+}
+
+// LogDestinationNormalized logs the destination in the form returned by
+// NormalizeLocator as dest_normalized.  When the destination can't be parsed
+// it is logged unchanged, dest_parse_failed is true and the parse error is
+// reported with FieldError.
+func LogDestinationNormalized() FieldOpt {
+	return Named("dest_normalized", KeyDestinationNormalized+","+KeyDestinationParseFailed, Appender(FieldAppenderFunc(func(msg wrp.Message, fields []zap.Field) []zap.Field {
+		normalized, err := normalizeLocator(msg.Destination)
+		return append(fields,
+			zap.String(fDestinationNormalized, normalized),
+			zap.Bool(fDestinationParseFailed, err != nil),
+			FieldError(fDestinationNormalized, err),
+		)
+	})))
+}
+
+// LogSourceNormalized logs the source in the form returned by
+// NormalizeLocator as source_normalized.  When the source can't be parsed it
+// is logged unchanged, source_parse_failed is true and the parse error is
+// reported with FieldError.
+func LogSourceNormalized() FieldOpt {
+	return Named("source_normalized", KeySourceNormalized+","+KeySourceParseFailed, Appender(FieldAppenderFunc(func(msg wrp.Message, fields []zap.Field) []zap.Field {
+		normalized, err := normalizeLocator(msg.Source)
+		return append(fields,
+			zap.String(fSourceNormalized, normalized),
+			zap.Bool(fSourceParseFailed, err != nil),
+			FieldError(fSourceNormalized, err),
+		)
+	})))
+}
+
+// LogServiceAlias logs the alias of the destination's service as
+// dest_service_name, so that internal service names can be logged as names
+// people recognize.  A service without an alias is logged as it is, and an
+// empty string is logged when the destination has no service or can't be
+// parsed.  The aliases are copied.
+//
+//	LogServiceAlias(map[string]string{"iot": "device-telemetry"})
+func LogServiceAlias(aliases map[string]string) FieldOpt {
+	aliases = maps.Clone(aliases)
+
+	return Named("service_alias", KeyDestinationServiceName, func(msg wrp.Message) zap.Field {
+		l, err := wrp.ParseLocator(msg.Destination)
+		if err != nil || l.Service == "" {
+			return zap.String(fDestinationServiceName, "")
+		}
+
+		if alias, found := aliases[l.Service]; found {
+			return zap.String(fDestinationServiceName, alias)
+		}
+		return zap.String(fDestinationServiceName, l.Service)
+	})
+}
+
+// locatorObject encodes a locator as an object with the parts wrp.ParseLocator
+// finds in it: scheme, authority, service and ignored, the part after the
+// service.  A locator that can't be parsed is encoded as the raw locator and
+// the parse error instead.  The locator is only parsed when the entry is
+// encoded.
+type locatorObject string
+
+func (l locatorObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	parsed, err := wrp.ParseLocator(string(l))
+	if err != nil {
+		enc.AddString("raw", string(l))
+		enc.AddString("error", err.Error())
+		return nil
+	}
+
+	enc.AddString("scheme", parsed.Scheme)
+	enc.AddString("authority", parsed.Authority)
+	enc.AddString("service", parsed.Service)
+	enc.AddString("ignored", parsed.Ignored)
+	return nil
+}
+
+// LogDestinationLocator logs the parts of the destination as an object under
+// dest_locator, for example
+// {"scheme": "mac", "authority": "112233445566", "service": "config",
+// "ignored": "/wifi"}.  Event locators have no service; what follows the
+// event name is ignored.  When the destination can't be parsed the object
+// holds the raw destination and the parse error instead, as
+// {"raw": "...", "error": "..."}.
+func LogDestinationLocator() FieldOpt {
+	return Named("dest_locator", KeyDestinationLocator, func(msg wrp.Message) zap.Field {
+		return zap.Object(fDestinationLocator, locatorObject(msg.Destination))
+	})
+}
+
+// LogSourceLocator logs the parts of the source as an object under
+// source_locator, in the same form as LogDestinationLocator, so the two can
+// be logged together.
+func LogSourceLocator() FieldOpt {
+	return Named("source_locator", KeySourceLocator, func(msg wrp.Message) zap.Field {
+		return zap.Object(fSourceLocator, locatorObject(msg.Source))
+	})
+}
@@ -0,0 +1,509 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLocatorScheme(t *testing.T) {
+	tests := []struct {
+		locator  string
+		expected string
+	}{
+		{locator: "", expected: ""},
+		{locator: "no-colon", expected: ""},
+		{locator: "mac:112233445566", expected: "mac"},
+		{locator: "MAC:112233445566/config", expected: "mac"},
+		{locator: " dns :talaria.example.net", expected: "dns"},
+		{locator: "event:device-status/mac:112233445566/online", expected: "event"},
+		{locator: ":empty", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locator, func(t *testing.T) {
+			assert.Equal(t, tt.expected, LocatorScheme(tt.locator))
+		})
+	}
+}
+
+func TestClassifyScheme(t *testing.T) {
+	tests := []struct {
+		scheme   string
+		expected SchemeClass
+	}{
+		{scheme: "dns", expected: SchemeClassCloud},
+		{scheme: "DNS", expected: SchemeClassCloud},
+		{scheme: "mac", expected: SchemeClassDevice},
+		{scheme: "uuid", expected: SchemeClassDevice},
+		{scheme: "serial", expected: SchemeClassDevice},
+		{scheme: "self", expected: SchemeClassDevice},
+		{scheme: "event", expected: SchemeClassEvent},
+		{scheme: "", expected: SchemeClassUnknown},
+		{scheme: "http", expected: SchemeClassUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ClassifyScheme(tt.scheme))
+		})
+	}
+}
+
+func TestLogSourceIsCloud(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected map[string]any
+	}{
+		{
+			source:   "dns:talaria.example.net/service",
+			expected: map[string]any{KeySourceIsCloud: true},
+		}, {
+			source:   "mac:112233445566/config",
+			expected: map[string]any{KeySourceIsCloud: false},
+		}, {
+			source:   "self:/service",
+			expected: map[string]any{KeySourceIsCloud: false},
+		}, {
+			source: "event:device-status",
+			expected: map[string]any{
+				KeySourceIsCloud:       false,
+				KeySourceUnknownScheme: "event",
+			},
+		}, {
+			source: "http://example.com",
+			expected: map[string]any{
+				KeySourceIsCloud:       false,
+				KeySourceUnknownScheme: "http",
+			},
+		}, {
+			source: "",
+			expected: map[string]any{
+				KeySourceIsCloud:       false,
+				KeySourceUnknownScheme: "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			assert.Equal(t, tt.expected, fieldMap(LogSourceIsCloud(), wrp.Message{Source: tt.source}))
+		})
+	}
+}
+
+func TestLogDestinationIsBroadcast(t *testing.T) {
+	tests := []struct {
+		dest     string
+		expected bool
+	}{
+		{dest: "event:device-status/mac:112233445566/online", expected: true},
+		{dest: "EVENT:device-status", expected: true},
+		{dest: "dns:talaria.example.net/service", expected: true},
+		{dest: "mac:112233445566/config"},
+		{dest: "uuid:1234/config"},
+		{dest: "serial:1234/config"},
+		{dest: "self:/config"},
+		{dest: "http://example.com"},
+		{dest: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dest, func(t *testing.T) {
+			got := LogDestinationIsBroadcast()(wrp.Message{Destination: tt.dest})
+			assert.Equal(t, zap.Bool(KeyDestinationIsBroadcast, tt.expected), got)
+		})
+	}
+}
+
+func TestLogEventName(t *testing.T) {
+	tests := []struct {
+		dest     string
+		expected string
+	}{
+		{dest: "event:device-status", expected: "device-status"},
+		{dest: "event:device-status/mac:112233445566/online", expected: "device-status"},
+		{dest: "event:device-status/mac:112233445566/online/extra/segments", expected: "device-status"},
+		{dest: "Event:node-change/", expected: "node-change"},
+		{dest: "event:device%2Dstatus/mac:112233445566", expected: "device-status"},
+		{dest: "event:a%2Fb/mac:112233445566", expected: "a/b"},
+		{dest: "event:bad%zzname/x", expected: "bad%zzname"},
+		{dest: "event:", expected: ""},
+		{dest: "mac:112233445566/config"},
+		{dest: "dns:talaria.example.net/event:device-status"},
+		{dest: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dest, func(t *testing.T) {
+			got := LogEventName()(wrp.Message{Destination: tt.dest})
+			assert.Equal(t, zap.String(KeyEventName, tt.expected), got)
+		})
+	}
+}
+
+func TestLogLocatorSchemes(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		dest   string
+		scheme [2]string
+	}{
+		{
+			name:   "both present",
+			source: "MAC:112233445566",
+			dest:   "event:device-status",
+			scheme: [2]string{"mac", "event"},
+		}, {
+			name:   "source missing",
+			source: "112233445566",
+			dest:   "dns:talaria.example.net",
+			scheme: [2]string{"", "dns"},
+		}, {
+			name:   "destination missing",
+			source: "self:",
+			scheme: [2]string{"self", ""},
+		}, {
+			name: "both missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldMap(LogLocatorSchemes(), wrp.Message{
+				Source:      tt.source,
+				Destination: tt.dest,
+			})
+			assert.Equal(t, map[string]any{
+				KeySourceScheme:      tt.scheme[0],
+				KeyDestinationScheme: tt.scheme[1],
+			}, got)
+		})
+	}
+}
+
+func TestCanonicalLocator(t *testing.T) {
+	tests := []struct {
+		locator  string
+		expected string
+	}{
+		{locator: "", expected: ""},
+		{locator: "AABBCCDDEEFF", expected: "AABBCCDDEEFF"},
+		{locator: "MAC:AABBCCDDEEFF", expected: "mac:aabbccddeeff"},
+		{locator: "mac:aabbccddeeff", expected: "mac:aabbccddeeff"},
+		{locator: "MAC:AABBCCDDEEFF/Config/Some/Path", expected: "mac:aabbccddeeff/Config/Some/Path"},
+		{locator: "UUID:ABC-123/Svc", expected: "uuid:abc-123/Svc"},
+		{locator: "Serial:ABC123/", expected: "serial:abc123/"},
+		{locator: "SELF:/Svc", expected: "self:/Svc"},
+		{locator: "DNS:Talaria.Example.NET/Svc", expected: "dns:talaria.example.net/Svc"},
+		{locator: "EVENT:Device-Status/MAC:AABB", expected: "event:Device-Status/MAC:AABB"},
+		{locator: "HTTP://Example.com", expected: "http://Example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locator, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CanonicalLocator(tt.locator))
+		})
+	}
+}
+
+func TestObserver_CanonicalizeLocators(t *testing.T) {
+	msg := wrp.Message{
+		Source:      "MAC:AABBCCDDEEFF/Config",
+		Destination: "DNS:Talaria.Example.NET/Svc",
+	}
+	fields := []FieldOpt{LogSource(), LogDestination(), LogLocatorSchemes()}
+
+	tests := []struct {
+		name      string
+		canonical bool
+		expected  map[string]any
+	}{
+		{
+			name: "raw fields are untouched by default",
+			expected: map[string]any{
+				KeySource:            "MAC:AABBCCDDEEFF/Config",
+				KeyDestination:       "DNS:Talaria.Example.NET/Svc",
+				KeySourceScheme:      "mac",
+				KeyDestinationScheme: "dns",
+			},
+		}, {
+			name:      "canonicalized",
+			canonical: true,
+			expected: map[string]any{
+				KeySource:            "mac:aabbccddeeff/Config",
+				KeyDestination:       "dns:talaria.example.net/Svc",
+				KeySourceScheme:      "mac",
+				KeyDestinationScheme: "dns",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zap.InfoLevel)
+			ob := Observer{
+				Logger:               zap.New(core),
+				Fields:               fields,
+				CanonicalizeLocators: tt.canonical,
+			}
+
+			ob.ObserveWRP(context.Background(), msg)
+
+			entries := recorded.All()
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.expected, entries[0].ContextMap())
+		})
+	}
+}
+
+func TestNormalizeLocator(t *testing.T) {
+	tests := []struct {
+		locator  string
+		expected string
+		failed   bool
+	}{
+		// mac
+		{locator: "mac:112233445566", expected: "mac:112233445566"},
+		{locator: "MAC:AABBCCDDEEFF", expected: "mac:aabbccddeeff"},
+		{locator: "mac:AA:BB:CC:DD:EE:FF/Config", expected: "mac:aabbccddeeff/Config"},
+		{locator: "mac:aa-bb-cc-dd-ee-ff/config/Path/", expected: "mac:aabbccddeeff/config/Path"},
+		{locator: "mac:112233445566/", expected: "mac:112233445566"},
+		{locator: "mac:1122", expected: "mac:1122", failed: true},
+
+		// uuid
+		{locator: "UUID:F47AC10B-58CC-4372-A567-0E02B2C3D479/iot", expected: "uuid:f47ac10b-58cc-4372-a567-0e02b2c3d479/iot"},
+		{locator: "uuid:", expected: "uuid:", failed: true},
+
+		// serial
+		{locator: "serial:ABC123/Service//", expected: "serial:abc123/Service"},
+
+		// self
+		{locator: "self:", expected: "self:"},
+		{locator: "SELF:/Config/", expected: "self:/Config"},
+		{locator: "self:abc", expected: "self:abc", failed: true},
+
+		// dns
+		{locator: "dns:Talaria.Example.NET", expected: "dns:talaria.example.net"},
+		{locator: "dns:talaria.example.net/missing", expected: "dns:talaria.example.net/missing"},
+		{locator: "DNS:Talaria.Example.NET/API/v1/", expected: "dns:talaria.example.net/API/v1"},
+		{locator: "dns:", expected: "dns:", failed: true},
+
+		// event
+		{locator: "event:device-status", expected: "event:device-status"},
+		{locator: "EVENT:Device-Status/mac:112233445566/Online/", expected: "event:Device-Status/mac:112233445566/Online"},
+		{locator: "event:", expected: "event:", failed: true},
+
+		// not a locator
+		{locator: "", expected: "", failed: true},
+		{locator: "http://example.com", expected: "http://example.com", failed: true},
+		{locator: "no-colon", expected: "no-colon", failed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locator, func(t *testing.T) {
+			got, ok := NormalizeLocator(tt.locator)
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, !tt.failed, ok)
+		})
+	}
+}
+
+func TestLogDestinationNormalized(t *testing.T) {
+	got := fieldMap(LogDestinationNormalized(), wrp.Message{Destination: "MAC:AABBCCDDEEFF/Config/"})
+	assert.Equal(t, map[string]any{
+		KeyDestinationNormalized:  "mac:aabbccddeeff/Config",
+		KeyDestinationParseFailed: false,
+	}, got)
+
+	got = fieldMap(LogDestinationNormalized(), wrp.Message{Destination: "Bogus/Path/"})
+	assert.Equal(t, map[string]any{
+		KeyDestinationNormalized:  "Bogus/Path/",
+		KeyDestinationParseFailed: true,
+	}, got)
+}
+
+func TestLogSourceNormalized(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+		failed   bool
+	}{
+		{source: "MAC:AA:BB:CC:DD:EE:FF/Parodus", expected: "mac:aabbccddeeff/Parodus"},
+		{source: "uuid:ABC-123/iot/", expected: "uuid:abc-123/iot"},
+		{source: "serial:XYZ/", expected: "serial:xyz"},
+		{source: "self:", expected: "self:"},
+		{source: "self:/config", expected: "self:/config"},
+		{source: "dns:talaria.example.net/missing", expected: "dns:talaria.example.net/missing"},
+		{source: "DNS:Scytale.Example.NET", expected: "dns:scytale.example.net"},
+		{source: "event:device-status/mac:112233445566", expected: "event:device-status/mac:112233445566"},
+		{source: "talaria", expected: "talaria", failed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got := fieldMap(LogSourceNormalized(), wrp.Message{Source: tt.source})
+			assert.Equal(t, map[string]any{
+				KeySourceNormalized:  tt.expected,
+				KeySourceParseFailed: tt.failed,
+			}, got)
+		})
+	}
+}
+
+func TestLogServiceAlias(t *testing.T) {
+	aliases := map[string]string{
+		"iot":     "device-telemetry",
+		"config":  "configuration",
+		"parodus": "device-agent",
+	}
+	opt := LogServiceAlias(aliases)
+
+	// Later changes to the map have no effect.
+	aliases["iot"] = "changed"
+	delete(aliases, "config")
+
+	tests := []struct {
+		dest     string
+		expected string
+	}{
+		{dest: "mac:112233445566/iot", expected: "device-telemetry"},
+		{dest: "mac:112233445566/config/a/b", expected: "configuration"},
+		{dest: "dns:talaria.example.net/parodus", expected: "device-agent"},
+		{dest: "mac:112233445566/unmapped", expected: "unmapped"},
+		{dest: "mac:112233445566", expected: ""},
+		{dest: "event:device-status/iot", expected: ""},
+		{dest: "not a locator", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dest, func(t *testing.T) {
+			got := opt(wrp.Message{Destination: tt.dest})
+			assert.Equal(t, zap.String(KeyDestinationServiceName, tt.expected), got)
+		})
+	}
+
+	// A nil map logs the services as they are.
+	got := LogServiceAlias(nil)(wrp.Message{Destination: "mac:112233445566/iot"})
+	assert.Equal(t, zap.String(KeyDestinationServiceName, "iot"), got)
+}
+
+func TestLocatorObject(t *testing.T) {
+	parts := func(scheme, authority, service, ignored string) map[string]any {
+		return map[string]any{
+			"scheme":    scheme,
+			"authority": authority,
+			"service":   service,
+			"ignored":   ignored,
+		}
+	}
+	failed := func(raw, err string) map[string]any {
+		return map[string]any{"raw": raw, "error": err}
+	}
+
+	tests := []struct {
+		locator  string
+		expected map[string]any
+	}{
+		{"mac:112233445566/config/wifi", parts("mac", "112233445566", "config", "/wifi")},
+		{"MAC:11-22-33-44-55-66", parts("mac", "11-22-33-44-55-66", "", "")},
+		{"uuid:c07ee5e1-70be-444c-a156-097c767ad8aa/svc", parts("uuid", "c07ee5e1-70be-444c-a156-097c767ad8aa", "svc", "")},
+		{"serial:ABC123/parodus/", parts("serial", "ABC123", "parodus", "/")},
+		{"self:/config", parts("self", "", "config", "")},
+		{"dns:talaria.example.net/api/v2", parts("dns", "talaria.example.net", "api", "/v2")},
+		{"event:device-status/mac:112233445566/online", parts("event", "device-status", "", "/mac:112233445566/online")},
+		{"", failed("", "invalid locator: `` does not match expected locator pattern")},
+		{"nocolon", failed("nocolon", "invalid locator: `nocolon` does not match expected locator pattern")},
+		{"foo:bar/baz", failed("foo:bar/baz", "invalid locator: `foo:bar/baz` does not match expected locator pattern")},
+		{"mac:bogus", failed("mac:bogus", "invalid device name: unable to make a device ID with scheme `mac` and authority `bogus`")},
+		{"dns:", failed("dns:", "invalid locator: empty authority")},
+		{"event:", failed("event:", "invalid locator: empty authority")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locator, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			require.NoError(t, locatorObject(tt.locator).MarshalLogObject(enc))
+			assert.Equal(t, tt.expected, enc.Fields)
+		})
+	}
+}
+
+func TestLogDestinationLocator(t *testing.T) {
+	msg := wrp.Message{
+		Source:      "dns:talaria.example.net",
+		Destination: "mac:112233445566/config/wifi",
+	}
+
+	assert.Equal(t, map[string]any{
+		KeyDestinationLocator: map[string]any{
+			"scheme":    "mac",
+			"authority": "112233445566",
+			"service":   "config",
+			"ignored":   "/wifi",
+		},
+	}, fieldMap(LogDestinationLocator(), msg))
+}
+
+func TestLogDestinationLocator_Golden(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		golden      string
+	}{
+		{
+			name:        "parsed",
+			destination: "mac:112233445566/config/wifi",
+			golden:      `{"dest_locator":{"scheme":"mac","authority":"112233445566","service":"config","ignored":"/wifi"}}`,
+		}, {
+			name:        "failed",
+			destination: "dns:",
+			golden:      `{"dest_locator":{"raw":"dns:","error":"invalid locator: empty authority"}}`,
+		},
+	}
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := LogDestinationLocator()(wrp.Message{Destination: tt.destination})
+			got, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{field})
+			require.NoError(t, err)
+			assert.Equal(t, tt.golden+"\n", got.String())
+		})
+	}
+}
+
+func TestLogSourceLocator(t *testing.T) {
+	msg := wrp.Message{
+		Source:      "dns:talaria.example.net/api/v2",
+		Destination: "bogus",
+	}
+
+	assert.Equal(t, map[string]any{
+		KeySourceLocator: map[string]any{
+			"scheme":    "dns",
+			"authority": "talaria.example.net",
+			"service":   "api",
+			"ignored":   "/v2",
+		},
+	}, fieldMap(LogSourceLocator(), msg))
+
+	// Both can be logged together, each with its own key.
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	got, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{
+		LogSourceLocator()(msg),
+		LogDestinationLocator()(msg),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `{"source_locator":{"scheme":"dns","authority":"talaria.example.net","service":"api","ignored":"/v2"},`+
+		"\"dest_locator\":{\"raw\":\"bogus\",\"error\":\"invalid locator: `bogus` does not match expected locator pattern\"}}\n", got.String())
+}
@@ -0,0 +1,252 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogMetadataBytes logs the total size in bytes of the metadata keys and
+// values, without logging their contents.
+func LogMetadataBytes() FieldOpt {
+	return Named("metadata_bytes", KeyMetadataBytes, func(msg wrp.Message) zap.Field {
+		var size int
+		for k, v := range msg.Metadata {
+			size += len(k) + len(v)
+		}
+		return zap.Int(fMetadataBytes, size)
+	})
+}
+
+// metadataObject encodes metadata as an object with the keys in ascending
+// byte order, as compared by the < operator on strings, which is the
+// ordering LogMetadata promises.  The encoder is handed the keys in that
+// order, so the order doesn't depend on the encoder.
+type metadataObject map[string]string
+
+func (m metadataObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := getStrings()
+	defer putStrings(keys)
+
+	sortedKeys(keys, m)
+	for _, k := range *keys {
+		enc.AddString(k, m[k])
+	}
+	return nil
+}
+
+// DefaultSendTimeMetadataKey is the metadata key devices record the time they
+// sent the message under, in milliseconds since the epoch.
+const DefaultSendTimeMetadataKey = "/xmidt-send-time"
+
+// LogMessageAge logs the time between the send time recorded in the metadata
+// key, in milliseconds since the epoch, and now, as message_age_ms.  The
+// current time comes from the Observer's Clock.  When the value is missing
+// or not a number, -1 is logged along with the raw value under
+// message_age_raw, and a value that is not a number is reported with
+// FieldError.
+//
+// Calling the returned FieldOpt directly produces an inline field holding
+// the same fields as its AppendFields method, which uses the system clock.
+func LogMessageAge(metadataKey string) FieldOpt {
+	return LogMessageAgeWithClock(metadataKey, nil)
+}
+
+// LogMessageAgeWithClock is LogMessageAge using the clock for the current
+// time instead of the Observer's Clock.  A nil clock uses the Observer's
+// Clock.
+func LogMessageAgeWithClock(metadataKey string, clock Clock) FieldOpt {
+	return Named("message_age", KeyMessageAge+","+KeyMessageAgeRaw, envAppender(func(env fieldEnv, msg wrp.Message, fields []zap.Field) []zap.Field {
+		raw, found := metadataValue(msg.Metadata, metadataKey, env.normalizeMetadata)
+		ms, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			fields = append(fields,
+				zap.Int64(fMessageAge, -1),
+				zap.String(fMessageAgeRaw, raw),
+			)
+			if found {
+				fields = append(fields, FieldError(fMessageAge, err))
+			}
+			return fields
+		}
+
+		now := clock
+		if now == nil {
+			now = env.clock
+		}
+		age := clockOrDefault(now).Now().Sub(time.UnixMilli(ms))
+		return append(fields, zap.Int64(fMessageAge, age.Milliseconds()))
+	}))
+}
+
+// DefaultUnknownMetadataKeysMax is the most unknown metadata keys
+// LogUnknownMetadataKeys logs the names of.
+const DefaultUnknownMetadataKeysMax = 10
+
+// LogUnknownMetadataKeys logs the number of metadata keys that are not one of
+// the known keys as unknown_metadata_key_count.  When there are at most
+// DefaultUnknownMetadataKeysMax of them, their sorted names are logged as
+// unknown_metadata_keys too.
+func LogUnknownMetadataKeys(known ...string) FieldOpt {
+	return LogUnknownMetadataKeysMax(DefaultUnknownMetadataKeysMax, known...)
+}
+
+// LogUnknownMetadataKeysMax is LogUnknownMetadataKeys with the most unknown
+// keys to log the names of set to max.
+//
+// The unknown keys are found when the entry is encoded, so entries that are
+// dropped don't pay for it.  Calling the returned FieldOpt directly produces
+// an inline field holding the same fields as its AppendFields method, which
+// matches the keys exactly.
+func LogUnknownMetadataKeysMax(max int, known ...string) FieldOpt {
+	// When the Observer normalizes the metadata keys, the known keys are
+	// known in their normalized form too.
+	exact := make(map[string]struct{}, len(known))
+	normalized := make(map[string]struct{}, 2*len(known))
+	for _, k := range known {
+		exact[k] = struct{}{}
+		normalized[k] = struct{}{}
+		normalized[NormalizeMetadataKey(k)] = struct{}{}
+	}
+
+	return Named("unknown_metadata_keys", KeyUnknownMetadataKeyCount+","+KeyUnknownMetadataKeys, envAppender(func(env fieldEnv, msg wrp.Message, fields []zap.Field) []zap.Field {
+		metadata := msg.Metadata
+		set := exact
+		if env.normalizeMetadata {
+			set = normalized
+		}
+
+		// The names can only be logged when no more than max of the keys
+		// can be unknown, and are at most all of the keys.
+		size := scalarFieldSize(fUnknownMetadataKeyCount)
+		if len(metadata)-len(set) <= max {
+			size += len(fUnknownMetadataKeys) + fieldOverhead + 2
+			for k := range metadata {
+				size += len(k) + 3
+			}
+		}
+
+		return append(fields, lazy(fUnknownMetadataKeyCount, size, func(enc zapcore.ObjectEncoder) {
+			keys := getStrings()
+			defer putStrings(keys)
+
+			for k := range metadata {
+				if _, ok := set[k]; !ok {
+					*keys = append(*keys, k)
+				}
+			}
+			slices.Sort(*keys)
+
+			enc.AddInt(fUnknownMetadataKeyCount, len(*keys))
+			if len(*keys) <= max {
+				_ = enc.AddArray(fUnknownMetadataKeys, (*stringArray)(keys))
+			}
+		}))
+	}))
+}
+
+// LogMissingMetadataKeys logs the required metadata keys that are missing
+// from the message, sorted, as missing_metadata_keys, and whether any are
+// missing as has_missing_metadata_keys.  An empty list is logged when all of
+// them are present.
+//
+// Calling the returned FieldOpt directly produces an inline field holding
+// the same fields as its AppendFields method, which matches the keys exactly.
+func LogMissingMetadataKeys(required ...string) FieldOpt {
+	required = slices.Clone(required)
+	slices.Sort(required)
+	required = slices.Compact(required)
+
+	return Named("missing_metadata_keys", KeyHasMissingMetadataKeys+","+KeyMissingMetadataKeys, envAppender(func(env fieldEnv, msg wrp.Message, fields []zap.Field) []zap.Field {
+		missing := []string{}
+		for _, k := range required {
+			if _, ok := metadataValue(msg.Metadata, k, env.normalizeMetadata); !ok {
+				missing = append(missing, k)
+			}
+		}
+
+		return append(fields,
+			zap.Bool(fHasMissingMetadataKeys, len(missing) > 0),
+			zap.Strings(fMissingMetadataKeys, missing),
+		)
+	}))
+}
+
+// NormalizeMetadataKey returns the metadata key with a single leading slash
+// removed and lowercased, so /FW-Name and fw-name are the same key.
+func NormalizeMetadataKey(key string) string {
+	return strings.ToLower(strings.TrimPrefix(key, "/"))
+}
+
+// normalizeMetadata returns the metadata with its keys normalized and the
+// sorted normalized keys that more than one key was normalized to.  When keys
+// collide the value of a key with a leading slash is kept, and among those
+// the value of the smallest key.  The metadata is returned as is when its
+// keys are already normalized.
+func normalizeMetadata(metadata map[string]string) (map[string]string, []string) {
+	normalized := true
+	for k := range metadata {
+		if NormalizeMetadataKey(k) != k {
+			normalized = false
+			break
+		}
+	}
+	if normalized {
+		return metadata, nil
+	}
+
+	keys := make(map[string]string, len(metadata))
+	out := make(map[string]string, len(metadata))
+	var collisions []string
+	for k, v := range metadata {
+		n := NormalizeMetadataKey(k)
+		prev, found := keys[n]
+		if found {
+			if !slices.Contains(collisions, n) {
+				collisions = append(collisions, n)
+			}
+			if !preferMetadataKey(k, prev) {
+				continue
+			}
+		}
+		keys[n] = k
+		out[n] = v
+	}
+
+	slices.Sort(collisions)
+	return out, collisions
+}
+
+// preferMetadataKey reports whether the value of key a is kept over that of
+// key b when they are normalized to the same key.
+func preferMetadataKey(a, b string) bool {
+	slashA, slashB := strings.HasPrefix(a, "/"), strings.HasPrefix(b, "/")
+	if slashA != slashB {
+		return slashA
+	}
+	return a < b
+}
+
+// metadataValue returns the value of the metadata key.  When normalized is
+// set it falls back to the key in its normalized form, so that the keys
+// FieldOpts are configured with are found in metadata normalized by
+// NormalizeMetadataKeys.
+func metadataValue(metadata map[string]string, key string, normalized bool) (string, bool) {
+	if v, found := metadata[key]; found {
+		return v, true
+	}
+
+	if n := NormalizeMetadataKey(key); normalized && n != key {
+		v, found := metadata[n]
+		return v, found
+	}
+	return "", false
+}
@@ -0,0 +1,671 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogMetadataBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		expected int
+	}{
+		{
+			name: "nil",
+		}, {
+			name:     "empty",
+			metadata: map[string]string{},
+		}, {
+			name:     "empty value",
+			metadata: map[string]string{"/key": ""},
+			expected: 4,
+		}, {
+			name: "several entries",
+			metadata: map[string]string{
+				"/boot-time":            "1700000000",
+				"/webpa-interface-used": "erouter0",
+				"/fw-name":              "fw-ünïcode",
+			},
+			expected: 10 + 10 + 21 + 8 + 8 + 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := LogMetadataBytes()
+			msg := wrp.Message{Metadata: tt.metadata}
+
+			// The map iteration order varies, the result must not.
+			for i := 0; i < 10; i++ {
+				assert.Equal(t, zap.Int(KeyMetadataBytes, tt.expected), opt(msg))
+			}
+		})
+	}
+}
+
+func TestLogMessageAge(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := newFakeClock(now)
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		expected map[string]any
+	}{
+		{
+			name:     "valid",
+			metadata: map[string]string{DefaultSendTimeMetadataKey: "1735787040000"},
+			expected: map[string]any{KeyMessageAge: int64(5000)},
+		}, {
+			name:     "whitespace",
+			metadata: map[string]string{DefaultSendTimeMetadataKey: " 1735787044500 "},
+			expected: map[string]any{KeyMessageAge: int64(500)},
+		}, {
+			name:     "from the future",
+			metadata: map[string]string{DefaultSendTimeMetadataKey: "1735787046000"},
+			expected: map[string]any{KeyMessageAge: int64(-1000)},
+		}, {
+			name: "missing",
+			expected: map[string]any{
+				KeyMessageAge:    int64(-1),
+				KeyMessageAgeRaw: "",
+			},
+		}, {
+			name:     "unparseable",
+			metadata: map[string]string{DefaultSendTimeMetadataKey: "yesterday"},
+			expected: map[string]any{
+				KeyMessageAge:    int64(-1),
+				KeyMessageAgeRaw: "yesterday",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := LogMessageAgeWithClock(DefaultSendTimeMetadataKey, clock)
+			assert.Equal(t, tt.expected, fieldMap(opt, wrp.Message{Metadata: tt.metadata}))
+		})
+	}
+}
+
+func TestLogMessageAge_ClockSteps(t *testing.T) {
+	clock := newFakeClock(time.UnixMilli(1000))
+	opt := LogMessageAgeWithClock("/sent", clock)
+	msg := wrp.Message{Metadata: map[string]string{"/sent": "1000"}}
+
+	assert.Equal(t, []zap.Field{zap.Int64(KeyMessageAge, 0)}, opt.AppendFields(msg, nil))
+	clock.Add(1500 * time.Millisecond)
+	assert.Equal(t, []zap.Field{zap.Int64(KeyMessageAge, 1500)}, opt.AppendFields(msg, nil))
+}
+
+func TestLogMessageAge_SystemClock(t *testing.T) {
+	sent := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	got := LogMessageAge("/sent").AppendFields(wrp.Message{Metadata: map[string]string{"/sent": sent}}, nil)
+	require.Len(t, got, 1)
+	assert.Equal(t, KeyMessageAge, got[0].Key)
+	assert.GreaterOrEqual(t, got[0].Integer, int64(0))
+}
+
+func TestLogMessageAge_ObserverClock(t *testing.T) {
+	clock := newFakeClock(time.UnixMilli(5000))
+	core, recorded := observer.New(zap.InfoLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithClock(clock),
+		WithFields(LogMessageAge("/sent")),
+	)
+	require.NoError(t, err)
+
+	msg := wrp.Message{Metadata: map[string]string{"/sent": "1000"}}
+	ob.ObserveWRP(context.Background(), msg)
+	clock.Add(time.Second)
+	ob.ObserveWRP(context.Background(), msg)
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(4000), entries[0].ContextMap()[KeyMessageAge])
+	assert.Equal(t, int64(5000), entries[1].ContextMap()[KeyMessageAge])
+}
+
+func TestLogUnknownMetadataKeys(t *testing.T) {
+	known := []string{"/boot-time", "/fw-name"}
+
+	tests := []struct {
+		name     string
+		opt      FieldOpt
+		metadata map[string]string
+		expected map[string]any
+	}{
+		{
+			name: "nil",
+			opt:  LogUnknownMetadataKeys(known...),
+			expected: map[string]any{
+				KeyUnknownMetadataKeyCount: 0,
+				KeyUnknownMetadataKeys:     []any{},
+			},
+		}, {
+			name:     "all known",
+			opt:      LogUnknownMetadataKeys(known...),
+			metadata: map[string]string{"/boot-time": "1", "/fw-name": "fw"},
+			expected: map[string]any{
+				KeyUnknownMetadataKeyCount: 0,
+				KeyUnknownMetadataKeys:     []any{},
+			},
+		}, {
+			name: "unknown keys are sorted",
+			opt:  LogUnknownMetadataKeys(known...),
+			metadata: map[string]string{
+				"/boot-time": "1",
+				"/zeta":      "z",
+				"/alpha":     "a",
+				"/mid":       "m",
+			},
+			expected: map[string]any{
+				KeyUnknownMetadataKeyCount: 3,
+				KeyUnknownMetadataKeys:     []any{"/alpha", "/mid", "/zeta"},
+			},
+		}, {
+			name:     "nothing known",
+			opt:      LogUnknownMetadataKeys(),
+			metadata: map[string]string{"/boot-time": "1"},
+			expected: map[string]any{
+				KeyUnknownMetadataKeyCount: 1,
+				KeyUnknownMetadataKeys:     []any{"/boot-time"},
+			},
+		}, {
+			name:     "at the cap",
+			opt:      LogUnknownMetadataKeysMax(2, known...),
+			metadata: map[string]string{"/a": "", "/b": ""},
+			expected: map[string]any{
+				KeyUnknownMetadataKeyCount: 2,
+				KeyUnknownMetadataKeys:     []any{"/a", "/b"},
+			},
+		}, {
+			name:     "over the cap",
+			opt:      LogUnknownMetadataKeysMax(2, known...),
+			metadata: map[string]string{"/a": "", "/b": "", "/c": ""},
+			expected: map[string]any{
+				KeyUnknownMetadataKeyCount: 3,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, fieldMap(tt.opt, wrp.Message{Metadata: tt.metadata}))
+		})
+	}
+}
+
+func TestLogMissingMetadataKeys(t *testing.T) {
+	required := []string{"/webpa-interface-used", "/boot-time", "/boot-time"}
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		expected map[string]any
+	}{
+		{
+			name: "nil metadata",
+			expected: map[string]any{
+				KeyHasMissingMetadataKeys: true,
+				KeyMissingMetadataKeys:    []any{"/boot-time", "/webpa-interface-used"},
+			},
+		}, {
+			name:     "one missing",
+			metadata: map[string]string{"/boot-time": "1700000000", "/other": "x"},
+			expected: map[string]any{
+				KeyHasMissingMetadataKeys: true,
+				KeyMissingMetadataKeys:    []any{"/webpa-interface-used"},
+			},
+		}, {
+			name:     "all present",
+			metadata: map[string]string{"/boot-time": "1700000000", "/webpa-interface-used": ""},
+			expected: map[string]any{
+				KeyHasMissingMetadataKeys: false,
+				KeyMissingMetadataKeys:    []any{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldMap(LogMissingMetadataKeys(required...), wrp.Message{Metadata: tt.metadata})
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	// Nothing required means nothing is missing.
+	assert.Equal(t, map[string]any{
+		KeyHasMissingMetadataKeys: false,
+		KeyMissingMetadataKeys:    []any{},
+	}, fieldMap(LogMissingMetadataKeys(), wrp.Message{}))
+
+	// Keys are matched exactly unless the Observer normalizes them.
+	assert.Equal(t, map[string]any{
+		KeyHasMissingMetadataKeys: true,
+		KeyMissingMetadataKeys:    []any{"/boot-time"},
+	}, fieldMap(LogMissingMetadataKeys("/boot-time"), wrp.Message{Metadata: map[string]string{"boot-time": "1"}}))
+}
+
+// tenMetadata returns metadata with ten entries, about what devices send.
+func tenMetadata() map[string]string {
+	metadata := make(map[string]string, 10)
+	for i := range 10 {
+		metadata["/key-"+strconv.Itoa(i)] = "value-" + strconv.Itoa(i)
+	}
+	return metadata
+}
+
+func TestLogMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		expected string
+	}{
+		{
+			name:     "nil",
+			expected: `{"metadata":{}}`,
+		}, {
+			name:     "sorted",
+			metadata: map[string]string{"/b": "2", "/a": "1", "/B": "3"},
+			expected: `{"metadata":{"/B":"3","/a":"1","/b":"2"}}`,
+		},
+	}
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := LogMetadata()(wrp.Message{Metadata: tt.metadata})
+			buf, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{field})
+			require.NoError(t, err)
+			defer buf.Free()
+
+			assert.Equal(t, tt.expected+"\n", buf.String())
+		})
+	}
+}
+
+func TestLogMetadata_Allocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector makes sync.Pool drop items")
+	}
+
+	msg := wrp.Message{Metadata: tenMetadata()}
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	opt := LogMetadata()
+	fields := make([]zap.Field, 1)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		fields[0] = opt(msg)
+		buf, err := enc.EncodeEntry(zapcore.Entry{}, fields)
+		if err == nil {
+			buf.Free()
+		}
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestLogUnknownMetadataKeys_Allocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector makes sync.Pool drop items")
+	}
+
+	msg := wrp.Message{Metadata: tenMetadata()}
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	opt := LogUnknownMetadataKeys("/key-0", "/key-1")
+	fields := make([]zap.Field, 0, 1)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		fields = opt.AppendFields(msg, fields[:0])
+		buf, err := enc.EncodeEntry(zapcore.Entry{}, fields)
+		if err == nil {
+			buf.Free()
+		}
+	})
+	assert.LessOrEqual(t, allocs, 1.0)
+}
+
+func BenchmarkLogMetadata(b *testing.B) {
+	msg := wrp.Message{Metadata: tenMetadata()}
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	fields := make([]zap.Field, 0, 1)
+
+	benchmarks := []struct {
+		name string
+		opt  FieldOpt
+	}{
+		{name: "LogMetadata", opt: LogMetadata()},
+		{name: "LogUnknownMetadataKeys", opt: LogUnknownMetadataKeys("/key-0", "/key-1")},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				fields = bm.opt.AppendFields(msg, fields[:0])
+				buf, err := enc.EncodeEntry(zapcore.Entry{}, fields)
+				if err != nil {
+					b.Fatal(err)
+				}
+				buf.Free()
+			}
+		})
+	}
+}
+
+func TestLogMetadata_Encoders(t *testing.T) {
+	metadata := map[string]string{
+		"/xmidt-send-time": "1700000000000",
+		"/Z":               "upper",
+		"/a":               "lower",
+		"/é":               "non-ascii",
+		"":                 "empty",
+		"/a/b":             "nested",
+	}
+	const sorted = `{"":"empty","/Z":"upper","/a":"lower","/a/b":"nested","/xmidt-send-time":"1700000000000","/é":"non-ascii"}`
+
+	tests := []struct {
+		name     string
+		enc      zapcore.Encoder
+		expected string
+	}{
+		{
+			name:     "json",
+			enc:      zapcore.NewJSONEncoder(zapcore.EncoderConfig{}),
+			expected: `{"metadata":` + sorted + "}\n",
+		}, {
+			name:     "console",
+			enc:      zapcore.NewConsoleEncoder(zapcore.EncoderConfig{}),
+			expected: `{"metadata": ` + strings.ReplaceAll(strings.ReplaceAll(sorted, `":"`, `": "`), `","`, `", "`) + "}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := LogMetadata()(wrp.Message{Metadata: metadata})
+
+			// Map iteration order changes between runs, so encode repeatedly.
+			for range 20 {
+				buf, err := tt.enc.EncodeEntry(zapcore.Entry{}, []zap.Field{field})
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, buf.String())
+				buf.Free()
+			}
+		})
+	}
+}
+
+// encodedKeys returns the keys of the JSON object, in the order they appear.
+func encodedKeys(t *testing.T, data []byte) []string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	require.NoError(t, err)
+	require.Equal(t, json.Delim('{'), tok)
+
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		require.NoError(t, err)
+		keys = append(keys, tok.(string))
+
+		var value string
+		require.NoError(t, dec.Decode(&value))
+	}
+	return keys
+}
+
+func TestLogMetadata_SortedProperty(t *testing.T) {
+	// The alphabet mixes cases, punctuation and multi-byte runes so that
+	// byte order and the order of the runes or of the lowercased keys differ.
+	alphabet := []rune("aAbBzZ/-_.09é€😀")
+	rng := rand.New(rand.NewPCG(1, 2))
+	randomString := func() string {
+		r := make([]rune, rng.IntN(8))
+		for i := range r {
+			r[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return string(r)
+	}
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	for i := 0; i < 500; i++ {
+		metadata := make(map[string]string)
+		for range rng.IntN(20) {
+			metadata[randomString()] = randomString()
+		}
+
+		obj := metadataObject(metadata)
+		first, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{zap.Object(KeyMetadata, obj)})
+		require.NoError(t, err)
+		second, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{zap.Object(KeyMetadata, obj)})
+		require.NoError(t, err)
+		require.Equal(t, first.String(), second.String(), "the encoding must be deterministic")
+
+		var entry map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(first.Bytes(), &entry))
+		keys := encodedKeys(t, entry[KeyMetadata])
+
+		require.Len(t, keys, len(metadata))
+		require.True(t, slices.IsSorted(keys), "keys must be in ascending byte order: %q", keys)
+
+		first.Free()
+		second.Free()
+	}
+}
+
+func TestNormalizeMetadataKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{key: "/fw-name", expected: "fw-name"},
+		{key: "fw-name", expected: "fw-name"},
+		{key: "/FW-Name", expected: "fw-name"},
+		{key: "//fw-name", expected: "/fw-name"},
+		{key: "/", expected: ""},
+		{key: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeMetadataKey(tt.key))
+		})
+	}
+}
+
+func TestNormalizeMetadata(t *testing.T) {
+	tests := []struct {
+		name       string
+		metadata   map[string]string
+		expected   map[string]string
+		collisions []string
+	}{
+		{name: "nil"},
+		{
+			name:     "normalized",
+			metadata: map[string]string{"fw-name": "1.0", "hw-model": "TG1682"},
+			expected: map[string]string{"fw-name": "1.0", "hw-model": "TG1682"},
+		}, {
+			name:     "mixed",
+			metadata: map[string]string{"/fw-name": "1.0", "HW-Model": "TG1682"},
+			expected: map[string]string{"fw-name": "1.0", "hw-model": "TG1682"},
+		}, {
+			name:       "slash is kept",
+			metadata:   map[string]string{"/fw-name": "slash", "fw-name": "bare"},
+			expected:   map[string]string{"fw-name": "slash"},
+			collisions: []string{"fw-name"},
+		}, {
+			name: "case",
+			metadata: map[string]string{
+				"/FW-Name": "upper",
+				"/fw-name": "lower",
+				"fw-name":  "bare",
+				"/a":       "a",
+				"A":        "A",
+			},
+			expected:   map[string]string{"fw-name": "upper", "a": "a"},
+			collisions: []string{"a", "fw-name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := maps.Clone(tt.metadata)
+
+			got, collisions := normalizeMetadata(tt.metadata)
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, tt.collisions, collisions)
+			assert.Equal(t, original, tt.metadata)
+		})
+	}
+}
+
+func TestWithNormalizedMetadataKeys(t *testing.T) {
+	clock := newFakeClock(time.UnixMilli(5000))
+	fields := []FieldOpt{
+		LogMetadata(),
+		LogMessageAgeWithClock(DefaultSendTimeMetadataKey, clock),
+		LogParentTransactionUUID(""),
+		LogMissingMetadataKeys("/fw-name", "/hw-model"),
+		LogUnknownMetadataKeys("/fw-name", DefaultSendTimeMetadataKey, DefaultParentTransactionUUIDMetadataKey),
+	}
+
+	tests := []struct {
+		name      string
+		normalize bool
+		metadata  map[string]string
+		expected  map[string]any
+	}{
+		{
+			name:      "older firmware",
+			normalize: true,
+			metadata: map[string]string{
+				"/FW-Name":                 "1.0",
+				"/xmidt-send-time":         "4000",
+				"/parent-transaction-uuid": "parent",
+			},
+			expected: map[string]any{
+				KeyMetadata:                map[string]any{"fw-name": "1.0", "xmidt-send-time": "4000", "parent-transaction-uuid": "parent"},
+				KeyMessageAge:              int64(1000),
+				KeyParentTransactionUUID:   "parent",
+				KeyHasMissingMetadataKeys:  true,
+				KeyMissingMetadataKeys:     []any{"/hw-model"},
+				KeyUnknownMetadataKeyCount: 0,
+				KeyUnknownMetadataKeys:     []any{},
+			},
+		}, {
+			name:      "newer firmware",
+			normalize: true,
+			metadata: map[string]string{
+				"fw-name":                 "2.0",
+				"hw-model":                "TG1682",
+				"xmidt-send-time":         "3000",
+				"parent-transaction-uuid": "parent",
+			},
+			expected: map[string]any{
+				KeyMetadata:                map[string]any{"fw-name": "2.0", "hw-model": "TG1682", "xmidt-send-time": "3000", "parent-transaction-uuid": "parent"},
+				KeyMessageAge:              int64(2000),
+				KeyParentTransactionUUID:   "parent",
+				KeyHasMissingMetadataKeys:  false,
+				KeyMissingMetadataKeys:     []any{},
+				KeyUnknownMetadataKeyCount: 1,
+				KeyUnknownMetadataKeys:     []any{"hw-model"},
+			},
+		}, {
+			name:      "collision",
+			normalize: true,
+			metadata: map[string]string{
+				"/fw-name":         "slash",
+				"fw-name":          "bare",
+				"/xmidt-send-time": "4000",
+			},
+			expected: map[string]any{
+				KeyMetadata:                map[string]any{"fw-name": "slash", "xmidt-send-time": "4000"},
+				KeyMessageAge:              int64(1000),
+				KeyParentTransactionUUID:   "",
+				KeyHasMissingMetadataKeys:  true,
+				KeyMissingMetadataKeys:     []any{"/hw-model"},
+				KeyUnknownMetadataKeyCount: 0,
+				KeyUnknownMetadataKeys:     []any{},
+				KeyMetadataKeyCollisions:   []any{"fw-name"},
+			},
+		}, {
+			name: "disabled",
+			metadata: map[string]string{
+				"/fw-name":         "slash",
+				"fw-name":          "bare",
+				"/xmidt-send-time": "4000",
+			},
+			expected: map[string]any{
+				KeyMetadata:                map[string]any{"/fw-name": "slash", "fw-name": "bare", "/xmidt-send-time": "4000"},
+				KeyMessageAge:              int64(1000),
+				KeyParentTransactionUUID:   "",
+				KeyHasMissingMetadataKeys:  true,
+				KeyMissingMetadataKeys:     []any{"/hw-model"},
+				KeyUnknownMetadataKeyCount: 1,
+				KeyUnknownMetadataKeys:     []any{"fw-name"},
+			},
+		}, {
+			// Without normalization the keys are matched exactly.
+			name: "disabled, newer firmware",
+			metadata: map[string]string{
+				"fw-name":                 "2.0",
+				"hw-model":                "TG1682",
+				"xmidt-send-time":         "3000",
+				"parent-transaction-uuid": "parent",
+			},
+			expected: map[string]any{
+				KeyMetadata:                map[string]any{"fw-name": "2.0", "hw-model": "TG1682", "xmidt-send-time": "3000", "parent-transaction-uuid": "parent"},
+				KeyMessageAge:              int64(-1),
+				KeyMessageAgeRaw:           "",
+				KeyParentTransactionUUID:   "",
+				KeyHasMissingMetadataKeys:  true,
+				KeyMissingMetadataKeys:     []any{"/fw-name", "/hw-model"},
+				KeyUnknownMetadataKeyCount: 4,
+				KeyUnknownMetadataKeys:     []any{"fw-name", "hw-model", "parent-transaction-uuid", "xmidt-send-time"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option{WithLevel(zap.InfoLevel), WithFields(fields...)}
+			if tt.normalize {
+				opts = append(opts, WithNormalizedMetadataKeys())
+			}
+
+			core, recorded := observer.New(zap.InfoLevel)
+			ob, err := NewObserver(zap.New(core), opts...)
+			require.NoError(t, err)
+
+			msg := wrp.Message{Metadata: tt.metadata}
+			original := maps.Clone(tt.metadata)
+			ob.ObserveWRP(context.Background(), msg)
+
+			entries := recorded.AllUntimed()
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.expected, entries[0].ContextMap())
+			assert.Equal(t, original, msg.Metadata)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap/zapcore"
+)
+
+// Metrics is told about the traffic an Observer sees, so it can be counted by
+// a metrics library without wrpzap depending on one.  The methods are called
+// synchronously, possibly from several goroutines at once, and so must be
+// safe for concurrent use and fast.
+type Metrics interface {
+	// MessageObserved is called for every message observed, whether or not
+	// it is logged, with the size of its payload.  Messages an AsyncObserver
+	// drops are not observed.
+	MessageObserved(msgType wrp.MessageType, qos wrp.QOSValue, payloadBytes int)
+
+	// EntryEmitted is called for every entry written, with its level.
+	EntryEmitted(level zapcore.Level)
+}
+
+// NopMetrics is a Metrics that does nothing.  It can be embedded by Metrics
+// implementations that only need some of the methods.
+type NopMetrics struct{}
+
+var _ Metrics = NopMetrics{}
+
+func (NopMetrics) MessageObserved(wrp.MessageType, wrp.QOSValue, int) {}
+
+func (NopMetrics) EntryEmitted(zapcore.Level) {}
+
+// observed tells Metrics about the message, when it is set.
+func (ob Observer) observed(msg *wrp.Message) {
+	if ob.Metrics != nil {
+		ob.Metrics.MessageObserved(msg.Type, msg.QualityOfService, len(msg.Payload))
+	}
+}
+
+// emitted tells Metrics about an entry written at the level, when it is set.
+func (ob Observer) emitted(level zapcore.Level) {
+	if ob.Metrics != nil {
+		ob.Metrics.EntryEmitted(level)
+	}
+}
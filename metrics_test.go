@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// recordingMetrics records the calls made to it.
+type recordingMetrics struct {
+	lock     sync.Mutex
+	messages []string
+	entries  []zapcore.Level
+}
+
+func (m *recordingMetrics) MessageObserved(msgType wrp.MessageType, qos wrp.QOSValue, payloadBytes int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.messages = append(m.messages, messageTypeName(msgType)+"/"+qos.Level().String()+"/"+strconv.Itoa(payloadBytes))
+}
+
+func (m *recordingMetrics) EntryEmitted(level zapcore.Level) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.entries = append(m.entries, level)
+}
+
+// countingMetrics counts the calls made to it without allocating.
+type countingMetrics struct {
+	messages atomic.Int64
+	entries  atomic.Int64
+}
+
+func (m *countingMetrics) MessageObserved(wrp.MessageType, wrp.QOSValue, int) { m.messages.Add(1) }
+func (m *countingMetrics) EntryEmitted(zapcore.Level)                         { m.entries.Add(1) }
+
+func TestObserver_Metrics(t *testing.T) {
+	event := wrp.Message{Type: wrp.SimpleEventMessageType, QualityOfService: wrp.QOSHighValue, Payload: []byte("ab")}
+	event0 := "SimpleEventMessageType/High/2"
+
+	tests := []struct {
+		name     string
+		disabled bool
+		opts     []Option
+		observe  func(ob Observer)
+		messages []string
+		entries  []zapcore.Level
+	}{
+		{
+			name: "observed and written",
+			observe: func(ob Observer) {
+				ob.ObserveWRP(context.Background(), event)
+			},
+			messages: []string{event0},
+			entries:  []zapcore.Level{zap.InfoLevel},
+		}, {
+			name: "detail entry",
+			opts: []Option{WithDebugFields(LogPayload())},
+			observe: func(ob Observer) {
+				ob.ObserveWRP(context.Background(), event)
+			},
+			messages: []string{event0},
+			entries:  []zapcore.Level{zap.InfoLevel, zap.DebugLevel},
+		}, {
+			name: "filtered",
+			opts: []Option{WithFilter(func(wrp.Message) bool { return false })},
+			observe: func(ob Observer) {
+				ob.ObserveWRP(context.Background(), event)
+			},
+			messages: []string{event0},
+		}, {
+			name:     "disabled",
+			disabled: true,
+			observe: func(ob Observer) {
+				ob.ObserveWRP(context.Background(), event)
+			},
+			messages: []string{event0},
+		}, {
+			name: "escalated",
+			opts: []Option{WithEscalations(EscalateOnRDRFailure())},
+			observe: func(ob Observer) {
+				ob.ObserveWRP(context.Background(), wrp.Message{RequestDeliveryResponse: int64p(6)})
+			},
+			messages: []string{"Invalid0MessageType/Low/0"},
+			entries:  []zapcore.Level{zap.ErrorLevel},
+		}, {
+			name: "batch",
+			observe: func(ob Observer) {
+				ob.ObserveWRPBatch(context.Background(), []wrp.Message{event, event})
+			},
+			messages: []string{event0, event0},
+			entries:  []zapcore.Level{zap.InfoLevel},
+		}, {
+			name: "conversion error",
+			observe: func(ob Observer) {
+				ob.ObserveConvertible(context.Background(), broken{})
+			},
+			entries: []zapcore.Level{zap.ErrorLevel},
+		}, {
+			name: "delta",
+			observe: func(ob Observer) {
+				d, err := NewDeltaObserver(ob, 1)
+				require.NoError(t, err)
+				d.ObserveWRP(context.Background(), event)
+			},
+			messages: []string{event0},
+			entries:  []zapcore.Level{zap.InfoLevel},
+		}, {
+			name: "top n",
+			observe: func(ob Observer) {
+				top, err := NewTopNObserver(ob, 1, 1, time.Hour)
+				require.NoError(t, err)
+				top.ObserveWRP(context.Background(), event)
+				top.ObserveWRP(context.Background(), event)
+				require.NoError(t, top.Close(context.Background()))
+			},
+			messages: []string{event0, event0},
+			entries:  []zapcore.Level{zap.InfoLevel},
+		}, {
+			name: "async",
+			observe: func(ob Observer) {
+				a, err := NewAsyncObserver(ob, 1)
+				require.NoError(t, err)
+				a.ObserveWRP(context.Background(), event)
+				require.NoError(t, a.Close(context.Background()))
+			},
+			messages: []string{event0},
+			entries:  []zapcore.Level{zap.InfoLevel},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level := zap.DebugLevel
+			if tt.disabled {
+				level = zap.WarnLevel
+			}
+			core, recorded := observer.New(level)
+			m := new(recordingMetrics)
+			opts := append([]Option{
+				WithLevel(zap.InfoLevel),
+				WithFields(LogSource()),
+				WithMetrics(m),
+			}, tt.opts...)
+			ob, err := NewObserver(zap.New(core), opts...)
+			require.NoError(t, err)
+
+			tt.observe(ob)
+
+			assert.Equal(t, tt.messages, m.messages)
+			assert.Equal(t, tt.entries, m.entries)
+			assert.Len(t, recorded.AllUntimed(), len(tt.entries))
+		})
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	_, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithMetrics(nil))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	ob, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithMetrics(NopMetrics{}))
+	require.NoError(t, err)
+	assert.Contains(t, ob.String(), " metrics=true")
+	assert.NotPanics(t, func() {
+		ob.ObserveWRP(context.Background(), wrp.Message{})
+	})
+}
+
+func TestObserver_Metrics_Allocations(t *testing.T) {
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), zapcore.AddSync(io.Discard), zap.DebugLevel)
+	msg := wrp.Message{Type: wrp.SimpleEventMessageType, Source: "mac:112233445566", Payload: []byte("abc")}
+
+	observe := func(metrics Metrics) float64 {
+		ob := Observer{Logger: zap.New(core), Fields: []FieldOpt{LogSource()}, DebugFields: []FieldOpt{LogPayloadSize()}, Metrics: metrics}
+		return testing.AllocsPerRun(100, func() { ob.ObserveWRPPtr(context.Background(), &msg) })
+	}
+
+	m := new(countingMetrics)
+	assert.Equal(t, observe(nil), observe(m))
+	assert.Equal(t, int64(101), m.messages.Load())
+	assert.Equal(t, int64(202), m.entries.Load())
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"errors"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+// NewProcessorMiddleware returns middleware that logs each message after the
+// next Processor in the chain handles it.  Along with the Observer's fields,
+// the entry has how long the next Processor took as duration, whether it
+// returned wrp.ErrNotHandled as not_handled, and any other error it returned
+// as error.  The next Processor's error is returned as it is.
+//
+// When next is nil the Processor only logs the message, and returns
+// wrp.ErrNotHandled like wrp.ObserverAsProcessor.
+func NewProcessorMiddleware(ob Observer) func(next wrp.Processor) wrp.Processor {
+	return func(next wrp.Processor) wrp.Processor {
+		return wrp.ProcessorFunc(func(ctx context.Context, msg wrp.Message) error {
+			if next == nil {
+				ob.observe(&msg, []zap.Field{zap.Bool(fNotHandled, true)})
+				return wrp.ErrNotHandled
+			}
+
+			clock := ob.clock()
+			start := clock.Now()
+			err := next.ProcessWRP(ctx, msg)
+			duration := clock.Now().Sub(start)
+
+			notHandled := errors.Is(err, wrp.ErrNotHandled)
+			extra := []zap.Field{
+				zap.Duration(fDuration, duration),
+				zap.Bool(fNotHandled, notHandled),
+			}
+			if err != nil && !notHandled {
+				extra = append(extra, zap.Error(err))
+			}
+
+			ob.observe(&msg, extra)
+			return err
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewProcessorMiddleware(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name       string
+		err        error
+		notHandled bool
+	}{
+		{name: "handled"},
+		{name: "not handled", err: wrp.ErrNotHandled, notHandled: true},
+		{name: "wrapped not handled", err: fmt.Errorf("skip: %w", wrp.ErrNotHandled), notHandled: true},
+		{name: "error", err: errFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zapcore.DebugLevel)
+			clock := newFakeClock(time.UnixMilli(1000))
+			ob := Observer{
+				Logger:  zap.New(core),
+				Message: "wrp processed",
+				Fields:  []FieldOpt{LogSource()},
+				Clock:   clock,
+			}
+
+			var order []string
+			mw := NewProcessorMiddleware(ob)
+
+			// A three element chain: the logging middleware, a processor
+			// that delegates, and the terminal processor.
+			terminal := wrp.ProcessorFunc(func(context.Context, wrp.Message) error {
+				order = append(order, "terminal")
+				clock.Add(5 * time.Millisecond)
+				return tt.err
+			})
+			middle := wrp.ProcessorFunc(func(ctx context.Context, msg wrp.Message) error {
+				order = append(order, "middle")
+				return terminal.ProcessWRP(ctx, msg)
+			})
+			chain := mw(middle)
+
+			err := chain.ProcessWRP(context.Background(), wrp.Message{Source: "mac:112233445566"})
+			assert.True(t, err == tt.err, "the error must be returned as it is")
+			assert.Equal(t, []string{"middle", "terminal"}, order)
+
+			entries := recorded.AllUntimed()
+			require.Len(t, entries, 1)
+
+			fields := entries[0].ContextMap()
+			assert.Equal(t, "mac:112233445566", fields[KeySource])
+			assert.Equal(t, tt.notHandled, fields[KeyNotHandled])
+			assert.Equal(t, 5*time.Millisecond, fields[KeyDuration])
+
+			if tt.err != nil && !tt.notHandled {
+				assert.Equal(t, tt.err.Error(), fields["error"])
+			} else {
+				assert.NotContains(t, fields, "error")
+			}
+		})
+	}
+}
+
+func TestNewProcessorMiddleware_Nested(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	outer := NewProcessorMiddleware(Observer{Logger: zap.New(core), Message: "outer"})
+	inner := NewProcessorMiddleware(Observer{Logger: zap.New(core), Message: "inner"})
+
+	errFailed := errors.New("failed")
+	chain := outer(inner(wrp.ProcessorFunc(func(context.Context, wrp.Message) error {
+		return errFailed
+	})))
+
+	err := chain.ProcessWRP(context.Background(), wrp.Message{})
+	assert.ErrorIs(t, err, errFailed)
+
+	// Each middleware logs after the rest of the chain is done.
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "inner", entries[0].Message)
+	assert.Equal(t, "outer", entries[1].Message)
+}
+
+func TestNewProcessorMiddleware_NilNext(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	p := NewProcessorMiddleware(Observer{Logger: zap.New(core)})(nil)
+
+	err := p.ProcessWRP(context.Background(), wrp.Message{})
+	assert.ErrorIs(t, err, wrp.ErrNotHandled)
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, true, entries[0].ContextMap()[KeyNotHandled])
+}
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+// NamedFieldOpt is a FieldOpt that knows its own name and the key of the
+// field it logs.  Every FieldOpt is a NamedFieldOpt: the built-in FieldOpts
+// are named by their constructors, those returned by ParseFieldNames after
+// their registration, and any other FieldOpt can be named with Named.
+type NamedFieldOpt interface {
+	FieldAppender
+
+	// Name is the name used to refer to the FieldOpt in configuration, or
+	// the name of the function that created it when it has none.
+	Name() string
+
+	// Key is the key of the field the FieldOpt logs, with the keys of
+	// FieldOpts logging more than one field separated by commas.  It is
+	// empty when the key is not known.
+	Key() string
+}
+
+var _ NamedFieldOpt = FieldOpt(nil)
+
+// optInfo is what is known about a FieldOpt created by Named or Appender: its
+// name and key and, for Appender's, the marker the Observer evaluates in place
+// of calling it.
+type optInfo struct {
+	name   string
+	key    string
+	marker fieldMarker
+
+	// seq tells the optInfos of FieldOpts sharing an address apart.
+	seq uint64
+}
+
+// optState is the state of a FieldOpt created by Named or Appender.  The
+// FieldOpt holds on to it, so it lives as long as the FieldOpt does, and its
+// finalizer removes the FieldOpt's optInfo.
+type optState struct {
+	opt    FieldOpt
+	marker fieldMarker
+
+	id  uintptr
+	seq uint64
+}
+
+var (
+	// optInfos holds the optInfo of the FieldOpts created by Named and
+	// Appender by their funcID, so they are described and evaluated without
+	// calling them.  It doesn't refer to the FieldOpts, so an entry doesn't
+	// keep its FieldOpt alive.
+	optInfos sync.Map
+
+	optSeq atomic.Uint64
+)
+
+// funcID identifies a func value by the address of the closure it refers to,
+// which differs for every FieldOpt Named and Appender return.
+func funcID(opt FieldOpt) uintptr {
+	return uintptr(*(*unsafe.Pointer)(unsafe.Pointer(&opt)))
+}
+
+// newOpt records the optInfo of fn, a closure holding on to s.
+func newOpt(s *optState, info optInfo, fn FieldOpt) FieldOpt {
+	s.id = funcID(fn)
+	s.seq = optSeq.Add(1)
+	info.seq = s.seq
+	optInfos.Store(s.id, info)
+	runtime.SetFinalizer(s, forgetOpt)
+	return fn
+}
+
+// forgetOpt removes the optInfo of a FieldOpt that was garbage collected.  Its
+// closure's address may already belong to a newer FieldOpt, whose optInfo is
+// kept.
+func forgetOpt(s *optState) {
+	if v, ok := optInfos.Load(s.id); ok && v.(optInfo).seq == s.seq {
+		optInfos.CompareAndDelete(s.id, v)
+	}
+}
+
+// lookupOpt returns the optInfo of a FieldOpt created by Named or Appender.
+// The code of the FieldOpt is checked first, so a FieldOpt that reuses the
+// address of a collected one is never mistaken for it.
+func lookupOpt(opt FieldOpt) (optInfo, bool) {
+	if opt == nil {
+		return optInfo{}, false
+	}
+	if code := reflect.ValueOf(opt).Pointer(); code != namedCode && code != markedCode {
+		return optInfo{}, false
+	}
+
+	v, ok := optInfos.Load(funcID(opt))
+	if !ok {
+		return optInfo{}, false
+	}
+	return v.(optInfo), true
+}
+
+// skip is the FieldOpt of a nil FieldOpt given to Named.
+func skip(wrp.Message) zap.Field {
+	return zap.Skip()
+}
+
+// Named names a FieldOpt, so Name and Key report the name and key and the
+// Observer describes it by the name.  The returned FieldOpt logs the same
+// fields as opt, whether it is called directly or by an Observer.  A nil opt
+// logs nothing.
+func Named(name, key string, opt FieldOpt) FieldOpt {
+	// A FieldOpt from Appender stays one, so the Observer still evaluates
+	// its marker.
+	if info, ok := lookupOpt(opt); ok && info.marker != nil {
+		return marked(name, key, info.marker)
+	}
+
+	if opt == nil {
+		opt = skip
+	}
+	return named(name, key, opt)
+}
+
+// named returns a FieldOpt that calls opt, recording the name and key.
+func named(name, key string, opt FieldOpt) FieldOpt {
+	s := &optState{opt: opt}
+	return newOpt(s, optInfo{name: name, key: key}, func(msg wrp.Message) zap.Field {
+		return s.opt(msg)
+	})
+}
+
+// namedCode is the code of the FieldOpts created by named.
+var namedCode = reflect.ValueOf(named("", "", skip)).Pointer()
+
+// Name returns the FieldOpt's name: the name given to Named, which names
+// every built-in FieldOpt and those returned by ParseFieldNames, or, failing
+// that, the name of the function that created it.  A nil FieldOpt is named
+// nil.
+func (opt FieldOpt) Name() string {
+	return fieldOptName(opt)
+}
+
+// Key returns the key of the field the FieldOpt logs, as given to Named.  An
+// empty string is returned for other FieldOpts.
+func (opt FieldOpt) Key() string {
+	if info, ok := lookupOpt(opt); ok {
+		return info.key
+	}
+	return ""
+}
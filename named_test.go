@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFieldOpt_Name(t *testing.T) {
+	custom := func(wrp.Message) zap.Field {
+		return zap.String("custom", "value")
+	}
+
+	tests := []struct {
+		name        string
+		opt         FieldOpt
+		expectedKey string
+		expected    string
+	}{
+		{name: "builtin", opt: LogSource(), expected: "source", expectedKey: KeySource},
+		{
+			name:        "appender",
+			opt:         LogLocatorSchemes(),
+			expected:    "locator_schemes",
+			expectedKey: KeySourceScheme + "," + KeyDestinationScheme,
+		},
+		{name: "with parameters", opt: LogHeader("X-Test"), expected: "header", expectedKey: "X-Test"},
+		{
+			name:        "with clock",
+			opt:         LogMessageAgeWithClock("/boot-time", ClockFunc(time.Now)),
+			expected:    "message_age",
+			expectedKey: KeyMessageAge + "," + KeyMessageAgeRaw,
+		},
+		{name: "at level", opt: FieldOptAtLevel(zap.DebugLevel, LogPayload()), expected: "payload", expectedKey: KeyPayload},
+		{name: "conditional", opt: LogIf(nil, LogHeader("X-Test")), expected: "header", expectedKey: "X-Test"},
+		{name: "function", opt: custom, expected: "wrpzap.TestFieldOpt_Name"},
+		{name: "nil", expected: "nil"},
+		{name: "named", opt: Named("custom", "custom", custom), expected: "custom", expectedKey: "custom"},
+		{name: "named builtin", opt: Named("from", KeySource, LogSource()), expected: "from", expectedKey: KeySource},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.opt.Name())
+			assert.Equal(t, tt.expectedKey, tt.opt.Key())
+		})
+	}
+}
+
+func TestFieldOpt_Name_Registered(t *testing.T) {
+	for _, desc := range AvailableFields() {
+		t.Run(desc.Name, func(t *testing.T) {
+			opts, err := ParseFieldNames([]string{desc.Name})
+			require.NoError(t, err)
+			require.Len(t, opts, 1)
+
+			var opt NamedFieldOpt = opts[0]
+			assert.Equal(t, desc.Name, opt.Name())
+			assert.Equal(t, desc.Key, opt.Key())
+		})
+	}
+}
+
+func TestNamed(t *testing.T) {
+	msg := wrp.Message{
+		Source:      "mac:112233445566",
+		Destination: "event:device-status",
+	}
+
+	tests := []struct {
+		name     string
+		opt      FieldOpt
+		expected map[string]any
+	}{
+		{
+			name:     "field",
+			opt:      Named("from", KeySource, LogSource()),
+			expected: map[string]any{KeySource: msg.Source},
+		}, {
+			name: "appender",
+			opt:  Named("schemes", KeySourceScheme+","+KeyDestinationScheme, LogLocatorSchemes()),
+			expected: map[string]any{
+				KeySourceScheme:      "mac",
+				KeyDestinationScheme: "event",
+			},
+		}, {
+			name:     "nil",
+			opt:      Named("nothing", "", nil),
+			expected: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, fieldMap(tt.opt, msg))
+
+			core, recorded := observer.New(zap.InfoLevel)
+			ob, err := NewObserver(zap.New(core), WithLevel(zap.InfoLevel), WithFields(tt.opt))
+			require.NoError(t, err)
+			ob.ObserveWRP(context.Background(), msg)
+
+			entries := recorded.AllUntimed()
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.expected, entries[0].ContextMap())
+			assert.Contains(t, ob.String(), tt.opt.Name())
+		})
+	}
+}
+
+func TestNamed_Direct(t *testing.T) {
+	msg := wrp.Message{Source: "mac:112233445566"}
+
+	assert.Equal(t, zap.String(KeySource, msg.Source), Named("from", KeySource, LogSource())(msg))
+	assert.Equal(t, zap.String(KeySource, msg.Source), LogSource()(msg))
+	assert.Equal(t, zap.Skip(), Named("nothing", "", nil)(msg))
+}
+
+func TestNamed_Collected(t *testing.T) {
+	entries := func() (n int) {
+		optInfos.Range(func(any, any) bool {
+			n++
+			return true
+		})
+		return n
+	}
+
+	kept := Named("kept", "kept", LogSource())
+	before := entries()
+	for i := 0; i < 100; i++ {
+		Named("dropped", "dropped", LogSource())
+	}
+	require.GreaterOrEqual(t, entries(), before+100)
+
+	// The entries of the FieldOpts no longer used go away, and the names of
+	// the ones still used stay.
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		return entries() <= before
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "kept", kept.Name())
+	assert.Equal(t, "kept", kept.Key())
+}
@@ -14,172 +14,764 @@ package wrpzap
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/xmidt-org/wrp-go/v3"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+var (
+	// ErrInvalidInput is returned when an invalid value is provided.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrNoFields is returned by Validate when an Observer has no Fields.
+	ErrNoFields = errors.New("no fields configured")
+
+	// ErrNoLevel is returned by NewObserver and Validate when the level the
+	// entries are logged at was not set.
+	ErrNoLevel = errors.New("no level configured")
+
+	// ErrClosed is reported when a message is observed by an observer that
+	// has been closed.
+	ErrClosed = errors.New("observer closed")
+
+	// ErrDropped is reported when an observer drops a message because it
+	// has no room for it.
+	ErrDropped = errors.New("message dropped")
+)
+
+// DefaultLevel is the level an Observer without a Level logs at.  Validate
+// and NewObserver report an Observer without a Level, so only an Observer
+// that skipped them logs at DefaultLevel.
+const DefaultLevel = zapcore.InfoLevel
+
+// AtLevel returns a pointer to the level, for setting the Level of an
+// Observer declared as a struct literal.
+//
+//	ob := wrpzap.Observer{Logger: logger, Level: wrpzap.AtLevel(zap.DebugLevel)}
+func AtLevel(level zapcore.Level) *zapcore.Level {
+	return &level
+}
+
 // Observer logs information about the message being processed and sends the
 // message to the next handler in the chain.
 type Observer struct {
-	Logger  *zap.Logger
-	Level   zapcore.Level
+	Logger *zap.Logger
+
+	// Level is the level the entries are logged at.  It must be set: the zero
+	// value of zapcore.Level is Info, so a plain level would make a forgotten
+	// level indistinguishable from Info.  See AtLevel and DefaultLevel.
+	Level *zapcore.Level
+
+	// AtomicLevel, when set, is used instead of Level, so the level the
+	// entries are logged at follows the atomic level as it is changed, and
+	// setting it to Debug makes the entries come out at Debug.  The level is
+	// read as each message is observed.
+	//
+	// The level of an entry is chosen in order: AtomicLevel if set, otherwise
+	// Level, otherwise DefaultLevel, and then the Escalations, which can only
+	// raise it.  The atomic level is usually also the level of the logger, so
+	// an entry at the atomic level is never gated out by it.
+	AtomicLevel *zap.AtomicLevel
+
 	Message string
-	Fields  []FieldOpt
+
+	// Fields are the FieldOpts logged for each message.  An Observer created
+	// by NewObserver has its own copy of them, as of DebugFields and
+	// Escalations, so changing the slices passed to the options afterwards
+	// changes nothing.  An Observer declared as a struct literal uses the
+	// slices as they are, so they must not be changed while it is in use.
+	Fields []FieldOpt
+
+	// UseDefaultFields logs the DefaultFields when Fields is empty, instead of
+	// logging entries with only the message text.  Use NoFields to log no
+	// fields when UseDefaultFields is set.
+	UseDefaultFields bool
+
+	// Messages is the entry's message text for each message type.  Types
+	// that aren't in the map use Message.  MessageFunc, when set, takes
+	// precedence over both.
+	Messages map[wrp.MessageType]string
+
+	// MessageFunc, when set, produces the entry's message text for each
+	// message instead of the static Message.  See ParseMessageTemplate.
+	MessageFunc func(wrp.Message) string
+
+	// Filter, when set, chooses the messages that are logged; messages it
+	// returns false for are not logged.  See FilterMetadataKeyPresent for
+	// some of the filters provided.
+	Filter func(wrp.Message) bool
+
+	// SampleByType appends the message type's name to the entry's message
+	// text, as in "wrp received SimpleEvent".  zap's sampler counts entries
+	// with the same level and message text together, so without this a burst
+	// of one type of message can leave no entries for the others.  With it
+	// each type is sampled separately.
+	SampleByType bool
+
+	// DebugFields are logged in a second, Debug level entry when the logger
+	// enables Debug.  The second entry shares the Message, carries the
+	// transaction UUID so the two entries can be tied together, and is marked
+	// with detail=true.  This keeps bulky fields like the payload out of the
+	// primary entry.
+	DebugFields []FieldOpt
+
+	// CanonicalizeLocators lowercases the scheme and authority of the Source
+	// and Destination before any FieldOpt sees the message, so that
+	// "MAC:AABBCCDDEEFF" and "mac:aabbccddeeff" are logged the same.  The
+	// service and any trailing path are left untouched; see CanonicalLocator.
+	//
+	// The FieldOpts that derive values from the locators, like the schemes,
+	// always log the lowercase scheme.  By default LogSource and
+	// LogDestination log the locators exactly as received; with
+	// CanonicalizeLocators set they log the canonical form as well.
+	CanonicalizeLocators bool
+
+	// NormalizeMetadataKeys removes a single leading slash from the metadata
+	// keys and lowercases them before any FieldOpt sees the message, so that
+	// "/fw-name" and "fw-name" are logged the same; see NormalizeMetadataKey.
+	// When a message has more than one key that normalize to the same key,
+	// the value of a key with the leading slash is logged, and the colliding
+	// keys are logged in their normalized form as metadata_key_collisions.
+	//
+	// The FieldOpts configured with metadata keys, like LogMessageAge, find
+	// the keys in their normalized form too, so they don't need to change.
+	// Otherwise they match the keys exactly.  Filters and Escalations see
+	// the metadata as received.
+	NormalizeMetadataKeys bool
+
+	// Escalations raise the level of the entries of some messages above
+	// Level, for example to log failed deliveries as errors.  The entry is
+	// logged at the highest level any of them choose.  The Debug detail
+	// entry is not escalated.
+	Escalations []Escalation
+
+	// StackOnError adds a stacktrace field to the entries logged at Error
+	// level or above, showing where the message was observed.  The stack
+	// starts at the caller of the Observer.  Entries at lower levels don't
+	// pay for capturing the stack.
+	StackOnError bool
+
+	// KeepLast retains a copy of the most recently observed message so it can
+	// be inspected with LastObserved.  KeepLast requires the Observer to be
+	// created by NewObserver.
+	KeepLast bool
+
+	// SequenceNumbers adds a seq field to every entry the Observer writes,
+	// numbering them 1, 2, 3 and so on, so entries with the same timestamp
+	// can be put in order.  The numbers are taken when the logger accepts an
+	// entry, so messages that are filtered, below the logger's level or
+	// sampled out don't use up numbers.  Entries written concurrently may
+	// reach the output in a different order than their numbers.  After
+	// 2^64-1 the numbers wrap around to 0.  SequenceNumbers requires the
+	// Observer to be created by NewObserver.
+	SequenceNumbers bool
+
+	// TypeCounters adds a msg_type_count field to the primary entries,
+	// counting the messages of each type the Observer has observed: the
+	// first simple event is 1, the second 2 and so on.  Every message passed
+	// to the Observer is counted, whether or not it is logged, so gaps in
+	// the counts show messages that were filtered or sampled out.  Message
+	// types wrp doesn't define share a single count.  After 2^64-1 the counts
+	// wrap around to 0.  TypeCounters requires the Observer to be created by
+	// NewObserver.
+	TypeCounters bool
+
+	// BatchCap is the largest batch ObserveWRPBatch logs each message of.
+	// Larger batches are logged with only the summary fields.  Zero means
+	// there is no limit.
+	BatchCap int
+
+	// Clock provides the time for the Observer's time based behavior, like
+	// the time LastObserved reports, the duration NewProcessorMiddleware
+	// logs and the age LogMessageAge logs.  The system clock is used when it
+	// is nil.  A Clock that also implements Timers provides the timers and
+	// tickers too.
+	Clock Clock
+
+	// OnError is called with the errors the Observer and the observers
+	// wrapping it run into that are not about a particular message, like a
+	// message dropped because the observer was closed or its buffer was
+	// full.  When it is nil the
+	// errors are logged at Warn level, at most one entry every
+	// ErrorLogInterval per Observer.
+	OnError func(error)
+
+	// Correlation, when set, remembers the last transactions each device
+	// sent, see CorrelationIndex.  Every message passed to the Observer is
+	// indexed, whether or not it is logged.
+	Correlation *CorrelationIndex
+
+	// EntryBudget is the largest size, in bytes, of the entries logged for a
+	// message.  When the estimated size of an entry is over it, the fields
+	// with the lowest priority are dropped until it fits, and their keys
+	// are logged as fields_dropped, in the order they were dropped.  Zero
+	// means there is no budget.
+	//
+	// The size is an estimate of the entry encoded as JSON: strings are
+	// counted without escaping, numbers, booleans and times at their longest,
+	// other fields are encoded to measure them, and EntryOverhead bytes are
+	// set aside for the rest of the entry.  The stacktrace of StackOnError is
+	// not counted.
+	EntryBudget int
+
+	// FieldPriorities sets the priorities of the fields with the keys, which
+	// decide the order fields are dropped in to keep an entry within the
+	// EntryBudget.  The routing fields are PriorityHigh, the payload
+	// PriorityLow and the other fields PriorityNormal unless they are set
+	// here.
+	FieldPriorities map[string]Priority
+
+	// FieldErrors logs the errors the FieldOpts report with FieldError, like
+	// a locator or a timestamp that couldn't be parsed, as field_errors, an
+	// array of {"key": ..., "error": ...} objects appended to the entry.
+	// Without it the errors are left out.
+	FieldErrors bool
+
+	// LogEncodedSize logs the size of the encoded message as encoded_size
+	// with the entries of ObserveEncoded.
+	LogEncodedSize bool
+
+	// Tee, when set, is called with the message and the fields of each
+	// primary entry after the entry is written, so another consumer can use
+	// the fields without computing them again.  It isn't called for messages
+	// that weren't logged, whether they were filtered, below the logger's
+	// level or sampled out, nor for the Debug detail entry.
+	//
+	// The field slice is a copy that belongs to Tee, so changing it can't
+	// change what the logger received.  The values in the fields, like the
+	// message passed along, may still refer to the message's memory and must
+	// not be changed.
+	Tee func(msg wrp.Message, fields []zap.Field)
+
+	// Metrics, when set, is told about each message observed and each entry
+	// written.  It is also used by the observers wrapping the Observer.
+	Metrics Metrics
+
+	// PartnerOverrides replaces Fields for the messages of some partners,
+	// for partners with their own logging contract.  The keys are partner
+	// IDs normalized by NormalizePartnerID, which Validate checks; see
+	// WithPartnerOverrides.
+	PartnerOverrides map[string][]FieldOpt
+
+	// accounting is the encoder used to measure the encoded size of entries.
+	accounting zapcore.Encoder
+
+	// state is shared by copies of an Observer created by NewObserver.
+	state *observerState
+}
+
+// observerState holds the mutable state of an Observer.
+type observerState struct {
+	last   lastObserved
+	stats  stats
+	errors errorLimiter
+	seq    atomic.Uint64
+	types  typeCounters
 }
 
-// ObserveWRP logs information about the message being processed.
+// NewObserver creates an Observer that logs to the provided logger.  Unlike
+// an Observer declared as a struct literal, an Observer created by
+// NewObserver can hold state, which is shared by all copies of it.  The
+// level must be set with WithLevel; ErrNoLevel is returned otherwise.
+func NewObserver(logger *zap.Logger, opts ...Option) (Observer, error) {
+	if logger == nil {
+		return Observer{}, fmt.Errorf("%w: logger is nil", ErrInvalidInput)
+	}
+
+	ob := Observer{
+		Logger: logger,
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt.apply(&ob); err != nil {
+			return Observer{}, fmt.Errorf("%s: %w", optionName(opt), err)
+		}
+	}
+
+	if ob.Level == nil && ob.AtomicLevel == nil {
+		return Observer{}, fmt.Errorf("%w: use WithLevel or WithAtomicLevel", ErrNoLevel)
+	}
+
+	// The slices are snapshots, whatever the options did with the callers'
+	// slices.
+	ob.Fields = slices.Clone(ob.Fields)
+	ob.DebugFields = slices.Clone(ob.DebugFields)
+	ob.Escalations = slices.Clone(ob.Escalations)
+
+	ob.state = &observerState{}
+
+	if ob.accounting != nil {
+		ob.Logger = ob.Logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return &accountingCore{
+				Core:  core,
+				enc:   ob.accounting,
+				stats: &ob.state.stats,
+			}
+		}))
+	}
+
+	return ob, nil
+}
+
+// MustNewObserver is NewObserver, panicking if the Observer can't be created.
+// It is meant for wiring done at startup, where a misconfigured Observer
+// should stop the program.  The panic names the option that failed.
+func MustNewObserver(logger *zap.Logger, opts ...Option) Observer {
+	ob, err := NewObserver(logger, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return ob
+}
+
+// Validate checks the Observer's configuration.  An Observer without Fields is
+// reported so that logging entries with only the message text is a conscious
+// choice, made by setting UseDefaultFields or using NoFields.  An Observer
+// without a Level is reported with ErrNoLevel.  PartnerOverrides keys that
+// are not normalized by NormalizePartnerID are ErrInvalidInput, since they
+// would never match.
+func (ob Observer) Validate() error {
+	if ob.Logger == nil {
+		return fmt.Errorf("%w: logger is nil", ErrInvalidInput)
+	}
+
+	if ob.Level == nil && ob.AtomicLevel == nil {
+		return ErrNoLevel
+	}
+
+	if len(ob.Fields) == 0 && !ob.UseDefaultFields {
+		return ErrNoFields
+	}
+
+	return ob.validatePartnerOverrides()
+}
+
+// ObserveWRP logs information about the message being processed.  It is the
+// same as ObserveWRPPtr.
 func (ob Observer) ObserveWRP(_ context.Context, msg wrp.Message) {
+	ob.observe(&msg, nil)
+}
+
+// ObserveWRPPtr logs information about the message being processed, without
+// copying the message to call the Observer.  A nil message is ignored.
+func (ob Observer) ObserveWRPPtr(_ context.Context, msg *wrp.Message) {
+	if msg == nil {
+		return
+	}
+
+	ob.observe(msg, nil)
+}
+
+// ObserveWRPWith logs information about the message being processed, with
+// the extra fields appended to the primary entry after the configured
+// fields.  It is for what the caller knows about the message that a FieldOpt
+// can't, like how long decoding it took:
+//
+//	ob.ObserveWRPWith(ctx, msg, zap.Duration("decode_time", elapsed))
+//
+// The extra fields only apply to this call.  They are not added to the Debug
+// detail entry.
+func (ob Observer) ObserveWRPWith(_ context.Context, msg wrp.Message, extra ...zap.Field) {
+	ob.observe(&msg, extra)
+}
+
+// observe logs the message, with the extra fields appended to the primary
+// entry.  It must be called directly by the exported methods so the stack
+// logged by StackOnError starts at their caller.
+//
+// The message is passed by pointer so that it is only copied to call the
+// functions the Observer is configured with, and must not be changed.
+func (ob Observer) observe(msg *wrp.Message, extra []zap.Field) {
 	if ob.Logger == nil {
 		return
 	}
 
-	fields := make([]zap.Field, 0, len(ob.Fields))
-	for _, field := range ob.Fields {
-		fields = append(fields, field(msg))
+	count, counted := ob.record(msg)
+
+	if ob.Filter != nil && !ob.Filter(*msg) {
+		return
 	}
 
-	ob.Logger.Log(ob.Level, ob.Message, fields...)
+	text := ob.message(msg)
+
+	// Checking first means the fields are only built when the entry is going
+	// to be written.
+	level := ob.level(msg)
+	if ce := ob.Logger.Check(level, text); ce != nil {
+		fields := ob.fitBudget(text, append(ob.fields(ob.fieldOpts(msg), msg), extra...))
+		if counted {
+			fields = append(fields, count)
+		}
+		if ob.StackOnError && level >= zapcore.ErrorLevel {
+			fields = append(fields, zap.StackSkip(fStacktrace, 2))
+		}
+		fields = ob.withSeq(fields...)
+		ce.Write(fields...)
+		ob.emitted(level)
+
+		if ob.Tee != nil {
+			ob.Tee(*msg, slices.Clone(fields))
+		}
+	}
+
+	if len(ob.DebugFields) == 0 || ob.hasOverride(msg) {
+		return
+	}
+
+	if ce := ob.Logger.Check(zapcore.DebugLevel, text); ce != nil {
+		fields := append([]zap.Field{
+			zap.String(fTransactionUUID, msg.TransactionUUID),
+			zap.Bool(fDetail, true),
+		}, ob.fields(ob.DebugFields, msg)...)
+		fields = ob.fitBudget(text, fields)
+		ce.Write(ob.withSeq(fields...)...)
+		ob.emitted(zapcore.DebugLevel)
+	}
 }
 
-// FieldOpt is a function that returns a zap.Field based on the message.
+// record keeps track of a message being observed, before the Filter is
+// consulted: KeepLast, the CorrelationIndex, the Metrics and the type
+// counters.  It returns the msg_type_count field and whether the message was
+// counted.
+func (ob Observer) record(msg *wrp.Message) (zap.Field, bool) {
+	if ob.KeepLast && ob.state != nil {
+		ob.state.last.store(msg, ob.clock().Now())
+	}
+	if ob.Correlation != nil {
+		ob.Correlation.record(msg, ob.clock().Now())
+	}
+
+	ob.observed(msg)
+	return ob.countType(msg.Type)
+}
+
+// baseLevel returns the level the entries are logged at before any
+// escalation.
+func (ob Observer) baseLevel() zapcore.Level {
+	if ob.AtomicLevel != nil {
+		return ob.AtomicLevel.Level()
+	}
+	if ob.Level == nil {
+		return DefaultLevel
+	}
+	return *ob.Level
+}
+
+// fieldOpts returns the FieldOpts to log for the message.
+func (ob Observer) fieldOpts(msg *wrp.Message) []FieldOpt {
+	if opts, found := ob.partnerOverride(msg); found {
+		return opts
+	}
+
+	if len(ob.Fields) == 0 && ob.UseDefaultFields {
+		return defaultFields
+	}
+
+	return ob.Fields
+}
+
+// message returns the entry's message text.
+func (ob Observer) message(msg *wrp.Message) string {
+	text := ob.Message
+	if ob.MessageFunc != nil {
+		text = ob.MessageFunc(*msg)
+	} else if t, found := ob.Messages[msg.Type]; found {
+		text = t
+	}
+
+	if !ob.SampleByType {
+		return text
+	}
+
+	name := typeName(msg.Type)
+	if text == "" {
+		return name
+	}
+
+	return text + " " + name
+}
+
+// typeName returns the name of the message type without the "MessageType"
+// suffix.  FriendlyName doesn't cover every type, so the suffix is trimmed
+// here.
+func typeName(mt wrp.MessageType) string {
+	return strings.TrimSuffix(messageTypeName(mt), "MessageType")
+}
+
+// fieldHeadroom is the room left for FieldOpts that add more than one field
+// and the fields added by the Observer itself.
+const fieldHeadroom = 4
+
+// fields evaluates the FieldOpts against the message.
+func (ob Observer) fields(opts []FieldOpt, msg *wrp.Message) []zap.Field {
+	if ob.CanonicalizeLocators {
+		canonical := *msg
+		canonical.Source = CanonicalLocator(msg.Source)
+		canonical.Destination = CanonicalLocator(msg.Destination)
+		msg = &canonical
+	}
+
+	var collisions []string
+	if ob.NormalizeMetadataKeys {
+		normalized := *msg
+		normalized.Metadata, collisions = normalizeMetadata(msg.Metadata)
+		msg = &normalized
+	}
+
+	core := ob.Logger.Core()
+	env := ob.fieldEnv()
+	fields := make([]zap.Field, 0, len(opts)+fieldHeadroom)
+
+	// The clock is only read once a LogObserveCost placeholder is found, so
+	// Observers without one don't pay for it.
+	costAt, start := -1, time.Time{}
+	for _, opt := range opts {
+		n := len(fields)
+		fields = appendField(core, env, fields, opt, msg)
+		if costAt < 0 && len(fields) > n && isObserveCost(fields[n]) {
+			costAt, start = n, ob.clock().Now()
+		}
+	}
+
+	if costAt >= 0 {
+		fields[costAt] = observeCostField(ob.clock().Now().Sub(start))
+	}
+	if len(collisions) > 0 {
+		fields = append(fields, zap.Strings(fMetadataKeyCollisions, collisions))
+	}
+
+	return ob.collectFieldErrors(fields)
+}
+
+// FieldOpt is a function that returns a zap.Field based on the message.  See
+// Appender for FieldOpts that log more than one field.
+//
+// A FieldOpt that has nothing to log for a message returns zap.Skip(), which
+// the Observer and FieldOpt.AppendFields leave out, so the entry holds only
+// the fields that were logged.
 type FieldOpt func(wrp.Message) zap.Field
 
 // LogMessageType logs the message type as a number.
 func LogMessageType() FieldOpt {
-	return LogMessageTypeAsNum()
+	return Named("msg_type", KeyMsgType, logMessageTypeAsNum)
+}
+
+// messageTypeNames are the names of the known message types, indexed by
+// wrp.MessageType, so that logging them doesn't go through the Stringer.
+var messageTypeNames = func() []string {
+	names := make([]string, wrp.LastMessageType+1)
+	for t := range names {
+		names[t] = wrp.MessageType(t).String()
+	}
+	return names
+}()
+
+// messageTypeName returns the name of the message type, as
+// wrp.MessageType.String does.
+func messageTypeName(t wrp.MessageType) string {
+	if t >= 0 && int(t) < len(messageTypeNames) {
+		return messageTypeNames[t]
+	}
+	return t.String()
 }
 
 // LogMessageTypeAsString logs the message type as a string.
 func LogMessageTypeAsString() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("msg_type_string", KeyMsgType, func(msg wrp.Message) zap.Field {
+		if msg.Type >= 0 && int(msg.Type) < len(messageTypeNames) {
+			return zap.String(fMsgType, messageTypeNames[msg.Type])
+		}
 		return zap.Stringer(fMsgType, msg.Type)
-	}
+	})
 }
 
 // LogMessageTypeAsNum logs the message type as a number.
 func LogMessageTypeAsNum() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
-		return zap.Int(fMsgType, int(msg.Type))
-	}
+	return Named("msg_type_num", KeyMsgType, logMessageTypeAsNum)
+}
+
+func logMessageTypeAsNum(msg wrp.Message) zap.Field {
+	return zap.Int(fMsgType, int(msg.Type))
 }
 
 // LogSource logs the source of the message.
 func LogSource() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("source", KeySource, func(msg wrp.Message) zap.Field {
 		return zap.String(fSource, msg.Source)
-	}
+	})
 }
 
 // LogDestination logs the destination of the message.
 func LogDestination() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("dest", KeyDestination, func(msg wrp.Message) zap.Field {
 		return zap.String(fDestination, msg.Destination)
-	}
+	})
 }
 
 // LogTransactionUUID logs the transaction UUID of the message.
 func LogTransactionUUID() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("transaction_uuid", KeyTransactionUUID, func(msg wrp.Message) zap.Field {
 		return zap.String(fTransactionUUID, msg.TransactionUUID)
-	}
+	})
 }
 
 // LogContentType logs the content type of the message.
 func LogContentType() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("content_type", KeyContentType, func(msg wrp.Message) zap.Field {
 		return zap.String(fContentType, msg.ContentType)
-	}
+	})
 }
 
 // LogAccept logs the accept header of the message.
 func LogAccept() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("accept", KeyAccept, func(msg wrp.Message) zap.Field {
 		return zap.String(fAccept, msg.Accept)
-	}
+	})
+}
+
+// LogAcceptMissing logs true when the message is a request that gets a
+// response and has no Accept value, which leaves the responder guessing the
+// encoding of the response.  False is logged for all other message types.
+func LogAcceptMissing() FieldOpt {
+	return Named("accept_missing", KeyAcceptMissing, func(msg wrp.Message) zap.Field {
+		missing := CategoryOf(msg.Type).ExpectsResponse() && msg.Accept == ""
+		return zap.Bool(fAcceptMissing, missing)
+	})
 }
 
 // LogStatus logs the status of the message.
 func LogStatus() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("status", KeyStatus, func(msg wrp.Message) zap.Field {
 		return zap.Int64p(fStatus, msg.Status)
-	}
+	})
 }
 
 // LogRequestDeliveryResponse logs the request delivery response of the message.
 func LogRequestDeliveryResponse() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("rdr", KeyRequestDeliveryResponse, func(msg wrp.Message) zap.Field {
 		return zap.Int64p(fRequestDeliveryResponse, msg.RequestDeliveryResponse)
-	}
+	})
 }
 
-// LogHeaders logs the headers of the message.
+// LogHeaders logs the headers of the message.  The field refers to the
+// message's slice of headers and is encoded when the entry is, without
+// copying the headers.  The only allocation is storing the slice in the
+// field.  A core that keeps fields to encode later, like zap's observer,
+// sees changes the pipeline makes to the slice in the meantime.
 func LogHeaders() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("headers", KeyHeaders, func(msg wrp.Message) zap.Field {
 		return zap.Strings(fHeaders, msg.Headers)
-	}
+	})
 }
 
-// LogMetadata logs the metadata of the message.
+// LogMetadata logs the metadata of the message as an object with the keys
+// sorted.  The metadata is encoded when the entry is, without copying it.
+//
+// The keys are always encoded in ascending byte order, whatever the encoder,
+// so the same metadata is encoded byte for byte the same by every service
+// that logs it.  This ordering is part of the package's contract.
 func LogMetadata() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
-		return zap.Any(fMetadata, msg.Metadata)
-	}
+	return Named("metadata", KeyMetadata, func(msg wrp.Message) zap.Field {
+		return zap.Object(fMetadata, metadataObject(msg.Metadata))
+	})
 }
 
 // LogPath logs the path of the message.
 func LogPath() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("path", KeyPath, func(msg wrp.Message) zap.Field {
 		return zap.String(fPath, msg.Path)
-	}
+	})
+}
+
+// LogCRUDPathValid logs whether the path of a CRUD message is non-empty and
+// absolute, starting with "/".  The path only has meaning for CRUD messages,
+// so true is logged for all other message types.
+func LogCRUDPathValid() FieldOpt {
+	return Named("crud_path_valid", KeyCRUDPathValid, func(msg wrp.Message) zap.Field {
+		valid := CategoryOf(msg.Type) != CategoryCRUD || strings.HasPrefix(msg.Path, "/")
+		return zap.Bool(fCRUDPathValid, valid)
+	})
 }
 
-// LogPayload logs the payload of the message.
+// LogPayload logs a copy of the payload of the message, so that the entry is
+// not affected by changes made to the payload after the message is observed,
+// even by cores that encode entries later.
+//
+// The copy costs every entry an allocation the size of the payload, also
+// with synchronous cores, where the payload is encoded before ObserveWRP
+// returns.  Encoding the payload costs more, as it is base64 encoded into a
+// buffer a third larger; BenchmarkObserver_LogPayload compares the two.  Use
+// LogPayloadNoCopy to avoid the copy when the payload is left alone.
 func LogPayload() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("payload", KeyPayload, func(msg wrp.Message) zap.Field {
+		return zap.Binary(fPayload, slices.Clone(msg.Payload))
+	})
+}
+
+// LogPayloadNoCopy logs the payload of the message like LogPayload, without
+// copying it.  The field refers to the message's payload, so the payload must
+// not be changed or reused until the entry has been written by every core;
+// with cores that buffer or encode entries asynchronously that is after
+// ObserveWRP returns.  A payload changed before then is logged with the
+// changes, or partly changed, and doing so is a data race.  Use it only when
+// the caller owns the payload and leaves it alone afterwards.
+func LogPayloadNoCopy() FieldOpt {
+	return Named("payload_no_copy", KeyPayload, func(msg wrp.Message) zap.Field {
 		return zap.Binary(fPayload, msg.Payload)
-	}
+	})
 }
 
 // LogPayloadSize logs the size of the payload of the message.
 func LogPayloadSize() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("payload_size", KeyPayloadSize, func(msg wrp.Message) zap.Field {
 		return zap.Int(fPayloadSize, len(msg.Payload))
-	}
+	})
 }
 
 // LogServiceName logs the service name of the message.
 func LogServiceName() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("service_name", KeyServiceName, func(msg wrp.Message) zap.Field {
 		return zap.String(fServiceName, msg.ServiceName)
-	}
+	})
 }
 
 // LogURL logs the URL of the message.
 func LogURL() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("url", KeyURL, func(msg wrp.Message) zap.Field {
 		return zap.String(fURL, msg.URL)
-	}
+	})
 }
 
-// LogPartnerIDs logs the partner IDs of the message.
+// LogPartnerIDs logs the partner IDs of the message.  The field refers to the
+// message's slice of partner IDs and is encoded when the entry is, without
+// copying the IDs.  Messages with up to four partner IDs, which is nearly
+// all of them, are logged without allocating; see partnerIDsArray.
 func LogPartnerIDs() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
-		return zap.Strings(fPartnerIDs, msg.PartnerIDs)
-	}
+	return Named("partner_ids", KeyPartnerIDs, func(msg wrp.Message) zap.Field {
+		return zap.Array(fPartnerIDs, partnerIDsArray(msg.PartnerIDs))
+	})
 }
 
 // LogSessionID logs the session ID of the message.
 func LogSessionID() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("session_id", KeySessionID, func(msg wrp.Message) zap.Field {
 		return zap.String(fSessionID, msg.SessionID)
-	}
+	})
 }
 
 // LogQualityOfService logs the quality of service of the message.
 func LogQualityOfService() FieldOpt {
-	return func(msg wrp.Message) zap.Field {
+	return Named("qos", KeyQualityOfService, func(msg wrp.Message) zap.Field {
 		return zap.Int(fQualityOfService, int(msg.QualityOfService))
-	}
+	})
 }
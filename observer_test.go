@@ -5,14 +5,20 @@ package wrpzap
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/xmidt-org/wrp-go/v3"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 )
 
@@ -45,7 +51,7 @@ func TestObserver_ObserveWRP(t *testing.T) {
 			name:            "log message type as string",
 			fields:          []FieldOpt{LogMessageTypeAsString()},
 			input_message:   wrp.Message{Type: wrp.SimpleRequestResponseMessageType},
-			expected_fields: []zap.Field{zap.Stringer(fMsgType, wrp.SimpleRequestResponseMessageType)},
+			expected_fields: []zap.Field{zap.String(fMsgType, wrp.SimpleRequestResponseMessageType.String())},
 		}, {
 			name:            "log source",
 			fields:          []FieldOpt{LogSource()},
@@ -71,6 +77,26 @@ func TestObserver_ObserveWRP(t *testing.T) {
 			fields:          []FieldOpt{LogAccept()},
 			input_message:   wrp.Message{Accept: "test accept"},
 			expected_fields: []zap.Field{zap.String(fAccept, "test accept")},
+		}, {
+			name:            "log accept missing on a request",
+			fields:          []FieldOpt{LogAcceptMissing()},
+			input_message:   wrp.Message{Type: wrp.SimpleRequestResponseMessageType},
+			expected_fields: []zap.Field{zap.Bool(fAcceptMissing, true)},
+		}, {
+			name:            "log accept missing on a crud request",
+			fields:          []FieldOpt{LogAcceptMissing()},
+			input_message:   wrp.Message{Type: wrp.RetrieveMessageType},
+			expected_fields: []zap.Field{zap.Bool(fAcceptMissing, true)},
+		}, {
+			name:            "log accept present on a request",
+			fields:          []FieldOpt{LogAcceptMissing()},
+			input_message:   wrp.Message{Type: wrp.UpdateMessageType, Accept: "application/json"},
+			expected_fields: []zap.Field{zap.Bool(fAcceptMissing, false)},
+		}, {
+			name:            "log accept missing on an event",
+			fields:          []FieldOpt{LogAcceptMissing()},
+			input_message:   wrp.Message{Type: wrp.SimpleEventMessageType},
+			expected_fields: []zap.Field{zap.Bool(fAcceptMissing, false)},
 		}, {
 			name:            "log nil status",
 			fields:          []FieldOpt{LogStatus()},
@@ -100,7 +126,7 @@ func TestObserver_ObserveWRP(t *testing.T) {
 			name:            "log metadata",
 			fields:          []FieldOpt{LogMetadata()},
 			input_message:   wrp.Message{Metadata: map[string]string{"key": "value"}},
-			expected_fields: []zap.Field{zap.Any(fMetadata, map[string]string{"key": "value"})},
+			expected_fields: []zap.Field{zap.Object(fMetadata, metadataObject{"key": "value"})},
 		}, {
 			name:            "log path",
 			fields:          []FieldOpt{LogPath()},
@@ -111,6 +137,11 @@ func TestObserver_ObserveWRP(t *testing.T) {
 			fields:          []FieldOpt{LogPayload()},
 			input_message:   wrp.Message{Payload: payload},
 			expected_fields: []zap.Field{zap.Binary(fPayload, payload)},
+		}, {
+			name:            "log payload without copying",
+			fields:          []FieldOpt{LogPayloadNoCopy()},
+			input_message:   wrp.Message{Payload: payload},
+			expected_fields: []zap.Field{zap.Binary(fPayload, payload)},
 		}, {
 			name:            "log payload size",
 			fields:          []FieldOpt{LogPayloadSize()},
@@ -130,7 +161,7 @@ func TestObserver_ObserveWRP(t *testing.T) {
 			name:            "log partner IDs",
 			fields:          []FieldOpt{LogPartnerIDs()},
 			input_message:   wrp.Message{PartnerIDs: []string{"partner1", "partner2"}},
-			expected_fields: []zap.Field{zap.Strings(fPartnerIDs, []string{"partner1", "partner2"})},
+			expected_fields: []zap.Field{zap.Array(fPartnerIDs, &stringArray2{"partner1", "partner2"})},
 		}, {
 			name:            "log session ID",
 			fields:          []FieldOpt{LogSessionID()},
@@ -152,7 +183,7 @@ func TestObserver_ObserveWRP(t *testing.T) {
 			text := "test message"
 			ob := Observer{
 				Logger:  logger,
-				Level:   zap.InfoLevel,
+				Level:   AtLevel(zap.InfoLevel),
 				Message: text,
 				Fields:  tt.fields,
 			}
@@ -179,28 +210,25 @@ func TestObserver_ObserveWRP(t *testing.T) {
 
 func TestFieldOpt_JSONTags(t *testing.T) {
 	fieldMap := map[string]string{
-		"Type":                    fMsgType,
-		"Source":                  fSource,
-		"Destination":             fDestination,
-		"TransactionUUID":         fTransactionUUID,
-		"ContentType":             fContentType,
-		"Accept":                  fAccept,
-		"Status":                  fStatus,
-		"RequestDeliveryResponse": fRequestDeliveryResponse,
-		"Headers":                 fHeaders,
-		"Metadata":                fMetadata,
-		"Path":                    fPath,
-		"Payload":                 fPayload,
-		"ServiceName":             fServiceName,
-		"URL":                     fURL,
-		"PartnerIDs":              fPartnerIDs,
-		"SessionID":               fSessionID,
-		"QualityOfService":        fQualityOfService,
-	}
-
-	ignored := map[string]struct{}{
-		"Spans":        {},
-		"IncludeSpans": {},
+		"Type":                    KeyMsgType,
+		"Source":                  KeySource,
+		"Destination":             KeyDestination,
+		"TransactionUUID":         KeyTransactionUUID,
+		"ContentType":             KeyContentType,
+		"Accept":                  KeyAccept,
+		"Status":                  KeyStatus,
+		"RequestDeliveryResponse": KeyRequestDeliveryResponse,
+		"Headers":                 KeyHeaders,
+		"Metadata":                KeyMetadata,
+		"Path":                    KeyPath,
+		"Payload":                 KeyPayload,
+		"ServiceName":             KeyServiceName,
+		"URL":                     KeyURL,
+		"PartnerIDs":              KeyPartnerIDs,
+		"SessionID":               KeySessionID,
+		"QualityOfService":        KeyQualityOfService,
+		"Spans":                   KeySpans,
+		"IncludeSpans":            KeyIncludeSpans,
 	}
 
 	msgType := reflect.TypeOf(wrp.Message{})
@@ -221,10 +249,1062 @@ func TestFieldOpt_JSONTags(t *testing.T) {
 	// Ensure all fields in wrp.Message are represented in the fieldMap
 	for i := 0; i < msgType.NumField(); i++ {
 		field := msgType.Field(i)
-		if _, found := ignored[field.Name]; found {
-			continue
+		assert.Contains(t, fieldMap, field.Name, "Field '%s' is not represented in the fieldMap", field.Name)
+	}
+}
+
+func TestObserver_ObserveWRP_DisabledLevel(t *testing.T) {
+	var calls int
+	opt := func(wrp.Message) zap.Field {
+		calls++
+		return zap.Skip()
+	}
+
+	core, recorded := observer.New(zap.InfoLevel)
+	ob := Observer{
+		Logger: zap.New(core),
+		Level:  AtLevel(zap.DebugLevel),
+		Fields: []FieldOpt{opt},
+	}
+
+	ob.ObserveWRP(context.Background(), wrp.Message{})
+
+	assert.Empty(t, recorded.All())
+	assert.Zero(t, calls, "fields should not be built for a disabled level")
+}
+
+func TestObserver_ObserveWRPPtr(t *testing.T) {
+	msg := wrp.Message{
+		Source:      "MAC:112233445566",
+		Destination: "event:device-status",
+		Metadata:    map[string]string{"/key": "value"},
+	}
+
+	tests := []struct {
+		name     string
+		msg      *wrp.Message
+		expected []zap.Field
+	}{
+		{
+			name: "message",
+			msg:  &msg,
+			expected: []zap.Field{
+				zap.String(fSource, "mac:112233445566"),
+				zap.Object(fMetadata, metadataObject{"/key": "value"}),
+			},
+		}, {
+			name: "nil message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zap.InfoLevel)
+			ob := Observer{
+				Logger:               zap.New(core),
+				Level:                AtLevel(zap.InfoLevel),
+				Fields:               []FieldOpt{LogSource(), LogMetadata()},
+				CanonicalizeLocators: true,
+			}
+
+			ob.ObserveWRPPtr(context.Background(), tt.msg)
+
+			entries := recorded.AllUntimed()
+			if tt.expected == nil {
+				assert.Empty(t, entries)
+				return
+			}
+
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.expected, entries[0].Context)
+			assert.Equal(t, "MAC:112233445566", tt.msg.Source, "the message must not be changed")
+
+			ob.ObserveWRP(context.Background(), *tt.msg)
+			assert.Equal(t, entries, recorded.AllUntimed()[1:])
+		})
+	}
+}
+
+func BenchmarkObserver_ObserveWRP(b *testing.B) {
+	msg := wrp.Message{
+		Type:            wrp.SimpleEventMessageType,
+		Source:          "mac:112233445566",
+		Destination:     "event:device-status/mac:112233445566/online",
+		TransactionUUID: "c07ee5e1-70be-444c-a156-097c767ad8aa",
+		PartnerIDs:      []string{"comcast"},
+		Headers:         []string{"X-Xmidt-Retry-Count: 1"},
+		Metadata:        tenMetadata(),
+	}
+
+	benchmarks := []struct {
+		name    string
+		observe func(Observer)
+	}{
+		{
+			name:    "value",
+			observe: func(ob Observer) { ob.ObserveWRP(context.Background(), msg) },
+		}, {
+			name:    "pointer",
+			observe: func(ob Observer) { ob.ObserveWRPPtr(context.Background(), &msg) },
+		},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			core := zapcore.NewCore(
+				zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+				zapcore.AddSync(io.Discard),
+				zap.InfoLevel,
+			)
+			ob := Observer{
+				Logger:  zap.New(core),
+				Level:   AtLevel(zap.InfoLevel),
+				Message: "wrp",
+				Fields:  append(DefaultFields(), LogMetadata()),
+			}
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				bm.observe(ob)
+			}
+		})
+	}
+}
+
+func TestObserver_UnsetLevel(t *testing.T) {
+	core, recorded := observer.New(zap.DebugLevel)
+	ob := Observer{
+		Logger: zap.New(core),
+		Fields: []FieldOpt{LogSource()},
+	}
+
+	assert.ErrorIs(t, ob.Validate(), ErrNoLevel)
+
+	ob.ObserveWRP(context.Background(), wrp.Message{})
+	entries := recorded.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, DefaultLevel, entries[0].Level)
+}
+
+func TestAtLevel(t *testing.T) {
+	level := AtLevel(zap.DebugLevel)
+	require.NotNil(t, level)
+	assert.Equal(t, zap.DebugLevel, *level)
+	assert.NotSame(t, level, AtLevel(zap.DebugLevel))
+}
+
+func TestObserver_DebugFields(t *testing.T) {
+	payload := []byte("test payload")
+	msg := wrp.Message{
+		Destination:     "mac:112233445566/config",
+		TransactionUUID: "test uuid",
+		Payload:         payload,
+		Metadata:        map[string]string{"key": "value"},
+	}
+
+	tests := []struct {
+		name      string
+		coreLevel zapcore.Level
+		expected  [][]zap.Field
+	}{
+		{
+			name:      "info core writes one entry",
+			coreLevel: zap.InfoLevel,
+			expected: [][]zap.Field{
+				{zap.String(fDestination, "mac:112233445566/config")},
+			},
+		}, {
+			name:      "debug core writes the detail entry",
+			coreLevel: zap.DebugLevel,
+			expected: [][]zap.Field{
+				{zap.String(fDestination, "mac:112233445566/config")},
+				{
+					zap.String(fTransactionUUID, "test uuid"),
+					zap.Bool(fDetail, true),
+					zap.Binary(fPayload, payload),
+					zap.Object(fMetadata, metadataObject{"key": "value"}),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(tt.coreLevel)
+
+			text := "test message"
+			ob := Observer{
+				Logger:      zap.New(core),
+				Level:       AtLevel(zap.InfoLevel),
+				Message:     text,
+				Fields:      []FieldOpt{LogDestination()},
+				DebugFields: []FieldOpt{LogPayload(), LogMetadata()},
+			}
+
+			ob.ObserveWRP(context.Background(), msg)
+
+			entries := recorded.All()
+			require.Len(t, entries, len(tt.expected))
+			for i, entry := range entries {
+				assert.Equal(t, text, entry.Message)
+				assert.ElementsMatch(t, tt.expected[i], entry.Context)
+			}
+			if len(entries) > 1 {
+				assert.Equal(t, zap.InfoLevel, entries[0].Level)
+				assert.Equal(t, zap.DebugLevel, entries[1].Level)
+			}
+		})
+	}
+}
+
+func TestNewObserver(t *testing.T) {
+	errUnknown := errors.New("unknown")
+
+	tests := []struct {
+		name      string
+		nilLogger bool
+		opts      []Option
+		expected  Observer
+		err       error
+	}{
+		{
+			name: "no options",
+			err:  ErrNoLevel,
+		}, {
+			name:     "level only",
+			opts:     []Option{WithLevel(zap.DebugLevel)},
+			expected: Observer{Level: AtLevel(zap.DebugLevel)},
+		}, {
+			name: "all options",
+			opts: []Option{
+				WithLevel(zap.WarnLevel),
+				WithMessage("test message"),
+				WithFields(LogSource()),
+				WithFields(LogDestination()),
+				WithDebugFields(LogPayload()),
+				WithKeepLast(),
+				WithCanonicalLocators(),
+				WithDefaultFields(),
+				WithBatchCap(10),
+				nil,
+			},
+			expected: Observer{
+				Level:                AtLevel(zap.WarnLevel),
+				Message:              "test message",
+				KeepLast:             true,
+				CanonicalizeLocators: true,
+				UseDefaultFields:     true,
+				BatchCap:             10,
+			},
+		}, {
+			name: "negative batch cap",
+			opts: []Option{WithBatchCap(-1)},
+			err:  ErrInvalidInput,
+		}, {
+			name:      "nil logger",
+			nilLogger: true,
+			err:       ErrInvalidInput,
+		}, {
+			name: "failing option",
+			opts: []Option{
+				optionFunc(func(*Observer) error { return errUnknown }),
+			},
+			err: errUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zap.NewNop()
+			if tt.nilLogger {
+				logger = nil
+			}
+
+			ob, err := NewObserver(logger, tt.opts...)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, logger, ob.Logger)
+			assert.Equal(t, tt.expected.Level, ob.Level)
+			assert.Equal(t, tt.expected.Message, ob.Message)
+			assert.Equal(t, tt.expected.KeepLast, ob.KeepLast)
+			assert.Equal(t, tt.expected.BatchCap, ob.BatchCap)
+			assert.Equal(t, tt.expected.UseDefaultFields, ob.UseDefaultFields)
+			assert.Equal(t, tt.expected.CanonicalizeLocators, ob.CanonicalizeLocators)
+			assert.NotNil(t, ob.state)
+		})
+	}
+}
+
+func TestMustNewObserver(t *testing.T) {
+	tests := []struct {
+		name   string
+		logger *zap.Logger
+		opts   []Option
+		panic  string
+	}{
+		{
+			name:   "valid",
+			logger: zap.NewNop(),
+			opts:   []Option{WithLevel(zap.InfoLevel)},
+		}, {
+			name:   "negative batch cap",
+			logger: zap.NewNop(),
+			opts:   []Option{WithLevel(zap.InfoLevel), WithBatchCap(-1)},
+			panic:  "wrpzap.WithBatchCap: invalid input: batch cap must not be negative",
+		}, {
+			name:   "nil clock",
+			logger: zap.NewNop(),
+			opts:   []Option{WithClock(nil), WithLevel(zap.InfoLevel)},
+			panic:  "wrpzap.WithClock: invalid input: clock is nil",
+		}, {
+			name:   "nil encoder",
+			logger: zap.NewNop(),
+			opts:   []Option{WithLevel(zap.InfoLevel), WithByteAccounting(nil)},
+			panic:  "wrpzap.WithByteAccounting: invalid input: encoder is nil",
+		}, {
+			name:   "no level",
+			logger: zap.NewNop(),
+			panic:  "no level configured: use WithLevel or WithAtomicLevel",
+		}, {
+			name:  "nil logger",
+			opts:  []Option{WithLevel(zap.InfoLevel)},
+			panic: "invalid input: logger is nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.panic != "" {
+				assert.PanicsWithError(t, tt.panic, func() {
+					MustNewObserver(tt.logger, tt.opts...)
+				})
+				return
+			}
+
+			ob := MustNewObserver(tt.logger, tt.opts...)
+			assert.Equal(t, tt.logger, ob.Logger)
+			assert.NotNil(t, ob.state)
+		})
+	}
+}
+
+func TestObserver_StackOnError(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     zapcore.Level
+		rdr       *int64
+		wantStack bool
+	}{
+		{name: "info", level: zapcore.InfoLevel},
+		{name: "warn", level: zapcore.WarnLevel},
+		{name: "error", level: zapcore.ErrorLevel, wantStack: true},
+		{name: "escalated to error", level: zapcore.InfoLevel, rdr: rdr(5), wantStack: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zapcore.DebugLevel)
+			ob := Observer{
+				Logger:       zap.New(core),
+				Level:        AtLevel(tt.level),
+				Fields:       []FieldOpt{LogTransactionUUID()},
+				Escalations:  []Escalation{EscalateOnRDRFailure()},
+				StackOnError: true,
+			}
+
+			ob.ObserveWRP(context.Background(), wrp.Message{RequestDeliveryResponse: tt.rdr})
+
+			entries := recorded.AllUntimed()
+			require.Len(t, entries, 1)
+
+			stack, found := entries[0].ContextMap()[KeyStacktrace]
+			if !tt.wantStack {
+				assert.False(t, found)
+				return
+			}
+
+			require.True(t, found)
+			require.IsType(t, "", stack)
+
+			// The stack starts at the caller, not inside the Observer.
+			assert.True(t, strings.HasPrefix(stack.(string), "github.com/xmidt-org/wrpzap.TestObserver_StackOnError"),
+				"unexpected stack: %s", stack)
+			assert.NotContains(t, stack, "wrpzap.Observer.ObserveWRP")
+		})
+	}
+}
+
+func TestObserver_StackOnError_Disabled(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	ob := Observer{
+		Logger: zap.New(core),
+		Level:  AtLevel(zapcore.ErrorLevel),
+		Fields: []FieldOpt{LogTransactionUUID()},
+	}
+
+	ob.ObserveWRP(context.Background(), wrp.Message{})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.NotContains(t, entries[0].ContextMap(), KeyStacktrace)
+}
+
+func TestObserver_SampleByType(t *testing.T) {
+	tests := []struct {
+		name     string
+		ob       Observer
+		msgType  wrp.MessageType
+		expected string
+	}{
+		{
+			name:     "disabled",
+			ob:       Observer{Message: "wrp received"},
+			msgType:  wrp.SimpleEventMessageType,
+			expected: "wrp received",
+		}, {
+			name:     "event",
+			ob:       Observer{Message: "wrp received", SampleByType: true},
+			msgType:  wrp.SimpleEventMessageType,
+			expected: "wrp received SimpleEvent",
+		}, {
+			name:     "no message text",
+			ob:       Observer{SampleByType: true},
+			msgType:  wrp.CreateMessageType,
+			expected: "Create",
+		}, {
+			name: "message func",
+			ob: Observer{
+				MessageFunc:  func(msg wrp.Message) string { return msg.Source },
+				SampleByType: true,
+			},
+			msgType:  wrp.AuthorizationMessageType,
+			expected: "mac:112233445566 Authorization",
+		}, {
+			name:     "unknown type",
+			ob:       Observer{Message: "wrp received", SampleByType: true},
+			msgType:  wrp.MessageType(99),
+			expected: "wrp received " + wrp.MessageType(99).String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zapcore.DebugLevel)
+			tt.ob.Logger = zap.New(core)
+
+			tt.ob.ObserveWRP(context.Background(), wrp.Message{
+				Type:   tt.msgType,
+				Source: "mac:112233445566",
+			})
+
+			entries := recorded.AllUntimed()
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.expected, entries[0].Message)
+		})
+	}
+}
+
+func TestObserver_SampleByType_Sampler(t *testing.T) {
+	types := []wrp.MessageType{
+		wrp.SimpleEventMessageType,
+		wrp.SimpleRequestResponseMessageType,
+		wrp.CreateMessageType,
+	}
+
+	observe := func(sampleByType bool) []observer.LoggedEntry {
+		core, recorded := observer.New(zapcore.DebugLevel)
+
+		// Only the first entry with each level and message text in a tick
+		// is kept.
+		sampled := zapcore.NewSamplerWithOptions(core, time.Hour, 1, 0)
+
+		ob := Observer{
+			Logger:       zap.New(sampled),
+			Message:      "wrp received",
+			Fields:       []FieldOpt{LogMessageTypeAsString()},
+			SampleByType: sampleByType,
 		}
 
-		assert.Contains(t, fieldMap, field.Name, "Field '%s' is not represented in the fieldMap", field.Name)
+		for range 10 {
+			for _, mt := range types {
+				ob.ObserveWRP(context.Background(), wrp.Message{Type: mt})
+			}
+		}
+
+		return recorded.AllUntimed()
+	}
+
+	assert.Len(t, observe(false), 1, "all types collapse into one sampled message")
+
+	entries := observe(true)
+	require.Len(t, entries, len(types))
+	for i, mt := range types {
+		assert.Equal(t, mt.String(), entries[i].ContextMap()[KeyMsgType])
+	}
+}
+
+func TestLogCRUDPathValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		msgType  wrp.MessageType
+		path     string
+		expected bool
+	}{
+		{name: "create absolute", msgType: wrp.CreateMessageType, path: "/config/a", expected: true},
+		{name: "retrieve root", msgType: wrp.RetrieveMessageType, path: "/", expected: true},
+		{name: "update empty", msgType: wrp.UpdateMessageType},
+		{name: "delete relative", msgType: wrp.DeleteMessageType, path: "config/a"},
+		{name: "event without path", msgType: wrp.SimpleEventMessageType, expected: true},
+		{name: "request relative", msgType: wrp.SimpleRequestResponseMessageType, path: "config", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LogCRUDPathValid()(wrp.Message{Type: tt.msgType, Path: tt.path})
+			assert.Equal(t, zap.Bool(KeyCRUDPathValid, tt.expected), got)
+		})
+	}
+}
+
+func TestObserver_Messages(t *testing.T) {
+	messages := map[wrp.MessageType]string{
+		wrp.SimpleEventMessageType:           "wrp event",
+		wrp.SimpleRequestResponseMessageType: "wrp request",
+		wrp.CreateMessageType:                "wrp crud",
+	}
+
+	core, recorded := observer.New(zapcore.DebugLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithMessage("wrp received"),
+		WithMessages(messages),
+		WithFields(LogMessageTypeAsString()),
+	)
+	require.NoError(t, err)
+
+	// The map is copied.
+	messages[wrp.CreateMessageType] = "changed"
+
+	for _, mt := range []wrp.MessageType{
+		wrp.SimpleEventMessageType,
+		wrp.SimpleRequestResponseMessageType,
+		wrp.CreateMessageType,
+		wrp.RetrieveMessageType,
+	} {
+		ob.ObserveWRP(context.Background(), wrp.Message{Type: mt})
+	}
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 4)
+	assert.Equal(t, "wrp event", entries[0].Message)
+	assert.Equal(t, "wrp request", entries[1].Message)
+	assert.Equal(t, "wrp crud", entries[2].Message)
+	assert.Equal(t, "wrp received", entries[3].Message, "unmapped types use Message")
+}
+
+func TestObserver_Messages_Precedence(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	ob := Observer{
+		Logger:       zap.New(core),
+		Message:      "wrp received",
+		Messages:     map[wrp.MessageType]string{wrp.SimpleEventMessageType: "wrp event"},
+		MessageFunc:  func(wrp.Message) string { return "from func" },
+		SampleByType: true,
+	}
+
+	ob.ObserveWRP(context.Background(), wrp.Message{Type: wrp.SimpleEventMessageType})
+
+	ob.MessageFunc = nil
+	ob.ObserveWRP(context.Background(), wrp.Message{Type: wrp.SimpleEventMessageType})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "from func SimpleEvent", entries[0].Message)
+	assert.Equal(t, "wrp event SimpleEvent", entries[1].Message)
+}
+
+func TestLogPayload_Aliasing(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     FieldOpt
+		aliased bool
+	}{
+		{name: "copied", opt: LogPayload()},
+		{name: "not copied", opt: LogPayloadNoCopy(), aliased: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := []byte("before")
+			field := tt.opt(wrp.Message{Payload: payload})
+			copy(payload, "after!")
+
+			if tt.aliased {
+				assert.Equal(t, zap.Binary(fPayload, []byte("after!")), field)
+			} else {
+				assert.Equal(t, zap.Binary(fPayload, []byte("before")), field)
+			}
+		})
+	}
+}
+
+func BenchmarkLogPayload(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		opt  FieldOpt
+	}{
+		{name: "copy", opt: LogPayload()},
+		{name: "no copy", opt: LogPayloadNoCopy()},
+	}
+
+	for _, size := range []int{8 << 10, 64 << 10} {
+		msg := wrp.Message{Payload: make([]byte, size)}
+		for _, bm := range benchmarks {
+			b.Run(fmt.Sprintf("%s/%dKB", bm.name, size>>10), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					bm.opt(msg)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkObserver_LogPayload measures what LogPayload's copy costs a whole
+// entry written to a synchronous core, next to encoding the payload.
+func BenchmarkObserver_LogPayload(b *testing.B) {
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), zapcore.AddSync(io.Discard), zap.InfoLevel)
+
+	benchmarks := []struct {
+		name string
+		opt  FieldOpt
+	}{
+		{name: "copy", opt: LogPayload()},
+		{name: "no copy", opt: LogPayloadNoCopy()},
+	}
+
+	for _, size := range []int{512, 8 << 10, 64 << 10} {
+		msg := wrp.Message{Source: "mac:112233445566", Payload: make([]byte, size)}
+		for _, bm := range benchmarks {
+			b.Run(fmt.Sprintf("%s/%dB", bm.name, size), func(b *testing.B) {
+				ob := Observer{Logger: zap.New(core), Fields: []FieldOpt{LogSource(), bm.opt}}
+				b.SetBytes(int64(size))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					ob.ObserveWRPPtr(context.Background(), &msg)
+				}
+			})
+		}
+	}
+}
+
+func TestLogMessageTypeAsString_Names(t *testing.T) {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	opt := LogMessageTypeAsString()
+
+	for mt := wrp.MessageType(-1); mt <= wrp.LastMessageType+1; mt++ {
+		t.Run(mt.String(), func(t *testing.T) {
+			got, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{opt(wrp.Message{Type: mt})})
+			require.NoError(t, err)
+			want, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{zap.Stringer(fMsgType, mt)})
+			require.NoError(t, err)
+			assert.Equal(t, want.String(), got.String())
+		})
+	}
+}
+
+func BenchmarkLogMessageTypeAsString(b *testing.B) {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	msg := wrp.Message{Type: wrp.SimpleEventMessageType}
+
+	benchmarks := []struct {
+		name string
+		opt  FieldOpt
+	}{
+		{
+			name: "stringer",
+			opt: func(msg wrp.Message) zap.Field {
+				return zap.Stringer(fMsgType, msg.Type)
+			},
+		}, {
+			name: "table",
+			opt:  LogMessageTypeAsString(),
+		},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf, _ := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{bm.opt(msg)})
+				buf.Free()
+			}
+		})
+	}
+}
+
+// manyHeaders returns the headers of a message with a long header list.
+func manyHeaders() []string {
+	headers := make([]string, 24)
+	for i := range headers {
+		headers[i] = fmt.Sprintf("X-Header-%d: value %d", i, i)
+	}
+	return headers
+}
+
+func TestLogHeaders_Allocations(t *testing.T) {
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), zapcore.AddSync(io.Discard), zap.InfoLevel)
+	msg := wrp.Message{Headers: manyHeaders()}
+
+	observe := func(opts ...FieldOpt) float64 {
+		ob := Observer{Logger: zap.New(core), Fields: opts}
+		return testing.AllocsPerRun(100, func() { ob.ObserveWRPPtr(context.Background(), &msg) })
+	}
+
+	// Storing the slice in the field is the only allocation, however many
+	// headers there are.
+	assert.Equal(t, observe(NoFields()...)+1, observe(LogHeaders()))
+}
+
+func BenchmarkLogHeaders(b *testing.B) {
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), zapcore.AddSync(io.Discard), zap.InfoLevel)
+	ob := Observer{Logger: zap.New(core), Fields: []FieldOpt{LogHeaders()}}
+	msg := wrp.Message{Headers: manyHeaders()}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ob.ObserveWRPPtr(context.Background(), &msg)
+	}
+}
+
+func TestObserver_Tee(t *testing.T) {
+	type teed struct {
+		msg    wrp.Message
+		fields []zap.Field
+	}
+
+	tests := []struct {
+		name   string
+		level  zapcore.Level
+		filter func(wrp.Message) bool
+		wrap   func(zapcore.Core) zapcore.Core
+		logged int
+	}{
+		{
+			name:   "logged",
+			level:  zap.InfoLevel,
+			logged: 3,
+		}, {
+			name:  "below the logger's level",
+			level: zap.DebugLevel,
+		}, {
+			name:   "filtered",
+			level:  zap.InfoLevel,
+			filter: func(msg wrp.Message) bool { return msg.Source == "mac:000000000002" },
+			logged: 1,
+		}, {
+			name:  "sampled",
+			level: zap.InfoLevel,
+			wrap: func(core zapcore.Core) zapcore.Core {
+				return zapcore.NewSamplerWithOptions(core, time.Minute, 1, 0)
+			},
+			logged: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var core zapcore.Core
+			core, recorded := observer.New(zap.InfoLevel)
+			if tt.wrap != nil {
+				core = tt.wrap(core)
+			}
+
+			var got []teed
+			ob, err := NewObserver(zap.New(core),
+				WithLevel(tt.level),
+				WithMessage("wrp"),
+				WithFields(LogSource(), LogMetadata(), LogRetryCount()),
+				WithDebugFields(LogPayload()),
+				WithFilter(tt.filter),
+				WithTee(func(msg wrp.Message, fields []zap.Field) {
+					got = append(got, teed{msg: msg, fields: fields})
+				}),
+			)
+			require.NoError(t, err)
+
+			for i := 1; i <= 3; i++ {
+				ob.ObserveWRP(context.Background(), wrp.Message{
+					Source:   fmt.Sprintf("mac:00000000000%d", i),
+					Metadata: map[string]string{"/k": "v"},
+				})
+			}
+
+			entries := recorded.AllUntimed()
+			require.Len(t, entries, tt.logged)
+			require.Len(t, got, tt.logged)
+			for i, entry := range entries {
+				assert.Equal(t, keys(entry.Context), keys(got[i].fields))
+				assert.Equal(t, entry.ContextMap()[KeySource], got[i].msg.Source)
+			}
+		})
+	}
+}
+
+func TestObserver_Tee_Ownership(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	ob := Observer{
+		Logger: zap.New(core),
+		Fields: []FieldOpt{LogSource(), LogDestination()},
+		Tee: func(_ wrp.Message, fields []zap.Field) {
+			fields[0] = zap.String(KeySource, "corrupted")
+			fields[1].Key = "corrupted"
+		},
+	}
+
+	ob.ObserveWRP(context.Background(), wrp.Message{Source: "mac:112233445566", Destination: "event:a"})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{
+		KeySource:      "mac:112233445566",
+		KeyDestination: "event:a",
+	}, entries[0].ContextMap())
+}
+
+func TestWithTee(t *testing.T) {
+	ob, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithTee(func(wrp.Message, []zap.Field) {}))
+	require.NoError(t, err)
+	assert.Contains(t, ob.String(), " tee=true")
+
+	_, err = NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithTee(nil))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestWithAtomicLevel(t *testing.T) {
+	core, recorded := observer.New(zap.DebugLevel)
+	atomic := zap.NewAtomicLevelAt(zap.InfoLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.ErrorLevel),
+		WithAtomicLevel(atomic),
+		WithFields(LogSource()),
+		WithEscalations(EscalateOnRDRFailure()),
+	)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ob.String(), "level=info atomic_level=true "), ob.String())
+
+	observe := func(rdr int64) {
+		ob.ObserveWRP(context.Background(), wrp.Message{RequestDeliveryResponse: &rdr})
+	}
+
+	// The atomic level takes precedence over Level.
+	observe(0)
+
+	// Turning the dial changes the level the entries are logged at, not
+	// just whether they are logged.
+	atomic.SetLevel(zap.DebugLevel)
+	observe(0)
+
+	// Escalations still raise the level above the atomic level.
+	observe(2)
+	observe(1)
+
+	// But never lower it.
+	atomic.SetLevel(zap.ErrorLevel)
+	observe(2)
+	assert.Contains(t, ob.String(), "level=error")
+
+	var levels []zapcore.Level
+	for _, entry := range recorded.AllUntimed() {
+		levels = append(levels, entry.Level)
+	}
+	assert.Equal(t, []zapcore.Level{
+		zap.InfoLevel,
+		zap.DebugLevel,
+		zap.WarnLevel,
+		zap.ErrorLevel,
+		zap.ErrorLevel,
+	}, levels)
+}
+
+func TestWithAtomicLevel_Gate(t *testing.T) {
+	// The usual setup: the logger and the Observer share the atomic level.
+	atomic := zap.NewAtomicLevelAt(zap.InfoLevel)
+	core, recorded := observer.New(atomic)
+	ob, err := NewObserver(zap.New(core), WithAtomicLevel(atomic), WithFields(LogSource()))
+	require.NoError(t, err)
+	require.NoError(t, ob.Validate())
+
+	for _, level := range []zapcore.Level{zap.DebugLevel, zap.WarnLevel, zap.InfoLevel} {
+		atomic.SetLevel(level)
+		ob.ObserveWRP(context.Background(), wrp.Message{})
+	}
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 3)
+	for i, level := range []zapcore.Level{zap.DebugLevel, zap.WarnLevel, zap.InfoLevel} {
+		assert.Equal(t, level, entries[i].Level)
+	}
+}
+
+func TestNewObserver_Snapshot(t *testing.T) {
+	core, recorded := observer.New(zap.DebugLevel)
+
+	fields := make([]FieldOpt, 0, 4)
+	fields = append(fields, LogSource())
+	debugFields := []FieldOpt{LogPayloadSize()}
+	escalations := []Escalation{EscalateOnRDRFailure()}
+
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithFields(fields...),
+		WithDebugFields(debugFields...),
+		WithEscalations(escalations...),
+	)
+	require.NoError(t, err)
+
+	msg := wrp.Message{Source: "a", Destination: "b", RequestDeliveryResponse: int64p(1)}
+	ob.ObserveWRP(context.Background(), msg)
+
+	// Changing the callers' slices, in place or by appending into their
+	// spare capacity, doesn't change what is logged.
+	fields[0] = LogDestination()
+	_ = append(fields, LogPayload())
+	debugFields[0] = LogPayload()
+	escalations[0] = func(wrp.Message, zapcore.Level) zapcore.Level { return zap.InfoLevel }
+	ob.ObserveWRP(context.Background(), msg)
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 4)
+	for _, i := range []int{0, 2} {
+		assert.Equal(t, zap.ErrorLevel, entries[i].Level)
+		assert.Equal(t, map[string]any{KeySource: "a"}, entries[i].ContextMap())
+		assert.Contains(t, entries[i+1].ContextMap(), KeyPayloadSize)
+	}
+}
+
+func TestNewObserver_SnapshotConcurrent(t *testing.T) {
+	fields := []FieldOpt{LogSource(), LogDestination()}
+	ob, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithFields(fields...))
+	require.NoError(t, err)
+
+	// Run with -race: the caller changing its slice doesn't race with
+	// observing.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			fields[i%2] = LogPayload()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		ob.ObserveWRP(context.Background(), wrp.Message{})
+	}
+	<-done
+}
+
+func TestObserver_ObserveWRP_SkippedFields(t *testing.T) {
+	// unknown is a skipped field carrying a value the Observer doesn't know.
+	type unknown struct{}
+
+	msg := wrp.Message{
+		Type:        wrp.SimpleEventMessageType,
+		Source:      "mac:112233445566",
+		Destination: "event:device-status",
+	}
+
+	core, recorded := observer.New(zap.InfoLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithFields(
+			func(wrp.Message) zap.Field { return zap.Skip() },
+			LogSource(),
+			func(wrp.Message) zap.Field { return zap.Field{Type: zapcore.SkipType, Interface: unknown{}} },
+			LogDestinationPartnerMismatch(nil),
+			LogIf(func(wrp.Message) bool { return false }, LogPayload()),
+			LogDestination(),
+			func(wrp.Message) zap.Field { return zap.Skip() },
+		),
+	)
+	require.NoError(t, err)
+	ob.ObserveWRP(context.Background(), msg)
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, []zap.Field{
+		zap.String(KeySource, msg.Source),
+		zap.String(KeyDestination, msg.Destination),
+	}, entries[0].Context)
+}
+
+func TestObserver_ObserveWRPWith(t *testing.T) {
+	msg := wrp.Message{
+		Source:          "mac:112233445566",
+		TransactionUUID: "c07ee5e1-70be-444c-a156-097c767ad8aa",
+	}
+
+	core, recorded := observer.New(zap.DebugLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithFields(LogSource()),
+		WithDebugFields(LogSource()),
+	)
+	require.NoError(t, err)
+
+	extra := []zap.Field{zap.Duration("decode_time", time.Millisecond), zap.String("handler", "config")}
+	ob.ObserveWRPWith(context.Background(), msg, extra...)
+	ob.ObserveWRPWith(context.Background(), msg)
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 4)
+	assert.Equal(t, []zap.Field{
+		zap.String(KeySource, msg.Source),
+		zap.Duration("decode_time", time.Millisecond),
+		zap.String("handler", "config"),
+	}, entries[0].Context)
+
+	// The extra fields are not in the Debug entry, nor in later calls.
+	assert.NotContains(t, entries[1].ContextMap(), "handler")
+	assert.Equal(t, []zap.Field{zap.String(KeySource, msg.Source)}, entries[2].Context)
+	assert.NotContains(t, entries[3].ContextMap(), "handler")
+
+	// Neither the caller's fields nor the Observer are changed.
+	assert.Equal(t, []zap.Field{zap.Duration("decode_time", time.Millisecond), zap.String("handler", "config")}, extra)
+	assert.Len(t, ob.Fields, 1)
+}
+
+func TestObserver_ObserveWRPWith_Concurrent(t *testing.T) {
+	const calls = 200
+
+	core, recorded := observer.New(zap.InfoLevel)
+	ob, err := NewObserver(zap.New(core), WithLevel(zap.InfoLevel), WithFields(LogSource()))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for _, caller := range []string{"a", "b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < calls; i++ {
+				ob.ObserveWRPWith(context.Background(), wrp.Message{Source: caller},
+					zap.String("caller", caller),
+					zap.Int("call", i),
+				)
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2*calls)
+	for _, entry := range entries {
+		require.Len(t, entry.Context, 3)
+		fields := entry.ContextMap()
+		assert.Equal(t, fields[KeySource], fields["caller"])
 	}
 }
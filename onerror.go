@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrorLogInterval is the shortest time between the entries an Observer
+// without OnError logs for its internal errors.  The errors in between are
+// counted, and the count is logged with the next entry as suppressed_errors.
+const ErrorLogInterval = time.Minute
+
+// ErrorLogMessage is the message text of the entries logged for internal
+// errors.
+const ErrorLogMessage = "wrp observer error"
+
+// errorLimiter limits how often internal errors are logged.
+type errorLimiter struct {
+	lock       sync.Mutex
+	last       time.Time
+	logged     bool
+	suppressed int
+}
+
+// allow reports whether an error that happened at the time is logged, along
+// with the number of errors suppressed since the last one that was.
+func (l *errorLimiter) allow(now time.Time) (bool, int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.logged && now.Sub(l.last) < ErrorLogInterval {
+		l.suppressed++
+		return false, 0
+	}
+
+	suppressed := l.suppressed
+	l.last, l.logged, l.suppressed = now, true, 0
+	return true, suppressed
+}
+
+// sharedErrors limits the internal errors logged by Observers that were not
+// created by NewObserver, which have no state of their own.
+var sharedErrors errorLimiter
+
+// reportError hands an internal error to OnError, or logs it when there is
+// no OnError.
+func (ob Observer) reportError(err error) {
+	if ob.OnError != nil {
+		ob.OnError(err)
+		return
+	}
+
+	if ob.Logger == nil {
+		return
+	}
+
+	limiter := &sharedErrors
+	if ob.state != nil {
+		limiter = &ob.state.errors
+	}
+
+	if ok, suppressed := limiter.allow(ob.clock().Now()); ok {
+		ob.Logger.Warn(ErrorLogMessage,
+			zap.Error(err),
+			zap.Int(fSuppressedErrors, suppressed),
+		)
+	}
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// overflow observes messages with an AsyncObserver of one slot whose worker
+// is held up, so every message after the second is dropped.  It returns the
+// AsyncObserver and the function that lets the worker go on.
+func overflow(t *testing.T, ob Observer, opts ...AsyncOption) (*AsyncObserver, func()) {
+	t.Helper()
+	core := newSlowCore(ob.Logger.Core())
+	ob.Logger = zap.New(core)
+
+	a, err := NewAsyncObserver(ob, 1, opts...)
+	require.NoError(t, err)
+
+	a.ObserveWRP(context.Background(), wrp.Message{Source: "1"})
+	<-core.started
+	a.ObserveWRP(context.Background(), wrp.Message{Source: "2"})
+
+	return a, func() { close(core.gate) }
+}
+
+func TestWithOnError(t *testing.T) {
+	var reported []error
+	core, _ := observer.New(zapcore.DebugLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithOnError(func(err error) { reported = append(reported, err) }),
+	)
+	require.NoError(t, err)
+	assert.Contains(t, ob.String(), " on_error=true")
+
+	// A message dropped because the async buffer is full.
+	a, release := overflow(t, ob)
+	a.ObserveWRP(context.Background(), wrp.Message{Source: "3"})
+	require.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0], ErrDropped)
+
+	// A message that can't be written because the observer is closed.
+	release()
+	require.NoError(t, a.Close(context.Background()))
+	a.ObserveWRP(context.Background(), wrp.Message{Source: "4"})
+	require.Len(t, reported, 2)
+	assert.ErrorIs(t, reported[1], ErrClosed)
+
+	_, err = NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithOnError(nil))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestObserver_ReportError_RateLimited(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	clock := newFakeClock(time.UnixMilli(1000))
+	ob, err := NewObserver(zap.New(core), WithLevel(zap.InfoLevel), WithClock(clock))
+	require.NoError(t, err)
+
+	errorEntries := func() []observer.LoggedEntry {
+		return recorded.FilterMessage(ErrorLogMessage).AllUntimed()
+	}
+
+	a, release := overflow(t, ob)
+	defer func() {
+		release()
+		assert.NoError(t, a.Close(context.Background()))
+	}()
+	for i := 0; i < 5; i++ {
+		a.ObserveWRP(context.Background(), wrp.Message{Source: "3"})
+		clock.Add(time.Second)
+	}
+
+	entries := errorEntries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	assert.Equal(t, map[string]any{
+		"error":             "message dropped: async buffer full, dropped the newest message",
+		KeySuppressedErrors: int64(0),
+	}, entries[0].ContextMap())
+
+	// Once the interval passes the next error is logged with the count of
+	// the ones in between.
+	clock.Add(ErrorLogInterval)
+	a.ObserveWRP(context.Background(), wrp.Message{Source: "3"})
+
+	entries = errorEntries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(4), entries[1].ContextMap()[KeySuppressedErrors])
+}
+
+func TestObserver_ReportError_NoLogger(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Observer{}.reportError(errors.New("failed"))
+	})
+}
@@ -0,0 +1,325 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Option is a configuration option for NewObserver.
+type Option interface {
+	apply(*Observer) error
+}
+
+type optionFunc func(*Observer) error
+
+func (f optionFunc) apply(ob *Observer) error {
+	return f(ob)
+}
+
+// optionName names an Option after the function that created it, like
+// wrpzap.WithClock, for the errors NewObserver returns.
+func optionName(opt Option) string {
+	if f, ok := opt.(optionFunc); ok {
+		return funcName(f)
+	}
+	return fmt.Sprintf("%T", opt)
+}
+
+// WithLevel sets the level the entries are logged at.
+func WithLevel(level zapcore.Level) Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.Level = &level
+		return nil
+	})
+}
+
+// WithAtomicLevel sets the atomic level the entries are logged at, so the
+// level can be changed while the Observer is in use.  It takes precedence
+// over WithLevel.
+func WithAtomicLevel(level zap.AtomicLevel) Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.AtomicLevel = &level
+		return nil
+	})
+}
+
+// WithMessage sets the message text of the entries.
+func WithMessage(text string) Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.Message = text
+		return nil
+	})
+}
+
+// WithMessages sets the message text of the entries for each message type.
+// The map is copied.
+func WithMessages(messages map[wrp.MessageType]string) Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.Messages = maps.Clone(messages)
+		return nil
+	})
+}
+
+// WithMessageFunc sets the function that produces the message text of the
+// entries.
+func WithMessageFunc(fn func(wrp.Message) string) Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.MessageFunc = fn
+		return nil
+	})
+}
+
+// WithFields adds the FieldOpts to the fields logged.
+func WithFields(opts ...FieldOpt) Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.Fields = append(ob.Fields, opts...)
+		return nil
+	})
+}
+
+// WithFilter sets the filter that chooses the messages that are logged.
+func WithFilter(filter func(wrp.Message) bool) Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.Filter = filter
+		return nil
+	})
+}
+
+// WithSampleByType appends the message type's name to the message text so
+// that zap's sampler samples each type of message separately.
+func WithSampleByType() Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.SampleByType = true
+		return nil
+	})
+}
+
+// WithDefaultFields logs the DefaultFields when no Fields are configured.
+func WithDefaultFields() Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.UseDefaultFields = true
+		return nil
+	})
+}
+
+// WithDebugFields adds the FieldOpts to the fields logged in the Debug detail
+// entry.
+func WithDebugFields(opts ...FieldOpt) Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.DebugFields = append(ob.DebugFields, opts...)
+		return nil
+	})
+}
+
+// WithEscalations adds the Escalations used to choose the level of each
+// entry.
+func WithEscalations(escs ...Escalation) Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.Escalations = append(ob.Escalations, escs...)
+		return nil
+	})
+}
+
+// WithStackOnError adds a stacktrace to the entries logged at Error level or
+// above.
+func WithStackOnError() Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.StackOnError = true
+		return nil
+	})
+}
+
+// WithCanonicalLocators enables canonicalizing the Source and Destination
+// before they are logged.
+func WithCanonicalLocators() Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.CanonicalizeLocators = true
+		return nil
+	})
+}
+
+// WithNormalizedMetadataKeys enables normalizing the metadata keys before they
+// are logged.
+func WithNormalizedMetadataKeys() Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.NormalizeMetadataKeys = true
+		return nil
+	})
+}
+
+// WithKeepLast enables retaining the most recently observed message.
+func WithKeepLast() Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.KeepLast = true
+		return nil
+	})
+}
+
+// WithSequenceNumbers numbers the entries the Observer writes with a seq
+// field.
+func WithSequenceNumbers() Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.SequenceNumbers = true
+		return nil
+	})
+}
+
+// WithTypeCounters counts the messages observed of each type and logs the
+// count with each entry as msg_type_count.
+func WithTypeCounters() Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.TypeCounters = true
+		return nil
+	})
+}
+
+// WithCorrelationIndex sets the CorrelationIndex that remembers the last
+// transactions each device sent.
+func WithCorrelationIndex(index *CorrelationIndex) Option {
+	return optionFunc(func(ob *Observer) error {
+		if index == nil {
+			return fmt.Errorf("%w: correlation index is nil", ErrInvalidInput)
+		}
+		ob.Correlation = index
+		return nil
+	})
+}
+
+// WithEntryBudget sets the largest size, in bytes, of the entries logged.
+// See Observer.EntryBudget.
+func WithEntryBudget(bytes int) Option {
+	return optionFunc(func(ob *Observer) error {
+		if bytes < 0 {
+			return fmt.Errorf("%w: entry budget must not be negative", ErrInvalidInput)
+		}
+		ob.EntryBudget = bytes
+		return nil
+	})
+}
+
+// WithFieldPriorities sets the priorities of the fields with the keys, used
+// to choose the fields dropped to keep an entry within the budget set by
+// WithEntryBudget.  The map is copied.
+func WithFieldPriorities(priorities map[string]Priority) Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.FieldPriorities = maps.Clone(priorities)
+		return nil
+	})
+}
+
+// WithFieldErrors logs the errors the FieldOpts report with FieldError as
+// field_errors.
+func WithFieldErrors() Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.FieldErrors = true
+		return nil
+	})
+}
+
+// WithBatchCap sets the largest batch ObserveWRPBatch logs each message of.
+func WithBatchCap(max int) Option {
+	return optionFunc(func(ob *Observer) error {
+		if max < 0 {
+			return fmt.Errorf("%w: batch cap must not be negative", ErrInvalidInput)
+		}
+		ob.BatchCap = max
+		return nil
+	})
+}
+
+// WithClock sets the Clock the Observer gets the time from.
+func WithClock(clock Clock) Option {
+	return optionFunc(func(ob *Observer) error {
+		if clock == nil {
+			return fmt.Errorf("%w: clock is nil", ErrInvalidInput)
+		}
+		ob.Clock = clock
+		return nil
+	})
+}
+
+// WithEncodedSize logs the size of the encoded message with the entries of
+// ObserveEncoded.
+func WithEncodedSize() Option {
+	return optionFunc(func(ob *Observer) error {
+		ob.LogEncodedSize = true
+		return nil
+	})
+}
+
+// WithTee sets the function called with the fields of each entry logged.
+func WithTee(fn func(msg wrp.Message, fields []zap.Field)) Option {
+	return optionFunc(func(ob *Observer) error {
+		if fn == nil {
+			return fmt.Errorf("%w: tee function is nil", ErrInvalidInput)
+		}
+		ob.Tee = fn
+		return nil
+	})
+}
+
+// WithMetrics sets the Metrics told about the messages observed and the
+// entries written.
+func WithMetrics(metrics Metrics) Option {
+	return optionFunc(func(ob *Observer) error {
+		if metrics == nil {
+			return fmt.Errorf("%w: metrics is nil", ErrInvalidInput)
+		}
+		ob.Metrics = metrics
+		return nil
+	})
+}
+
+// WithOnError sets the function called with the Observer's internal errors.
+func WithOnError(fn func(error)) Option {
+	return optionFunc(func(ob *Observer) error {
+		if fn == nil {
+			return fmt.Errorf("%w: error function is nil", ErrInvalidInput)
+		}
+		ob.OnError = fn
+		return nil
+	})
+}
+
+// WithPartnerOverrides sets the FieldOpts logged instead of the Fields for
+// the messages of each partner.  The partner IDs are normalized with
+// NormalizePartnerID, so they are matched without regard to case or
+// surrounding whitespace, and two IDs that normalize to the same ID are
+// ErrInvalidInput.  The map and the slices are copied.
+func WithPartnerOverrides(overrides map[string][]FieldOpt) Option {
+	return optionFunc(func(ob *Observer) error {
+		copied := make(map[string][]FieldOpt, len(overrides))
+		for id, opts := range overrides {
+			key := NormalizePartnerID(id)
+			if _, dup := copied[key]; dup {
+				return fmt.Errorf("%w: partner %q has more than one override", ErrInvalidInput, key)
+			}
+			copied[key] = slices.Clone(opts)
+		}
+		ob.PartnerOverrides = copied
+		return nil
+	})
+}
+
+// WithByteAccounting enables counting the entries written and their encoded
+// size, which is reported by Observer.Stats.  The encoder should be configured
+// the same as the encoder used by the logger's core so the counts are exact.
+// Context fields already added to the logger with With are not known to the
+// encoder, so they need to be added to enc as well to be counted.
+func WithByteAccounting(enc zapcore.Encoder) Option {
+	return optionFunc(func(ob *Observer) error {
+		if enc == nil {
+			return fmt.Errorf("%w: encoder is nil", ErrInvalidInput)
+		}
+		ob.accounting = enc
+		return nil
+	})
+}
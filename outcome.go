@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+// The delivery outcomes DeliveryOutcome classifies messages into.
+const (
+	OutcomeSuccess         = "success"
+	OutcomeClientError     = "client_error"
+	OutcomeServerError     = "server_error"
+	OutcomeDeliveryFailure = "delivery_failure"
+	OutcomeTimeout         = "timeout"
+	OutcomeUnknown         = "unknown"
+)
+
+// DeliveryOutcome classifies the combination of the message's request
+// delivery response (RDR) and Status into one of the Outcome constants.  The
+// rules are applied in order and the first that matches wins:
+//
+//  1. An RDR of 2, the message expired before it was delivered, is timeout.
+//  2. Any other non-zero RDR is delivery_failure.  A failed delivery
+//     outranks the Status, even a 200.
+//  3. A Status of 408 or 504 is timeout.
+//  4. A Status of 100-399 is success.
+//  5. A Status of 400-499 is client_error.
+//  6. A Status of 500-599 is server_error.
+//  7. Any other Status is unknown.
+//  8. Without a Status, an RDR of 0, a successful delivery, is success.
+//  9. Without either, the outcome is unknown.
+func DeliveryOutcome(msg wrp.Message) string {
+	if rdr := msg.RequestDeliveryResponse; rdr != nil && *rdr != rdrDelivered {
+		if *rdr == rdrExpired {
+			return OutcomeTimeout
+		}
+		return OutcomeDeliveryFailure
+	}
+
+	if msg.Status != nil {
+		switch status := *msg.Status; {
+		case status == 408 || status == 504:
+			return OutcomeTimeout
+		case 100 <= status && status < 400:
+			return OutcomeSuccess
+		case 400 <= status && status < 500:
+			return OutcomeClientError
+		case 500 <= status && status < 600:
+			return OutcomeServerError
+		default:
+			return OutcomeUnknown
+		}
+	}
+
+	if msg.RequestDeliveryResponse != nil {
+		return OutcomeSuccess
+	}
+
+	return OutcomeUnknown
+}
+
+// LogDeliveryOutcome logs the outcome of the delivery as delivery_outcome,
+// classified from the Status and request delivery response by
+// DeliveryOutcome.
+func LogDeliveryOutcome() FieldOpt {
+	return Named("delivery_outcome", KeyDeliveryOutcome, func(msg wrp.Message) zap.Field {
+		return zap.String(fDeliveryOutcome, DeliveryOutcome(msg))
+	})
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+func int64p(i int64) *int64 {
+	return &i
+}
+
+func TestDeliveryOutcome(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   *int64
+		rdr      *int64
+		expected string
+	}{
+		// Neither.
+		{name: "both nil", expected: OutcomeUnknown},
+
+		// Only the RDR.
+		{name: "delivered", rdr: int64p(0), expected: OutcomeSuccess},
+		{name: "failed", rdr: int64p(1), expected: OutcomeDeliveryFailure},
+		{name: "expired", rdr: int64p(2), expected: OutcomeTimeout},
+		{name: "queue full", rdr: int64p(3), expected: OutcomeDeliveryFailure},
+		{name: "offline", rdr: int64p(4), expected: OutcomeDeliveryFailure},
+		{name: "invalid", rdr: int64p(5), expected: OutcomeDeliveryFailure},
+		{name: "unauthorized", rdr: int64p(6), expected: OutcomeDeliveryFailure},
+		{name: "unknown rdr", rdr: int64p(99), expected: OutcomeDeliveryFailure},
+		{name: "negative rdr", rdr: int64p(-1), expected: OutcomeDeliveryFailure},
+
+		// Only the status.
+		{name: "continue", status: int64p(100), expected: OutcomeSuccess},
+		{name: "ok", status: int64p(200), expected: OutcomeSuccess},
+		{name: "accepted", status: int64p(202), expected: OutcomeSuccess},
+		{name: "redirect", status: int64p(307), expected: OutcomeSuccess},
+		{name: "bad request", status: int64p(400), expected: OutcomeClientError},
+		{name: "not found", status: int64p(404), expected: OutcomeClientError},
+		{name: "request timeout", status: int64p(408), expected: OutcomeTimeout},
+		{name: "too many requests", status: int64p(429), expected: OutcomeClientError},
+		{name: "internal error", status: int64p(500), expected: OutcomeServerError},
+		{name: "service unavailable", status: int64p(503), expected: OutcomeServerError},
+		{name: "gateway timeout", status: int64p(504), expected: OutcomeTimeout},
+		{name: "zero status", status: int64p(0), expected: OutcomeUnknown},
+		{name: "negative status", status: int64p(-1), expected: OutcomeUnknown},
+		{name: "status 99", status: int64p(99), expected: OutcomeUnknown},
+		{name: "status 600", status: int64p(600), expected: OutcomeUnknown},
+
+		// Both, agreeing.
+		{name: "delivered ok", status: int64p(200), rdr: int64p(0), expected: OutcomeSuccess},
+		{name: "delivered client error", status: int64p(403), rdr: int64p(0), expected: OutcomeClientError},
+		{name: "delivered server error", status: int64p(502), rdr: int64p(0), expected: OutcomeServerError},
+		{name: "delivered gateway timeout", status: int64p(504), rdr: int64p(0), expected: OutcomeTimeout},
+		{name: "delivered unknown status", status: int64p(0), rdr: int64p(0), expected: OutcomeUnknown},
+
+		// Both, conflicting: a failed delivery outranks the status.
+		{name: "failed with ok", status: int64p(200), rdr: int64p(1), expected: OutcomeDeliveryFailure},
+		{name: "offline with server error", status: int64p(503), rdr: int64p(4), expected: OutcomeDeliveryFailure},
+		{name: "expired with ok", status: int64p(200), rdr: int64p(2), expected: OutcomeTimeout},
+		{name: "expired with client error", status: int64p(400), rdr: int64p(2), expected: OutcomeTimeout},
+		{name: "failed with timeout", status: int64p(408), rdr: int64p(3), expected: OutcomeDeliveryFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := wrp.Message{Status: tt.status, RequestDeliveryResponse: tt.rdr}
+			assert.Equal(t, tt.expected, DeliveryOutcome(msg))
+			assert.Equal(t, zap.String(KeyDeliveryOutcome, tt.expected), LogDeliveryOutcome()(msg))
+		})
+	}
+}
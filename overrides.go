@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// NormalizePartnerID returns the partner ID trimmed and lowercased, the form
+// of the PartnerOverrides keys and of the IDs they are matched against.
+func NormalizePartnerID(id string) string {
+	return strings.ToLower(strings.TrimSpace(id))
+}
+
+// partnerOverride returns the FieldOpts the PartnerOverrides configure for
+// the message.  A message with several PartnerIDs uses the override of the
+// first one, in the order the IDs appear in the message, that has an
+// override; the rest are not consulted.  A message of a partner without an
+// override, or without PartnerIDs, uses the Fields.
+func (ob Observer) partnerOverride(msg *wrp.Message) ([]FieldOpt, bool) {
+	if len(ob.PartnerOverrides) == 0 {
+		return nil, false
+	}
+
+	for _, id := range msg.PartnerIDs {
+		if opts, found := ob.PartnerOverrides[NormalizePartnerID(id)]; found {
+			return opts, true
+		}
+	}
+
+	return nil, false
+}
+
+// hasOverride reports whether the message is logged with an override.  The
+// Debug detail entry is not logged for those messages, since the override is
+// everything the partner allows to be logged.
+func (ob Observer) hasOverride(msg *wrp.Message) bool {
+	_, found := ob.partnerOverride(msg)
+	return found
+}
+
+// validatePartnerOverrides reports the PartnerOverrides keys that are not
+// normalized, since no partner ID would ever match them.
+func (ob Observer) validatePartnerOverrides() error {
+	for id := range ob.PartnerOverrides {
+		if NormalizePartnerID(id) != id {
+			return fmt.Errorf("%w: partner override %q is not normalized", ErrInvalidInput, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestObserver_PartnerOverrides(t *testing.T) {
+	msg := wrp.Message{
+		Source:      "mac:112233445566",
+		Destination: "event:device-status",
+		Payload:     []byte("test payload"),
+	}
+
+	tests := []struct {
+		name       string
+		partnerIDs []string
+		expected   []string
+		detail     bool
+	}{
+		{
+			name:     "no partners",
+			expected: []string{KeySource},
+			detail:   true,
+		}, {
+			name:       "partner without an override",
+			partnerIDs: []string{"other"},
+			expected:   []string{KeySource},
+			detail:     true,
+		}, {
+			name:       "routing only",
+			partnerIDs: []string{"strict"},
+			expected:   []string{KeySource, KeyDestination},
+		}, {
+			name:       "payload preview",
+			partnerIDs: []string{"preview"},
+			expected:   []string{KeySource, KeyPayload},
+		}, {
+			name:       "case and spaces are ignored",
+			partnerIDs: []string{" STRICT "},
+			expected:   []string{KeySource, KeyDestination},
+		}, {
+			name:       "first match wins",
+			partnerIDs: []string{"other", "preview", "strict"},
+			expected:   []string{KeySource, KeyPayload},
+		}, {
+			name:       "first match wins regardless of strictness",
+			partnerIDs: []string{"strict", "preview"},
+			expected:   []string{KeySource, KeyDestination},
+		}, {
+			name:       "empty override",
+			partnerIDs: []string{"silent"},
+			expected:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zapcore.DebugLevel)
+			ob, err := NewObserver(zap.New(core),
+				WithLevel(zap.InfoLevel),
+				WithFields(LogSource()),
+				WithDebugFields(LogPayload()),
+				WithPartnerOverrides(map[string][]FieldOpt{
+					"Strict":  {LogSource(), LogDestination()},
+					"preview": {LogSource(), LogPayload()},
+					"silent":  {},
+				}),
+			)
+			require.NoError(t, err)
+
+			msg := msg
+			msg.PartnerIDs = tt.partnerIDs
+			ob.ObserveWRP(context.Background(), msg)
+
+			entries := recorded.AllUntimed()
+			require.NotEmpty(t, entries)
+			assert.Equal(t, tt.expected, keys(entries[0].Context))
+
+			if tt.detail {
+				assert.Len(t, entries, 2)
+			} else {
+				assert.Len(t, entries, 1, "the detail entry is not logged for overrides")
+			}
+		})
+	}
+}
+
+func TestWithPartnerOverrides(t *testing.T) {
+	overrides := map[string][]FieldOpt{
+		"Partner": {LogSource()},
+	}
+
+	ob, err := NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithPartnerOverrides(overrides))
+	require.NoError(t, err)
+	assert.Equal(t, "level=info message=\"\" fields=[] partner_overrides=[partner=[source]]", ob.String())
+
+	// Changes to the map and the slices don't reach the Observer.
+	overrides["Partner"][0] = LogDestination()
+	overrides["other"] = []FieldOpt{LogPayload()}
+	assert.Len(t, ob.PartnerOverrides, 1)
+	assert.Equal(t, []string{"source"}, fieldOptNames(ob.PartnerOverrides["partner"]))
+
+	_, err = NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithPartnerOverrides(map[string][]FieldOpt{
+		"partner": {LogSource()},
+		"PARTNER": {LogDestination()},
+	}))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithPartnerOverrides(map[string][]FieldOpt{
+		"partner":   {LogSource()},
+		" partner ": {LogDestination()},
+	}))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	// Keys are trimmed as well as lowercased, the same as the IDs they are
+	// matched against.
+	ob, err = NewObserver(zap.NewNop(), WithLevel(zap.InfoLevel), WithPartnerOverrides(map[string][]FieldOpt{
+		" Acme ": {LogDestination()},
+	}))
+	require.NoError(t, err)
+	opts, found := ob.partnerOverride(&wrp.Message{PartnerIDs: []string{"ACME"}})
+	assert.True(t, found)
+	assert.Equal(t, []string{"dest"}, fieldOptNames(opts))
+}
+
+func TestNormalizePartnerID(t *testing.T) {
+	assert.Equal(t, "acme", NormalizePartnerID(" Acme\t"))
+	assert.Equal(t, "acme", NormalizePartnerID("acme"))
+	assert.Empty(t, NormalizePartnerID("  "))
+}
+
+func TestObserver_PartnerOverrides_Batch(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	ob := Observer{
+		Logger:           zap.New(core),
+		Fields:           []FieldOpt{LogSource()},
+		PartnerOverrides: map[string][]FieldOpt{"strict": {LogDestination()}},
+	}
+
+	ob.ObserveWRPBatch(context.Background(), []wrp.Message{
+		{Source: "mac:112233445566", Destination: "event:a"},
+		{Source: "mac:112233445566", Destination: "event:b", PartnerIDs: []string{"strict"}},
+	})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, []any{
+		map[string]any{KeySource: "mac:112233445566"},
+		map[string]any{KeyDestination: "event:b"},
+	}, entries[0].ContextMap()[KeyMessages])
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogDestinationPartnerMismatch logs whether the partner the destination
+// belongs to is missing from the message's PartnerIDs, which points to a
+// routing bug.  The resolver maps the destination to its partner and returns
+// an empty string when the partner is not known; an unknown partner is never
+// a mismatch.  Partner IDs are compared case-insensitively.
+//
+// A nil resolver makes the FieldOpt log nothing.
+func LogDestinationPartnerMismatch(resolver func(destination string) string) FieldOpt {
+	if resolver == nil {
+		return Named("dest_partner_mismatch", KeyDestinationPartnerMismatch, nil)
+	}
+
+	return Named("dest_partner_mismatch", KeyDestinationPartnerMismatch, func(msg wrp.Message) zap.Field {
+		partner := resolver(msg.Destination)
+		if partner == "" {
+			return zap.Bool(fDestinationPartnerMismatch, false)
+		}
+
+		for _, id := range msg.PartnerIDs {
+			if strings.EqualFold(id, partner) {
+				return zap.Bool(fDestinationPartnerMismatch, false)
+			}
+		}
+		return zap.Bool(fDestinationPartnerMismatch, true)
+	})
+}
+
+// normalizePartnerIDs returns the partner IDs trimmed, lowercased and without
+// duplicates or empty IDs, in the order they were first seen, and whether
+// that changed the list.
+func normalizePartnerIDs(ids []string) ([]string, bool) {
+	normalized := make([]string, 0, len(ids))
+	changed := false
+	for _, id := range ids {
+		n := NormalizePartnerID(id)
+		if n != id {
+			changed = true
+		}
+		if n == "" || slices.Contains(normalized, n) {
+			changed = true
+			continue
+		}
+		normalized = append(normalized, n)
+	}
+
+	return normalized, changed
+}
+
+// LogPartnerIDsNormalized logs the partner IDs trimmed of whitespace,
+// lowercased and with duplicates and empty IDs removed, keeping the order the
+// IDs were first seen in, as partner_ids_normalized.  Whether that changed
+// the list is logged as partner_ids_changed.  LogPartnerIDs logs the list as
+// it was received.
+func LogPartnerIDsNormalized() FieldOpt {
+	return Named("partner_ids_normalized", KeyPartnerIDsNormalized+","+KeyPartnerIDsChanged, Appender(FieldAppenderFunc(func(msg wrp.Message, fields []zap.Field) []zap.Field {
+		ids, changed := normalizePartnerIDs(msg.PartnerIDs)
+		return append(fields,
+			zap.Bool(fPartnerIDsChanged, changed),
+			zap.Strings(fPartnerIDsNormalized, ids),
+		)
+	})))
+}
+
+// partnerIDsArray returns an ArrayMarshaler for the partner IDs.  Storing a
+// slice in an interface allocates, since a slice is larger than a pointer,
+// so short slices are converted to pointers to arrays of their elements,
+// which refer to the same memory and are stored without allocating.
+func partnerIDsArray(ids []string) zapcore.ArrayMarshaler {
+	switch len(ids) {
+	case 0:
+		return (*stringArray1)(nil)
+	case 1:
+		return (*stringArray1)(ids)
+	case 2:
+		return (*stringArray2)(ids)
+	case 3:
+		return (*stringArray3)(ids)
+	case 4:
+		return (*stringArray4)(ids)
+	default:
+		s := stringArray(ids)
+		return &s
+	}
+}
+
+type (
+	stringArray1 [1]string
+	stringArray2 [2]string
+	stringArray3 [3]string
+	stringArray4 [4]string
+)
+
+func (a *stringArray1) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	if a != nil {
+		return appendStrings(enc, a[:])
+	}
+	return nil
+}
+
+func (a *stringArray2) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return appendStrings(enc, a[:])
+}
+
+func (a *stringArray3) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return appendStrings(enc, a[:])
+}
+
+func (a *stringArray4) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return appendStrings(enc, a[:])
+}
+
+func appendStrings(enc zapcore.ArrayEncoder, s []string) error {
+	for _, v := range s {
+		enc.AppendString(v)
+	}
+	return nil
+}
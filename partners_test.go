@@ -0,0 +1,224 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogDestinationPartnerMismatch(t *testing.T) {
+	resolver := func(dest string) string {
+		switch {
+		case strings.HasPrefix(dest, "mac:112233445566"):
+			return "comcast"
+		case strings.HasPrefix(dest, "mac:665544332211"):
+			return "sky"
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name     string
+		resolver func(string) string
+		msg      wrp.Message
+		expected zap.Field
+	}{
+		{
+			name:     "nil resolver",
+			msg:      wrp.Message{Destination: "mac:112233445566"},
+			expected: zap.Skip(),
+		}, {
+			name:     "match",
+			resolver: resolver,
+			msg: wrp.Message{
+				Destination: "mac:112233445566/config",
+				PartnerIDs:  []string{"sky", "comcast"},
+			},
+			expected: zap.Bool(KeyDestinationPartnerMismatch, false),
+		}, {
+			name:     "case insensitive match",
+			resolver: resolver,
+			msg: wrp.Message{
+				Destination: "mac:112233445566/config",
+				PartnerIDs:  []string{"Comcast"},
+			},
+			expected: zap.Bool(KeyDestinationPartnerMismatch, false),
+		}, {
+			name:     "mismatch",
+			resolver: resolver,
+			msg: wrp.Message{
+				Destination: "mac:665544332211/config",
+				PartnerIDs:  []string{"comcast"},
+			},
+			expected: zap.Bool(KeyDestinationPartnerMismatch, true),
+		}, {
+			name:     "empty partner IDs with a known partner",
+			resolver: resolver,
+			msg: wrp.Message{
+				Destination: "mac:665544332211/config",
+			},
+			expected: zap.Bool(KeyDestinationPartnerMismatch, true),
+		}, {
+			name:     "empty partner IDs with an unknown partner",
+			resolver: resolver,
+			msg: wrp.Message{
+				Destination: "event:device-status",
+			},
+			expected: zap.Bool(KeyDestinationPartnerMismatch, false),
+		}, {
+			name:     "unknown partner",
+			resolver: resolver,
+			msg: wrp.Message{
+				Destination: "event:device-status",
+				PartnerIDs:  []string{"comcast"},
+			},
+			expected: zap.Bool(KeyDestinationPartnerMismatch, false),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, LogDestinationPartnerMismatch(tt.resolver)(tt.msg))
+		})
+	}
+}
+
+func TestLogPartnerIDsNormalized(t *testing.T) {
+	tests := []struct {
+		name     string
+		ids      []string
+		expected []any
+		changed  bool
+	}{
+		{name: "nil", expected: []any{}},
+		{name: "already normalized", ids: []string{"comcast", "sky"}, expected: []any{"comcast", "sky"}},
+		{
+			name:     "buggy client",
+			ids:      []string{"Comcast", "comcast ", "comcast"},
+			expected: []any{"comcast"},
+			changed:  true,
+		}, {
+			name:     "first seen order",
+			ids:      []string{"sky", "Comcast", "SKY"},
+			expected: []any{"sky", "comcast"},
+			changed:  true,
+		}, {
+			name:     "exact duplicate",
+			ids:      []string{"sky", "sky"},
+			expected: []any{"sky"},
+			changed:  true,
+		}, {
+			name:     "empty ids",
+			ids:      []string{"", " ", "sky"},
+			expected: []any{"sky"},
+			changed:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := wrp.Message{PartnerIDs: tt.ids}
+			got := fieldMap(LogPartnerIDsNormalized(), msg)
+			assert.Equal(t, map[string]any{
+				KeyPartnerIDsNormalized: tt.expected,
+				KeyPartnerIDsChanged:    tt.changed,
+			}, got)
+
+			// The original list is untouched.
+			assert.Equal(t, encode(zap.Strings(KeyPartnerIDs, tt.ids)), encode(LogPartnerIDs()(msg)))
+		})
+	}
+}
+
+func TestLogPartnerIDs_Golden(t *testing.T) {
+	tests := []struct {
+		name   string
+		ids    []string
+		golden string
+	}{
+		{name: "nil", golden: `{"partner_ids":[]}`},
+		{name: "empty", ids: []string{}, golden: `{"partner_ids":[]}`},
+		{name: "one", ids: []string{"comcast"}, golden: `{"partner_ids":["comcast"]}`},
+		{name: "two", ids: []string{"comcast", "sky"}, golden: `{"partner_ids":["comcast","sky"]}`},
+		{name: "three", ids: []string{"a", "", "c"}, golden: `{"partner_ids":["a","","c"]}`},
+		{name: "four", ids: []string{"a", "b", "c", "d"}, golden: `{"partner_ids":["a","b","c","d"]}`},
+		{name: "five", ids: []string{"a", "b", "c", "d", "e"}, golden: `{"partner_ids":["a","b","c","d","e"]}`},
+		{name: "escaped", ids: []string{`"quoted"`, "tab\t", "é"}, golden: `{"partner_ids":["\"quoted\"","tab\t","é"]}`},
+	}
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{LogPartnerIDs()(wrp.Message{PartnerIDs: tt.ids})})
+			require.NoError(t, err)
+			assert.Equal(t, tt.golden+"\n", got.String())
+
+			// The encoding is the same as the zap.Strings encoding used before.
+			want, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{zap.Strings(KeyPartnerIDs, tt.ids)})
+			require.NoError(t, err)
+			assert.Equal(t, want.String(), got.String())
+		})
+	}
+}
+
+func TestLogPartnerIDs_Allocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector makes sync.Pool drop items")
+	}
+
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), zapcore.AddSync(io.Discard), zap.InfoLevel)
+
+	observe := func(ids []string, opts ...FieldOpt) float64 {
+		ob := Observer{Logger: zap.New(core), Fields: opts}
+		msg := wrp.Message{PartnerIDs: ids}
+		return testing.AllocsPerRun(100, func() { ob.ObserveWRPPtr(context.Background(), &msg) })
+	}
+
+	for _, ids := range [][]string{nil, {"a"}, {"a", "b"}, {"a", "b", "c"}, {"a", "b", "c", "d"}} {
+		assert.Equal(t, observe(ids, NoFields()...), observe(ids, LogPartnerIDs()), "%d partner IDs", len(ids))
+	}
+
+	// Longer lists are stored in the field as a slice.
+	ids := []string{"a", "b", "c", "d", "e"}
+	assert.Equal(t, observe(ids, NoFields()...)+1, observe(ids, LogPartnerIDs()))
+}
+
+func BenchmarkLogPartnerIDs(b *testing.B) {
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), zapcore.AddSync(io.Discard), zap.InfoLevel)
+	msg := wrp.Message{PartnerIDs: []string{"comcast"}}
+
+	benchmarks := []struct {
+		name string
+		opt  FieldOpt
+	}{
+		{
+			name: "strings",
+			opt: func(msg wrp.Message) zap.Field {
+				return zap.Strings(fPartnerIDs, msg.PartnerIDs)
+			},
+		}, {
+			name: "array",
+			opt:  LogPartnerIDs(),
+		},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			ob := Observer{Logger: zap.New(core), Fields: []FieldOpt{bm.opt}}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ob.ObserveWRPPtr(context.Background(), &msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// UnknownPartner is the partner the messages without partner IDs are
+	// summarized under.
+	UnknownPartner = "unknown"
+
+	// OtherPartners is the partner the messages of the partners seen after
+	// the PartnerSummaryObserver's limit was reached are summarized under.
+	OtherPartners = "other"
+)
+
+// PartnerSummaryObserver periodically logs a summary of the messages of each
+// partner, instead of logging each message.  A message belongs to its first
+// partner ID, or to UnknownPartner when it has none.  Every interval, one
+// entry is logged for each partner seen during it, using the Observer's
+// Logger, Level and Message, with:
+//
+//   - the partner as partner_id
+//   - the number of messages as message_count
+//   - the total size of their payloads as payload_bytes
+//   - the number of messages of each type as msg_types
+//
+// The entries are logged in partner order.  An interval without messages
+// logs nothing.
+//
+// At most maxPartners partners are summarized separately in an interval.  The
+// messages of the partners seen after that are summarized together under
+// OtherPartners, so memory stays bounded whatever partner IDs the messages
+// claim.
+type PartnerSummaryObserver struct {
+	ob          Observer
+	maxPartners int
+
+	lock     sync.Mutex
+	partners map[string]*partnerSummary
+	closed   bool
+
+	ticker Timer
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewPartnerSummaryObserver creates a PartnerSummaryObserver that logs the
+// partner summaries every interval, summarizing at most maxPartners partners
+// separately.  maxPartners and interval must be positive.  Close must be
+// called to stop the PartnerSummaryObserver.
+func NewPartnerSummaryObserver(ob Observer, maxPartners int, interval time.Duration) (*PartnerSummaryObserver, error) {
+	if ob.Logger == nil {
+		return nil, fmt.Errorf("%w: logger is nil", ErrInvalidInput)
+	}
+	if maxPartners < 1 {
+		return nil, fmt.Errorf("%w: maxPartners must be positive", ErrInvalidInput)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: interval must be positive", ErrInvalidInput)
+	}
+
+	p := &PartnerSummaryObserver{
+		ob:          ob,
+		maxPartners: maxPartners,
+		partners:    make(map[string]*partnerSummary),
+		ticker:      ob.timers().NewTicker(interval),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	go p.run()
+
+	return p, nil
+}
+
+// ObserveWRP adds the message to its partner's summary.  Messages the
+// Observer's Filter rejects are not summarized.  Messages observed after
+// Close are dropped and reported to the Observer's OnError as ErrClosed.
+func (p *PartnerSummaryObserver) ObserveWRP(_ context.Context, msg wrp.Message) {
+	p.ob.observed(&msg)
+
+	if p.ob.Filter != nil && !p.ob.Filter(msg) {
+		return
+	}
+
+	if !p.add(&msg) {
+		p.ob.reportError(fmt.Errorf("%w: partner summary message dropped", ErrClosed))
+	}
+}
+
+// add adds the message to its partner's summary.  It returns false when the
+// PartnerSummaryObserver is closed.
+func (p *PartnerSummaryObserver) add(msg *wrp.Message) bool {
+	partner := UnknownPartner
+	if len(msg.PartnerIDs) > 0 {
+		partner = msg.PartnerIDs[0]
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.closed {
+		return false
+	}
+
+	s, found := p.partners[partner]
+	if !found {
+		if len(p.partners) >= p.maxPartners {
+			partner = OtherPartners
+			s, found = p.partners[partner]
+		}
+		if !found {
+			s = &partnerSummary{partner: partner}
+			p.partners[partner] = s
+		}
+	}
+
+	s.count++
+	s.bytes += uint64(len(msg.Payload))
+	s.types.next(msg.Type)
+	return true
+}
+
+// Close stops the periodic reports and logs the final one.  Messages
+// observed after Close are dropped.  The context is not used, since the final
+// report doesn't wait on anything.
+func (p *PartnerSummaryObserver) Close(context.Context) error {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.lock.Unlock()
+
+	close(p.stop)
+	<-p.done
+
+	p.report()
+	return nil
+}
+
+func (p *PartnerSummaryObserver) run() {
+	defer close(p.done)
+
+	defer p.ticker.Stop()
+
+	for {
+		select {
+		case <-p.ticker.C():
+			p.report()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// report logs the summary of each partner and starts a new interval.
+func (p *PartnerSummaryObserver) report() {
+	p.lock.Lock()
+	partners := p.partners
+	p.partners = make(map[string]*partnerSummary, len(partners))
+	p.lock.Unlock()
+
+	summaries := make([]*partnerSummary, 0, len(partners))
+	for _, s := range partners {
+		summaries = append(summaries, s)
+	}
+	slices.SortFunc(summaries, func(a, b *partnerSummary) int {
+		return cmp.Compare(a.partner, b.partner)
+	})
+
+	level := p.ob.baseLevel()
+	for _, s := range summaries {
+		ce := p.ob.Logger.Check(level, p.ob.Message)
+		if ce == nil {
+			return
+		}
+
+		ce.Write(p.ob.withSeq(
+			zap.String(fPartnerID, s.partner),
+			zap.Uint64(fMessageCount, s.count),
+			zap.Uint64(fPayloadBytes, s.bytes),
+			zap.Object(fMsgTypes, &s.types),
+		)...)
+		p.ob.emitted(level)
+	}
+}
+
+// partnerSummary is the summary of a partner's messages in an interval.
+type partnerSummary struct {
+	partner string
+	count   uint64
+	bytes   uint64
+	types   typeCounters
+}
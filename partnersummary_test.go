@@ -0,0 +1,207 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewPartnerSummaryObserver(t *testing.T) {
+	ob := Observer{Logger: zap.NewNop()}
+
+	tests := []struct {
+		name        string
+		ob          Observer
+		maxPartners int
+		interval    time.Duration
+	}{
+		{name: "no logger", maxPartners: 1, interval: time.Second},
+		{name: "zero maxPartners", ob: ob, interval: time.Second},
+		{name: "zero interval", ob: ob, maxPartners: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPartnerSummaryObserver(tt.ob, tt.maxPartners, tt.interval)
+			assert.ErrorIs(t, err, ErrInvalidInput)
+			assert.Nil(t, p)
+		})
+	}
+}
+
+// partnerSummaries returns the fields of the summary entries.
+func partnerSummaries(entries []observer.LoggedEntry) []map[string]any {
+	summaries := make([]map[string]any, 0, len(entries))
+	for _, entry := range entries {
+		summaries = append(summaries, entry.ContextMap())
+	}
+	return summaries
+}
+
+func TestPartnerSummaryObserver(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	var reported []error
+	p, err := NewPartnerSummaryObserver(Observer{
+		Logger:  zap.New(core),
+		Message: "partner summary",
+		Filter:  func(msg wrp.Message) bool { return msg.Destination != "filtered" },
+		OnError: func(err error) { reported = append(reported, err) },
+	}, 10, time.Hour)
+	require.NoError(t, err)
+
+	msgs := []wrp.Message{
+		{Type: wrp.SimpleEventMessageType, PartnerIDs: []string{"comcast", "sky"}, Payload: []byte("12345")},
+		{Type: wrp.SimpleEventMessageType, PartnerIDs: []string{"comcast"}, Payload: []byte("123")},
+		{Type: wrp.SimpleRequestResponseMessageType, PartnerIDs: []string{"comcast"}},
+		{Type: wrp.SimpleEventMessageType, PartnerIDs: []string{"sky", "comcast"}, Payload: []byte("1234567")},
+		{Type: wrp.SimpleEventMessageType, PartnerIDs: []string{"sky"}, Destination: "filtered", Payload: []byte("12")},
+	}
+	for _, msg := range msgs {
+		p.ObserveWRP(context.Background(), msg)
+	}
+	require.NoError(t, p.Close(context.Background()))
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, "partner summary", entry.Message)
+	}
+	assert.Equal(t, []map[string]any{
+		{
+			KeyPartnerID:    "comcast",
+			KeyMessageCount: uint64(3),
+			KeyPayloadBytes: uint64(8),
+			KeyMsgTypes:     map[string]any{"SimpleEvent": uint64(2), "SimpleRequestResponse": uint64(1)},
+		}, {
+			KeyPartnerID:    "sky",
+			KeyMessageCount: uint64(1),
+			KeyPayloadBytes: uint64(7),
+			KeyMsgTypes:     map[string]any{"SimpleEvent": uint64(1)},
+		},
+	}, partnerSummaries(entries))
+
+	// Messages after Close are dropped and reported, and closing again does
+	// nothing.
+	p.ObserveWRP(context.Background(), wrp.Message{PartnerIDs: []string{"comcast"}})
+	require.NoError(t, p.Close(context.Background()))
+	assert.Len(t, recorded.AllUntimed(), 2)
+	require.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0], ErrClosed)
+}
+
+func TestPartnerSummaryObserver_UnknownPartner(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	p, err := NewPartnerSummaryObserver(Observer{Logger: zap.New(core)}, 10, time.Hour)
+	require.NoError(t, err)
+
+	p.ObserveWRP(context.Background(), wrp.Message{Type: wrp.SimpleEventMessageType})
+	p.ObserveWRP(context.Background(), wrp.Message{Type: wrp.MessageType(99), PartnerIDs: []string{}})
+	require.NoError(t, p.Close(context.Background()))
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{
+		KeyPartnerID:    UnknownPartner,
+		KeyMessageCount: uint64(2),
+		KeyPayloadBytes: uint64(0),
+		KeyMsgTypes:     map[string]any{"SimpleEvent": uint64(1), "other": uint64(1)},
+	}, entries[0].ContextMap())
+}
+
+func TestPartnerSummaryObserver_Intervals(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	p, err := NewPartnerSummaryObserver(Observer{Logger: zap.New(core)}, 10, time.Hour)
+	require.NoError(t, err)
+	defer p.Close(context.Background())
+
+	p.ObserveWRP(context.Background(), wrp.Message{PartnerIDs: []string{"a"}})
+	p.report()
+
+	// An interval without messages logs nothing.
+	p.report()
+
+	p.ObserveWRP(context.Background(), wrp.Message{PartnerIDs: []string{"b"}})
+	p.report()
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "a", entries[0].ContextMap()[KeyPartnerID])
+	assert.Equal(t, "b", entries[1].ContextMap()[KeyPartnerID])
+	assert.Equal(t, uint64(1), entries[1].ContextMap()[KeyMessageCount])
+}
+
+func TestPartnerSummaryObserver_Periodic(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	clock := newFakeClock(time.UnixMilli(0))
+	hook, writes := written()
+	p, err := NewPartnerSummaryObserver(Observer{Logger: zap.New(core, hook), Clock: clock}, 10, time.Minute)
+	require.NoError(t, err)
+	defer p.Close(context.Background())
+
+	for _, partner := range []string{"a", "b"} {
+		p.ObserveWRP(context.Background(), wrp.Message{PartnerIDs: []string{partner}})
+		clock.Add(time.Minute)
+		<-writes
+	}
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "a", entries[0].ContextMap()[KeyPartnerID])
+	assert.Equal(t, "b", entries[1].ContextMap()[KeyPartnerID])
+}
+
+func TestPartnerSummaryObserver_Bounded(t *testing.T) {
+	const (
+		maxPartners = 3
+		distinct    = 1000
+	)
+
+	core, recorded := observer.New(zapcore.DebugLevel)
+	p, err := NewPartnerSummaryObserver(Observer{Logger: zap.New(core)}, maxPartners, time.Hour)
+	require.NoError(t, err)
+
+	for i := range distinct {
+		p.ObserveWRP(context.Background(), wrp.Message{PartnerIDs: []string{"partner-" + strconv.Itoa(i)}})
+	}
+
+	// A partner summarized separately stays separate.
+	p.ObserveWRP(context.Background(), wrp.Message{PartnerIDs: []string{"partner-0"}})
+
+	p.lock.Lock()
+	assert.Len(t, p.partners, maxPartners+1)
+	p.lock.Unlock()
+
+	require.NoError(t, p.Close(context.Background()))
+
+	counts := make(map[string]uint64)
+	for _, summary := range partnerSummaries(recorded.AllUntimed()) {
+		counts[summary[KeyPartnerID].(string)] = summary[KeyMessageCount].(uint64)
+	}
+	assert.Equal(t, map[string]uint64{
+		"partner-0":   2,
+		"partner-1":   1,
+		"partner-2":   1,
+		OtherPartners: distinct - maxPartners,
+	}, counts)
+}
+
+func TestPartnerSummaryObserver_DisabledLevel(t *testing.T) {
+	core, recorded := observer.New(zapcore.InfoLevel)
+	p, err := NewPartnerSummaryObserver(Observer{Logger: zap.New(core), Level: AtLevel(zap.DebugLevel)}, 10, time.Hour)
+	require.NoError(t, err)
+
+	p.ObserveWRP(context.Background(), wrp.Message{PartnerIDs: []string{"a"}})
+	require.NoError(t, p.Close(context.Background()))
+	assert.Zero(t, recorded.Len())
+}
@@ -0,0 +1,279 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// notChecked is logged in place of a result when a check was skipped.
+const notChecked = "not_checked"
+
+// isJSONContentType reports whether the content type describes JSON, either
+// application/json or a +json structured syntax suffix.  Parameters such as
+// the charset are ignored.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	return mediaType == wrp.MimeTypeJson ||
+		(strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json"))
+}
+
+// LogPayloadIsValidJSON logs whether the payload is valid JSON when the
+// content type says the payload is JSON.  For other content types true is
+// logged, since the payload doesn't claim to be JSON.
+//
+// Validating the payload costs time proportional to its size; see
+// LogPayloadIsValidJSONMax to bound it.  The payload is validated when the
+// entry is encoded, so entries that are dropped don't pay for it.
+func LogPayloadIsValidJSON() FieldOpt {
+	return LogPayloadIsValidJSONMax(0)
+}
+
+// LogPayloadIsValidJSONMax is LogPayloadIsValidJSON with a limit on the size
+// of the payloads that are validated.  Larger payloads are not validated and
+// "not_checked" is logged instead.  A max of zero means there is no limit.
+func LogPayloadIsValidJSONMax(max int) FieldOpt {
+	return Named("payload_valid_json", KeyPayloadIsValidJSON, func(msg wrp.Message) zap.Field {
+		if !isJSONContentType(msg.ContentType) {
+			return zap.Bool(fPayloadIsValidJSON, true)
+		}
+		if max > 0 && len(msg.Payload) > max {
+			return zap.String(fPayloadIsValidJSON, notChecked)
+		}
+
+		payload := msg.Payload
+		return lazy(fPayloadIsValidJSON, scalarFieldSize(fPayloadIsValidJSON), func(enc zapcore.ObjectEncoder) {
+			enc.AddBool(fPayloadIsValidJSON, json.Valid(payload))
+		})
+	})
+}
+
+const (
+	gzipHeaderSize  = 10
+	gzipTrailerSize = 8
+)
+
+// gzipISize returns the uncompressed size recorded in the trailer of a gzip
+// payload.  ok is false when the payload is not gzip or is too short to hold
+// the gzip header and trailer.
+func gzipISize(payload []byte) (size uint32, ok bool) {
+	if len(payload) < gzipHeaderSize+gzipTrailerSize {
+		return 0, false
+	}
+
+	// The magic number followed by the deflate compression method.
+	if payload[0] != 0x1f || payload[1] != 0x8b || payload[2] != 8 {
+		return 0, false
+	}
+
+	return binary.LittleEndian.Uint32(payload[len(payload)-4:]), true
+}
+
+// LogPayloadDecompressedSize logs the size of a gzip payload once
+// decompressed, read from the gzip trailer without decompressing the payload.
+// -1 is logged when the payload is not gzip or is too short to be.
+//
+// The gzip trailer records the size modulo 2^32 and only for the last member
+// of the stream, so the value is only exact for single member payloads under
+// 4GiB.
+func LogPayloadDecompressedSize() FieldOpt {
+	return Named("payload_decompressed_size", KeyPayloadDecompressedSize, func(msg wrp.Message) zap.Field {
+		size, ok := gzipISize(msg.Payload)
+		if !ok {
+			return zap.Int64(fPayloadDecompressedSize, -1)
+		}
+		return zap.Int64(fPayloadDecompressedSize, int64(size))
+	})
+}
+
+// LogPayloadPreviewLines logs the first n lines of a text payload as
+// payload_preview, joined with "\n", along with the number of lines in the
+// payload as payload_lines.  A "\r\n" line ending is treated the same as
+// "\n", and a final line ending doesn't start another line.
+//
+// The payload is only treated as text when it is valid UTF-8.  Otherwise only
+// the payload_size is logged.  The lines are found when the entry is encoded.
+func LogPayloadPreviewLines(n int) FieldOpt {
+	n = max(n, 0)
+
+	return Named("payload_preview_lines", KeyPayloadPreview+","+KeyPayloadLines, func(msg wrp.Message) zap.Field {
+		payload := msg.Payload
+
+		// The preview is at most the whole payload.
+		preview := len(payload)
+		if n == 0 {
+			preview = 0
+		}
+		size := stringSize(fPayloadPreview, preview) + scalarFieldSize(fPayloadLines)
+
+		return lazy(fPayloadPreview, size, func(enc zapcore.ObjectEncoder) {
+			if !utf8.Valid(payload) {
+				enc.AddInt(fPayloadSize, len(payload))
+				return
+			}
+
+			preview, lines := previewLines(payload, n)
+			enc.AddString(fPayloadPreview, preview)
+			enc.AddInt(fPayloadLines, lines)
+		})
+	})
+}
+
+// previewLines returns the first n lines of the text joined with "\n", and
+// the total number of lines.
+func previewLines(text []byte, n int) (string, int) {
+	var (
+		b     strings.Builder
+		lines int
+	)
+
+	for len(text) > 0 {
+		line, rest, _ := bytes.Cut(text, []byte("\n"))
+		line = bytes.TrimSuffix(line, []byte("\r"))
+
+		if lines < n {
+			if lines > 0 {
+				b.WriteByte('\n')
+			}
+			b.Write(line)
+		}
+
+		lines++
+		text = rest
+	}
+
+	return b.String(), lines
+}
+
+const (
+	// binarySampleSize is the most bytes of a payload LogPayloadIsBinary
+	// inspects.
+	binarySampleSize = 512
+
+	// binaryThreshold is the fraction of the characters inspected that must
+	// be non-printable for LogPayloadIsBinary to consider the payload binary.
+	binaryThreshold = 0.05
+)
+
+// LogPayloadIsBinary logs whether the payload looks like binary data rather
+// than text, inspecting at most the first 512 bytes.  A payload is binary if
+// it contains a NUL byte, or if more than 5% of the characters are not
+// printable.  Control characters other than tab, line feed, carriage return
+// and form feed are not printable, and neither are bytes that are not valid
+// UTF-8.  An empty payload is not binary.
+func LogPayloadIsBinary() FieldOpt {
+	return Named("payload_is_binary", KeyPayloadIsBinary, func(msg wrp.Message) zap.Field {
+		return zap.Bool(fPayloadIsBinary, isBinary(msg.Payload))
+	})
+}
+
+func isBinary(payload []byte) bool {
+	sample := payload[:min(len(payload), binarySampleSize)]
+	truncated := len(sample) < len(payload)
+
+	var chars, odd int
+	for len(sample) > 0 {
+		// A character cut off by the end of the sample isn't counted.
+		if truncated && !utf8.FullRune(sample) {
+			break
+		}
+
+		r, size := utf8.DecodeRune(sample)
+		sample = sample[size:]
+		chars++
+
+		switch {
+		case r == 0:
+			return true
+		case r == utf8.RuneError && size == 1:
+			odd++
+		case r < 0x20 && r != '\t' && r != '\n' && r != '\r' && r != '\f':
+			odd++
+		case r == 0x7f:
+			odd++
+		}
+	}
+
+	return chars > 0 && float64(odd) > binaryThreshold*float64(chars)
+}
+
+// LogPayloadSampled logs the payload, as LogPayload does, for the fraction of
+// the messages given by rate, and the fallback FieldOpt, typically
+// LogPayloadSize, for the rest.  Whether the payload was logged is logged as
+// payload_sampled.  A rate of 0 or less never logs the payload and a rate of
+// 1 or more always does.  A nil fallback logs nothing in place of the
+// payload.
+//
+// The messages are chosen by a hash of their transaction UUID rather than at
+// random, so a message and its retries are either all sampled or none are.
+// Messages without a transaction UUID are chosen by a hash of their payload.
+func LogPayloadSampled(rate float64, fallback FieldOpt) FieldOpt {
+	var threshold uint64
+	all := rate >= 1
+	if rate > 0 && !all {
+		threshold = uint64(math.Ldexp(rate, 64))
+	}
+
+	key := KeyPayload + "," + KeyPayloadSampled
+	if k := fallback.Key(); k != "" {
+		key = KeyPayload + "," + k + "," + KeyPayloadSampled
+	}
+
+	payload := LogPayload()
+	return Named("payload_sampled", key, Appender(FieldAppenderFunc(func(msg wrp.Message, fields []zap.Field) []zap.Field {
+		sampled := all || (threshold > 0 && sampleHash(&msg) < threshold)
+		switch {
+		case sampled:
+			fields = payload.AppendFields(msg, fields)
+		case fallback != nil:
+			fields = fallback.AppendFields(msg, fields)
+		}
+		return append(fields, zap.Bool(fPayloadSampled, sampled))
+	})))
+}
+
+// sampleHash returns the 64-bit FNV-1a hash of the message's transaction
+// UUID, or of its payload when it has none, with the bits mixed by mix64.
+func sampleHash(msg *wrp.Message) uint64 {
+	const (
+		offset = 14695981039346656037
+		prime  = 1099511628211
+	)
+
+	h := uint64(offset)
+	if msg.TransactionUUID != "" {
+		for i := 0; i < len(msg.TransactionUUID); i++ {
+			h = (h ^ uint64(msg.TransactionUUID[i])) * prime
+		}
+		return mix64(h)
+	}
+
+	for _, b := range msg.Payload {
+		h = (h ^ uint64(b)) * prime
+	}
+	return mix64(h)
+}
+
+// mix64 is the murmur3 finalizer.  FNV-1a barely changes the high bits for
+// the last bytes hashed, and UUIDs often differ only there, so the bits are
+// mixed before the hash is compared with the threshold.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
@@ -0,0 +1,413 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+func TestLogPayloadIsValidJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		opt         FieldOpt
+		contentType string
+		payload     string
+		expected    zap.Field
+	}{
+		{
+			name:        "valid",
+			opt:         LogPayloadIsValidJSON(),
+			contentType: "application/json",
+			payload:     `{"a": [1, 2]}`,
+			expected:    zap.Bool(KeyPayloadIsValidJSON, true),
+		}, {
+			name:        "truncated",
+			opt:         LogPayloadIsValidJSON(),
+			contentType: "application/json",
+			payload:     `{"a": [1, 2`,
+			expected:    zap.Bool(KeyPayloadIsValidJSON, false),
+		}, {
+			name:        "empty",
+			opt:         LogPayloadIsValidJSON(),
+			contentType: "application/json",
+			expected:    zap.Bool(KeyPayloadIsValidJSON, false),
+		}, {
+			name:        "doubly encoded is a valid string",
+			opt:         LogPayloadIsValidJSON(),
+			contentType: "application/json",
+			payload:     `"{\"a\": 1}"`,
+			expected:    zap.Bool(KeyPayloadIsValidJSON, true),
+		}, {
+			name:        "parameters and case",
+			opt:         LogPayloadIsValidJSON(),
+			contentType: " Application/JSON; charset=utf-8",
+			payload:     `{"a"`,
+			expected:    zap.Bool(KeyPayloadIsValidJSON, false),
+		}, {
+			name:        "suffix",
+			opt:         LogPayloadIsValidJSON(),
+			contentType: "application/vnd.api+json",
+			payload:     `nope`,
+			expected:    zap.Bool(KeyPayloadIsValidJSON, false),
+		}, {
+			name:        "not json",
+			opt:         LogPayloadIsValidJSON(),
+			contentType: "application/msgpack",
+			payload:     `{"a"`,
+			expected:    zap.Bool(KeyPayloadIsValidJSON, true),
+		}, {
+			name:     "no content type",
+			opt:      LogPayloadIsValidJSON(),
+			payload:  `{"a"`,
+			expected: zap.Bool(KeyPayloadIsValidJSON, true),
+		}, {
+			name:        "at the limit",
+			opt:         LogPayloadIsValidJSONMax(8),
+			contentType: "application/json",
+			payload:     `{"a": 1}`,
+			expected:    zap.Bool(KeyPayloadIsValidJSON, true),
+		}, {
+			name:        "over the limit",
+			opt:         LogPayloadIsValidJSONMax(8),
+			contentType: "application/json",
+			payload:     `{"a": 10}`,
+			expected:    zap.String(KeyPayloadIsValidJSON, "not_checked"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.opt(wrp.Message{
+				ContentType: tt.contentType,
+				Payload:     []byte(tt.payload),
+			})
+			assert.Equal(t, encode(tt.expected), encode(got))
+		})
+	}
+}
+
+func BenchmarkLogPayloadIsValidJSON(b *testing.B) {
+	payload := append([]byte("["), bytes.Repeat([]byte(`{"key": "value", "n": 12345},`), 2048)...)
+	payload[len(payload)-1] = ']'
+
+	msg := wrp.Message{
+		ContentType: "application/json",
+		Payload:     payload,
+	}
+
+	benchmarks := []struct {
+		name string
+		opt  FieldOpt
+	}{
+		{name: "unlimited", opt: LogPayloadIsValidJSON()},
+		{name: "over the limit", opt: LogPayloadIsValidJSONMax(4096)},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.SetBytes(int64(len(payload)))
+			for i := 0; i < b.N; i++ {
+				bm.opt(msg)
+			}
+		})
+	}
+}
+
+func gzipped(t testing.TB, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestLogPayloadDecompressedSize(t *testing.T) {
+	text := bytes.Repeat([]byte("compressible "), 1000)
+	compressed := gzipped(t, text)
+
+	tests := []struct {
+		name     string
+		payload  []byte
+		expected int64
+	}{
+		{
+			name:     "gzip",
+			payload:  compressed,
+			expected: int64(len(text)),
+		}, {
+			name:     "empty gzip",
+			payload:  gzipped(t, nil),
+			expected: 0,
+		}, {
+			name:     "not gzip",
+			payload:  text,
+			expected: -1,
+		}, {
+			name:     "nil",
+			expected: -1,
+		}, {
+			name:     "magic only",
+			payload:  []byte{0x1f, 0x8b},
+			expected: -1,
+		}, {
+			name:     "header without trailer",
+			payload:  compressed[:gzipHeaderSize+gzipTrailerSize-1],
+			expected: -1,
+		}, {
+			name:     "wrong compression method",
+			payload:  append([]byte{0x1f, 0x8b, 7}, compressed[3:]...),
+			expected: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LogPayloadDecompressedSize()(wrp.Message{Payload: tt.payload})
+			assert.Equal(t, zap.Int64(KeyPayloadDecompressedSize, tt.expected), got)
+		})
+	}
+}
+
+func FuzzLogPayloadDecompressedSize(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x1f, 0x8b, 8})
+	f.Add(gzipped(f, []byte("seed")))
+
+	opt := LogPayloadDecompressedSize()
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		got := opt(wrp.Message{Payload: payload})
+		assert.GreaterOrEqual(t, got.Integer, int64(-1))
+		assert.LessOrEqual(t, got.Integer, int64(math.MaxUint32))
+	})
+}
+
+func TestLogPayloadPreviewLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		payload  string
+		expected map[string]any
+	}{
+		{
+			name:     "empty",
+			n:        2,
+			expected: map[string]any{KeyPayloadPreview: "", KeyPayloadLines: 0},
+		}, {
+			name:     "one line",
+			n:        2,
+			payload:  "key = value",
+			expected: map[string]any{KeyPayloadPreview: "key = value", KeyPayloadLines: 1},
+		}, {
+			name:     "fewer lines than n",
+			n:        5,
+			payload:  "a\nb\n",
+			expected: map[string]any{KeyPayloadPreview: "a\nb", KeyPayloadLines: 2},
+		}, {
+			name:     "more lines than n",
+			n:        2,
+			payload:  "a\nb\nc\nd",
+			expected: map[string]any{KeyPayloadPreview: "a\nb", KeyPayloadLines: 4},
+		}, {
+			name:     "crlf",
+			n:        2,
+			payload:  "a\r\nb\r\nc\r\n",
+			expected: map[string]any{KeyPayloadPreview: "a\nb", KeyPayloadLines: 3},
+		}, {
+			name:     "mixed line endings",
+			n:        3,
+			payload:  "a\r\nb\nc",
+			expected: map[string]any{KeyPayloadPreview: "a\nb\nc", KeyPayloadLines: 3},
+		}, {
+			name:     "lone cr is kept",
+			n:        3,
+			payload:  "a\rb\n",
+			expected: map[string]any{KeyPayloadPreview: "a\rb", KeyPayloadLines: 1},
+		}, {
+			name:     "blank lines",
+			n:        3,
+			payload:  "\n\nc",
+			expected: map[string]any{KeyPayloadPreview: "\n\nc", KeyPayloadLines: 3},
+		}, {
+			name:     "zero lines",
+			n:        0,
+			payload:  "a\nb",
+			expected: map[string]any{KeyPayloadPreview: "", KeyPayloadLines: 2},
+		}, {
+			name:     "negative lines",
+			n:        -1,
+			payload:  "a\nb",
+			expected: map[string]any{KeyPayloadPreview: "", KeyPayloadLines: 2},
+		}, {
+			name:     "utf-8",
+			n:        1,
+			payload:  "héllo\nwörld",
+			expected: map[string]any{KeyPayloadPreview: "héllo", KeyPayloadLines: 2},
+		}, {
+			name:     "not utf-8",
+			n:        1,
+			payload:  "a\n\xff\xfe\n",
+			expected: map[string]any{KeyPayloadSize: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldMap(LogPayloadPreviewLines(tt.n), wrp.Message{Payload: []byte(tt.payload)})
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestLogPayloadIsBinary(t *testing.T) {
+	var msgpack []byte
+	err := wrp.NewEncoderBytes(&msgpack, wrp.Msgpack).Encode(&wrp.Message{
+		Type:            wrp.SimpleEventMessageType,
+		Source:          "mac:112233445566",
+		Destination:     "event:device-status/mac:112233445566/online",
+		TransactionUUID: "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		ContentType:     "application/json",
+		Payload:         []byte(`{"a": 1}`),
+	})
+	require.NoError(t, err)
+
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	var pngImage bytes.Buffer
+	require.NoError(t, png.Encode(&pngImage, img))
+
+	// 5% of 100 characters are allowed to be non-printable.
+	atThreshold := append(bytes.Repeat([]byte("a"), 95), bytes.Repeat([]byte{0x01}, 5)...)
+	overThreshold := append(bytes.Repeat([]byte("a"), 94), bytes.Repeat([]byte{0x01}, 6)...)
+
+	// A multi-byte character cut off by the end of the sample is ignored.
+	cutOff := append(bytes.Repeat([]byte("a"), binarySampleSize-1), "é"...)
+
+	tests := []struct {
+		name     string
+		payload  []byte
+		expected bool
+	}{
+		{name: "empty"},
+		{name: "text", payload: []byte("Hello, world!\nThis is a\ttext payload.\r\n")},
+		{name: "utf-8 text", payload: []byte("héllo wörld, こんにちは")},
+		{name: "json", payload: []byte(`{"key": "value", "list": [1, 2, 3], "nested": {"a": true}}`)},
+		{name: "msgpack", payload: msgpack, expected: true},
+		{name: "png", payload: pngImage.Bytes(), expected: true},
+		{name: "nul", payload: []byte("text\x00text"), expected: true},
+		{name: "invalid utf-8", payload: []byte("\xff\xfe\xfd text"), expected: true},
+		{name: "at the threshold", payload: atThreshold},
+		{name: "over the threshold", payload: overThreshold, expected: true},
+		{name: "cut off character", payload: cutOff},
+		{
+			name:    "only the sample is inspected",
+			payload: append(bytes.Repeat([]byte("a"), binarySampleSize), 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LogPayloadIsBinary()(wrp.Message{Payload: tt.payload})
+			assert.Equal(t, zap.Bool(KeyPayloadIsBinary, tt.expected), got)
+		})
+	}
+}
+
+func TestLogPayloadSampled(t *testing.T) {
+	msg := wrp.Message{TransactionUUID: "c07ee5e1-70be-444c-a156-097c767ad8aa", Payload: []byte("abc")}
+	sampled := map[string]any{KeyPayload: []byte("abc"), KeyPayloadSampled: true}
+	size := map[string]any{KeyPayloadSize: int64(3), KeyPayloadSampled: false}
+
+	tests := []struct {
+		name     string
+		rate     float64
+		fallback FieldOpt
+		expected map[string]any
+	}{
+		{name: "zero", rate: 0, fallback: LogPayloadSize(), expected: size},
+		{name: "negative", rate: -1, fallback: LogPayloadSize(), expected: size},
+		{name: "NaN", rate: math.NaN(), fallback: LogPayloadSize(), expected: size},
+		{name: "smallest", rate: math.SmallestNonzeroFloat64, fallback: LogPayloadSize(), expected: size},
+		{name: "one", rate: 1, fallback: LogPayloadSize(), expected: sampled},
+		{name: "more than one", rate: 2, fallback: LogPayloadSize(), expected: sampled},
+		{name: "nil fallback", rate: 0, expected: map[string]any{KeyPayloadSampled: false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, fieldMap(LogPayloadSampled(tt.rate, tt.fallback), msg))
+		})
+	}
+}
+
+func TestLogPayloadSampled_Deterministic(t *testing.T) {
+	opt := LogPayloadSampled(0.5, LogPayloadSize())
+
+	var count int
+	for i := 0; i < 100; i++ {
+		uuid := fmt.Sprintf("00000000-0000-4000-8000-%012d", i)
+		first := fieldMap(opt, wrp.Message{TransactionUUID: uuid, Payload: []byte("first")})[KeyPayloadSampled]
+
+		// A retry with the same transaction UUID is sampled the same way,
+		// whatever the payload.
+		retry := fieldMap(opt, wrp.Message{TransactionUUID: uuid, Payload: []byte("retry")})[KeyPayloadSampled]
+		assert.Equal(t, first, retry, uuid)
+
+		if first == true {
+			count++
+		}
+	}
+	assert.InDelta(t, 50, count, 20)
+
+	// Without a transaction UUID the payload decides.
+	msg := wrp.Message{Payload: []byte("payload")}
+	assert.Equal(t, fieldMap(opt, msg), fieldMap(opt, msg))
+}
+
+func TestLogPayloadSampled_Rate(t *testing.T) {
+	const messages = 200_000
+
+	for _, rate := range []float64{0.005, 0.1, 0.9} {
+		t.Run(fmt.Sprint(rate), func(t *testing.T) {
+			opt := LogPayloadSampled(rate, nil)
+
+			var count int
+			for i := 0; i < messages; i++ {
+				msg := wrp.Message{TransactionUUID: fmt.Sprintf("uuid-%d", i)}
+				if fieldMap(opt, msg)[KeyPayloadSampled] == true {
+					count++
+				}
+			}
+
+			assert.InEpsilon(t, rate*messages, float64(count), 0.1)
+		})
+	}
+}
+
+func TestSampleHash(t *testing.T) {
+	for _, msg := range []wrp.Message{
+		{TransactionUUID: "c07ee5e1-70be-444c-a156-097c767ad8aa", Payload: []byte("ignored")},
+		{Payload: []byte("payload")},
+		{},
+	} {
+		h := fnv.New64a()
+		if msg.TransactionUUID != "" {
+			h.Write([]byte(msg.TransactionUUID))
+		} else {
+			h.Write(msg.Payload)
+		}
+		assert.Equal(t, mix64(h.Sum64()), sampleHash(&msg))
+	}
+}
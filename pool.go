@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"slices"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// maxPooledStrings is the largest capacity of a scratch slice that is put
+// back in the pool, so that one message with a huge metadata map or header
+// list doesn't keep the memory around.
+const maxPooledStrings = 256
+
+// stringsPool holds the scratch slices the metadata and header FieldOpts
+// collect keys and names in while building or encoding a field.
+var stringsPool = sync.Pool{
+	New: func() any {
+		s := make([]string, 0, 16)
+		return &s
+	},
+}
+
+// getStrings returns an empty scratch slice from the pool.  It must be given
+// back with putStrings, and nothing referring to it may outlive that: a field
+// must copy what it needs out of the slice.
+func getStrings() *[]string {
+	return stringsPool.Get().(*[]string)
+}
+
+// putStrings clears the scratch slice, so the pool doesn't keep the strings
+// alive, and gives it back to the pool.
+func putStrings(s *[]string) {
+	if cap(*s) > maxPooledStrings {
+		return
+	}
+
+	clear(*s)
+	*s = (*s)[:0]
+	stringsPool.Put(s)
+}
+
+// sortedKeys appends the keys of the map to the scratch slice, sorted in
+// ascending byte order.
+func sortedKeys(s *[]string, m map[string]string) {
+	for k := range m {
+		*s = append(*s, k)
+	}
+	slices.Sort(*s)
+}
+
+// stringArray encodes a scratch slice as an array.  It is used through a
+// pointer, which can be stored in an interface without allocating.
+type stringArray []string
+
+func (s *stringArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, v := range *s {
+		enc.AppendString(v)
+	}
+	return nil
+}
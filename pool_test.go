@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPutStrings(t *testing.T) {
+	s := getStrings()
+	*s = append(*s, "a", "b")
+	backing := (*s)[:2]
+
+	putStrings(s)
+	assert.Empty(t, *s)
+	assert.Equal(t, []string{"", ""}, backing, "the strings must not be kept alive")
+}
+
+func TestPutStrings_Large(t *testing.T) {
+	s := make([]string, 1, maxPooledStrings+1)
+	s[0] = "kept"
+
+	putStrings(&s)
+	assert.Equal(t, []string{"kept"}, s, "large slices are left to the garbage collector")
+}
+
+func TestSortedKeys(t *testing.T) {
+	s := getStrings()
+	defer putStrings(s)
+
+	sortedKeys(s, map[string]string{"b": "", "B": "", "a": "", "": ""})
+	assert.Equal(t, []string{"", "B", "a", "b"}, *s)
+}
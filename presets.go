@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var defaultFields = DefaultFields()
+
+// DefaultFields returns the FieldOpts that describe how a message is routed
+// and delivered without logging any of its contents.
+func DefaultFields() []FieldOpt {
+	return []FieldOpt{
+		LogMessageType(),
+		LogSource(),
+		LogDestination(),
+		LogTransactionUUID(),
+		LogContentType(),
+		LogStatus(),
+		LogRequestDeliveryResponse(),
+		LogPayloadSize(),
+		LogPartnerIDs(),
+		LogSessionID(),
+		LogQualityOfService(),
+	}
+}
+
+// keyedField is a FieldOpt constructor and the key of the field it logs.
+type keyedField struct {
+	key string
+	fn  func() FieldOpt
+}
+
+// allFields are the FieldOpts that log each field of the message.
+var allFields = []keyedField{
+	{KeyMsgType, LogMessageType},
+	{KeySource, LogSource},
+	{KeyDestination, LogDestination},
+	{KeyTransactionUUID, LogTransactionUUID},
+	{KeyContentType, LogContentType},
+	{KeyAccept, LogAccept},
+	{KeyStatus, LogStatus},
+	{KeyRequestDeliveryResponse, LogRequestDeliveryResponse},
+	{KeyHeaders, LogHeaders},
+	{KeyMetadata, LogMetadata},
+	{KeyPath, LogPath},
+	{KeyPayload, LogPayload},
+	{KeyServiceName, LogServiceName},
+	{KeyURL, LogURL},
+	{KeyPartnerIDs, LogPartnerIDs},
+	{KeySessionID, LogSessionID},
+	{KeyQualityOfService, LogQualityOfService},
+}
+
+// AllFields returns the FieldOpts that log every field of the message as it
+// was received, including the headers, metadata and payload.  The deprecated
+// spans are not included; use LogSpans and LogIncludeSpans for them.
+func AllFields() []FieldOpt {
+	opts := make([]FieldOpt, 0, len(allFields))
+	for _, f := range allFields {
+		opts = append(opts, f.fn())
+	}
+	return opts
+}
+
+// AllFieldsExcept returns the AllFields FieldOpts without the ones that log
+// the keys, as in AllFieldsExcept(KeyPayload, KeyMetadata).  If any of the
+// keys are not logged by AllFields, an ErrUnknownField error listing them is
+// returned, so that a misspelled key can't leave the field in.
+func AllFieldsExcept(keys ...string) ([]FieldOpt, error) {
+	var unknown []string
+	for _, key := range keys {
+		known := slices.ContainsFunc(allFields, func(f keyedField) bool {
+			return f.key == key
+		})
+		if !known {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownField, strings.Join(unknown, ", "))
+	}
+
+	opts := make([]FieldOpt, 0, len(allFields))
+	for _, f := range allFields {
+		if !slices.Contains(keys, f.key) {
+			opts = append(opts, f.fn())
+		}
+	}
+	return opts, nil
+}
+
+// MustAllFieldsExcept is AllFieldsExcept, panicking if any of the keys are
+// not logged by AllFields.  It is meant for package level variables.
+func MustAllFieldsExcept(keys ...string) []FieldOpt {
+	opts, err := AllFieldsExcept(keys...)
+	if err != nil {
+		panic(err)
+	}
+	return opts
+}
+
+// omitted is the marker of a FieldOpt that never contributes a field.
+type omitted struct{}
+
+// NoFields returns FieldOpts that log nothing.  It is used to make an Observer
+// that logs only the message text pass Validate and to keep UseDefaultFields
+// from applying.
+func NoFields() []FieldOpt {
+	return []FieldOpt{
+		func(wrp.Message) zap.Field {
+			return zap.Field{Type: zapcore.SkipType, Interface: omitted{}}
+		},
+	}
+}
@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestObserver_UseDefaultFields(t *testing.T) {
+	defaults := []string{
+		KeyMsgType,
+		KeySource,
+		KeyDestination,
+		KeyTransactionUUID,
+		KeyContentType,
+		KeyStatus,
+		KeyRequestDeliveryResponse,
+		KeyPayloadSize,
+		KeyPartnerIDs,
+		KeySessionID,
+		KeyQualityOfService,
+	}
+
+	tests := []struct {
+		name        string
+		fields      []FieldOpt
+		useDefaults bool
+		expected    []string
+	}{
+		{
+			name:     "unset without defaults",
+			expected: []string{},
+		}, {
+			name:        "unset with defaults",
+			useDefaults: true,
+			expected:    defaults,
+		}, {
+			name:        "explicitly empty slice with defaults",
+			fields:      []FieldOpt{},
+			useDefaults: true,
+			expected:    defaults,
+		}, {
+			name:        "no fields with defaults",
+			fields:      NoFields(),
+			useDefaults: true,
+			expected:    []string{},
+		}, {
+			name:     "no fields without defaults",
+			fields:   NoFields(),
+			expected: []string{},
+		}, {
+			name:        "configured fields win",
+			fields:      []FieldOpt{LogSource()},
+			useDefaults: true,
+			expected:    []string{KeySource},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zap.InfoLevel)
+			ob := Observer{
+				Logger:           zap.New(core),
+				Fields:           tt.fields,
+				UseDefaultFields: tt.useDefaults,
+			}
+
+			ob.ObserveWRP(context.Background(), wrp.Message{})
+
+			entries := recorded.All()
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.expected, keys(entries[0].Context))
+		})
+	}
+}
+
+func TestObserver_Validate(t *testing.T) {
+	info := AtLevel(zap.InfoLevel)
+
+	tests := []struct {
+		name string
+		ob   Observer
+		err  error
+	}{
+		{
+			name: "nil logger",
+			ob:   Observer{Level: info, Fields: DefaultFields()},
+			err:  ErrInvalidInput,
+		}, {
+			name: "unset level",
+			ob:   Observer{Logger: zap.NewNop(), Fields: DefaultFields()},
+			err:  ErrNoLevel,
+		}, {
+			name: "debug level",
+			ob:   Observer{Logger: zap.NewNop(), Level: AtLevel(zap.DebugLevel), Fields: DefaultFields()},
+		}, {
+			name: "unset fields",
+			ob:   Observer{Logger: zap.NewNop(), Level: info},
+			err:  ErrNoFields,
+		}, {
+			name: "empty fields",
+			ob:   Observer{Logger: zap.NewNop(), Level: info, Fields: []FieldOpt{}},
+			err:  ErrNoFields,
+		}, {
+			name: "default fields",
+			ob:   Observer{Logger: zap.NewNop(), Level: info, UseDefaultFields: true},
+		}, {
+			name: "no fields",
+			ob:   Observer{Logger: zap.NewNop(), Level: info, Fields: NoFields()},
+		}, {
+			name: "fields",
+			ob:   Observer{Logger: zap.NewNop(), Level: info, Fields: []FieldOpt{LogSource()}},
+		}, {
+			name: "normalized partner overrides",
+			ob: Observer{Logger: zap.NewNop(), Level: info, Fields: []FieldOpt{LogSource()},
+				PartnerOverrides: map[string][]FieldOpt{"acme": {LogSource()}}},
+		}, {
+			name: "uppercase partner override",
+			ob: Observer{Logger: zap.NewNop(), Level: info, Fields: []FieldOpt{LogSource()},
+				PartnerOverrides: map[string][]FieldOpt{"Acme": {LogSource()}}},
+			err: ErrInvalidInput,
+		}, {
+			name: "untrimmed partner override",
+			ob: Observer{Logger: zap.NewNop(), Level: info, Fields: []FieldOpt{LogSource()},
+				PartnerOverrides: map[string][]FieldOpt{" acme": {LogSource()}}},
+			err: ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.ob.Validate(), tt.err)
+		})
+	}
+}
+
+func TestAllFields(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	ob := Observer{
+		Logger: zap.New(core),
+		Level:  AtLevel(zap.InfoLevel),
+		Fields: AllFields(),
+	}
+
+	ob.ObserveWRP(context.Background(), wrp.Message{})
+
+	entries := recorded.All()
+	require.Len(t, entries, 1)
+	assert.Len(t, entries[0].Context, len(allFields))
+	for _, f := range allFields {
+		assert.Contains(t, entries[0].ContextMap(), f.key)
+	}
+}
+
+func TestAllFieldsExcept(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		err  error
+	}{
+		{
+			name: "nothing",
+		}, {
+			name: "contents",
+			keys: []string{KeyPayload, KeyMetadata, KeyHeaders},
+		}, {
+			name: "unknown key",
+			keys: []string{KeyPayload, "paylaod", "metdata"},
+			err:  ErrUnknownField,
+		}, {
+			name: "derived key",
+			keys: []string{KeyPayloadSize},
+			err:  ErrUnknownField,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := AllFieldsExcept(tt.keys...)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, opts)
+				assert.Panics(t, func() { MustAllFieldsExcept(tt.keys...) })
+				return
+			}
+			require.NoError(t, err)
+
+			core, recorded := observer.New(zap.InfoLevel)
+			ob := Observer{
+				Logger: zap.New(core),
+				Level:  AtLevel(zap.InfoLevel),
+				Fields: opts,
+			}
+			ob.ObserveWRP(context.Background(), wrp.Message{
+				Headers:  []string{"Accept: a"},
+				Metadata: map[string]string{"/key": "value"},
+				Payload:  []byte("payload"),
+			})
+
+			entries := recorded.All()
+			require.Len(t, entries, 1)
+			logged := entries[0].ContextMap()
+			assert.Len(t, logged, len(allFields)-len(tt.keys))
+			for _, key := range tt.keys {
+				assert.NotContains(t, logged, key)
+			}
+			assert.Len(t, MustAllFieldsExcept(tt.keys...), len(opts))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"strconv"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxQOSValue is the highest valid QOS value.
+const maxQOSValue = wrp.QOSValue(99)
+
+// qosInvalid is logged for QOS values outside of 0-99.
+const qosInvalid = "invalid"
+
+// qosBuckets are the labels of the QOS levels, indexed by wrp.QOSLevel.  The
+// boundaries come from the wrp QOS value constants.
+var qosBuckets = func() []string {
+	lower := []wrp.QOSValue{
+		wrp.QOSLowValue,
+		wrp.QOSMediumValue,
+		wrp.QOSHighValue,
+		wrp.QOSCriticalValue,
+		maxQOSValue + 1,
+	}
+
+	buckets := make([]string, len(lower)-1)
+	for i := range buckets {
+		buckets[i] = strconv.Itoa(int(lower[i])) + "-" + strconv.Itoa(int(lower[i+1]-1))
+	}
+	return buckets
+}()
+
+// validQOS reports whether the QOS value is in the range defined by the spec.
+func validQOS(qos wrp.QOSValue) bool {
+	return wrp.QOSLowValue <= qos && qos <= maxQOSValue
+}
+
+// LogQOSBucket logs the range of QOS values the message's QOS falls in, for
+// example "0-24", matching the queues the QOS levels are mapped to.  Values
+// outside of 0-99 are logged as "invalid".
+func LogQOSBucket() FieldOpt {
+	return Named("qos_bucket", KeyQOSBucket, func(msg wrp.Message) zap.Field {
+		if !validQOS(msg.QualityOfService) {
+			return zap.String(fQOSBucket, qosInvalid)
+		}
+		return zap.String(fQOSBucket, qosBuckets[msg.QualityOfService.Level()])
+	})
+}
+
+// qosLevels are the lowercase names of the QOS levels, indexed by
+// wrp.QOSLevel.
+var qosLevels = []string{"low", "medium", "high", "critical"}
+
+// qosObject encodes a QOS value as an object with the value and the name of
+// its level.  It is a plain integer so that storing a valid QOS value in a
+// field doesn't allocate.
+type qosObject wrp.QOSValue
+
+func (q qosObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	qos := wrp.QOSValue(q)
+	enc.AddInt("value", int(qos))
+	if validQOS(qos) {
+		enc.AddString("level", qosLevels[qos.Level()])
+	} else {
+		enc.AddString("level", qosInvalid)
+	}
+	return nil
+}
+
+// LogQualityOfServiceDetailed logs the QOS value and the name of its level
+// together as an object under qos_detailed, for example
+// {"value": 87, "level": "critical"}.  Values outside of 0-99 have the level
+// "invalid".  It uses its own key so it can be logged along with
+// LogQualityOfService.
+func LogQualityOfServiceDetailed() FieldOpt {
+	return Named("qos_detailed", KeyQualityOfServiceDetailed, func(msg wrp.Message) zap.Field {
+		return zap.Object(fQualityOfServiceDetailed, qosObject(msg.QualityOfService))
+	})
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogQOSBucket(t *testing.T) {
+	tests := []struct {
+		qos      wrp.QOSValue
+		expected string
+	}{
+		{qos: -1, expected: "invalid"},
+		{qos: 0, expected: "0-24"},
+		{qos: 24, expected: "0-24"},
+		{qos: 25, expected: "25-49"},
+		{qos: 49, expected: "25-49"},
+		{qos: 50, expected: "50-74"},
+		{qos: 74, expected: "50-74"},
+		{qos: 75, expected: "75-99"},
+		{qos: 99, expected: "75-99"},
+		{qos: 100, expected: "invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			got := LogQOSBucket()(wrp.Message{QualityOfService: tt.qos})
+			assert.Equal(t, zap.String(KeyQOSBucket, tt.expected), got)
+		})
+	}
+}
+
+func TestLogQualityOfServiceDetailed(t *testing.T) {
+	tests := []struct {
+		qos   wrp.QOSValue
+		level string
+	}{
+		{qos: -1, level: "invalid"},
+		{qos: 0, level: "low"},
+		{qos: 24, level: "low"},
+		{qos: 25, level: "medium"},
+		{qos: 50, level: "high"},
+		{qos: 87, level: "critical"},
+		{qos: 99, level: "critical"},
+		{qos: 100, level: "invalid"},
+		{qos: 1000, level: "invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(int(tt.qos)), func(t *testing.T) {
+			got := encode(LogQualityOfServiceDetailed()(wrp.Message{QualityOfService: tt.qos}))
+			assert.Equal(t, map[string]any{
+				KeyQualityOfServiceDetailed: map[string]any{
+					"value": int(tt.qos),
+					"level": tt.level,
+				},
+			}, got)
+		})
+	}
+}
+
+func TestLogQualityOfServiceDetailed_WithScalar(t *testing.T) {
+	var buf bytes.Buffer
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}),
+		zapcore.AddSync(&buf),
+		zap.InfoLevel,
+	)
+	ob := Observer{
+		Logger:  zap.New(core),
+		Message: "wrp",
+		Fields:  []FieldOpt{LogQualityOfService(), LogQualityOfServiceDetailed()},
+	}
+	ob.ObserveWRP(context.Background(), wrp.Message{QualityOfService: 87})
+
+	assert.JSONEq(t, `{"msg":"wrp","qos":87,"qos_detailed":{"value":87,"level":"critical"}}`, buf.String())
+}
+
+func TestLogQualityOfServiceDetailed_Allocations(t *testing.T) {
+	opt := LogQualityOfServiceDetailed()
+	msg := wrp.Message{QualityOfService: 87}
+	assert.Zero(t, testing.AllocsPerRun(100, func() { opt(msg) }))
+}
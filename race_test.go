@@ -0,0 +1,10 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build race
+
+package wrpzap
+
+// raceEnabled is whether the tests run with the race detector, which makes
+// sync.Pool drop items at random, so allocation counts relying on it vary.
+const raceEnabled = true
@@ -0,0 +1,268 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownField is returned when a field name is not registered.
+	ErrUnknownField = errors.New("unknown field")
+
+	// ErrDuplicateField is returned when a field name is registered twice.
+	ErrDuplicateField = errors.New("duplicate field")
+)
+
+// FieldDescription describes a FieldOpt that can be referred to by name.
+type FieldDescription struct {
+	// Name is the name used to refer to the FieldOpt in configuration.
+	Name string
+
+	// Key is the key of the field the FieldOpt logs.
+	Key string
+
+	// Description is a one line description of the field.
+	Description string
+}
+
+// registration is a named FieldOpt constructor.
+type registration struct {
+	FieldDescription
+	fn func() FieldOpt
+}
+
+var registry = struct {
+	lock   sync.RWMutex
+	names  map[string]registration
+	groups map[string][]FieldOpt
+}{
+	names:  make(map[string]registration),
+	groups: make(map[string][]FieldOpt),
+}
+
+func init() {
+	// The built-in FieldOpts are named by their constructors.
+	builtins := []struct {
+		fn          func() FieldOpt
+		description string
+	}{
+		{LogMessageType, "The message type as a number."},
+		{LogMessageTypeAsNum, "The message type as a number."},
+		{LogMessageTypeAsString, "The message type as a string."},
+		{LogSource, "The source of the message."},
+		{LogSourceNormalized, "The source in a canonical form."},
+		{LogSourceIsCloud, "Whether the source is a cloud service rather than a device."},
+		{LogDestination, "The destination of the message."},
+		{LogDestinationLocator, "The parts of the destination locator as an object."},
+		{LogSourceLocator, "The parts of the source locator as an object."},
+		{LogDestinationNormalized, "The destination in a canonical form."},
+		{LogDestinationIsBroadcast, "Whether the destination fans out rather than being a single device."},
+		{LogEventName, "The event name of an event destination."},
+		{LogLocatorSchemes, "The schemes of the source and destination."},
+		{LogTransactionUUID, "The transaction UUID of the message."},
+		{LogTransactionUUIDValid, "Whether the transaction UUID is an RFC 4122 UUID."},
+		{LogContentType, "The content type of the message."},
+		{LogAccept, "The accept header of the message."},
+		{LogAcceptMissing, "Whether a request that gets a response has no accept value."},
+		{LogStatus, "The status of the message."},
+		{LogRequestDeliveryResponse, "The request delivery response of the message."},
+		{LogDeliveryOutcome, "The outcome of the delivery, classified from the status and request delivery response."},
+		{LogHostname, "The hostname of the machine logging the entry."},
+		{LogHeaders, "The headers of the message."},
+		{LogHeaderDuplicates, "Whether any header name appears more than once."},
+		{LogHeadersBytes, "The total size of the headers in bytes."},
+		{LogWebpaHeaders, "The legacy WebPA headers, like X-Webpa-Device-Name."},
+		{LogMetadata, "The metadata of the message."},
+		{LogMetadataBytes, "The total size of the metadata keys and values in bytes."},
+		{LogPath, "The path of the message."},
+		{LogCRUDPathValid, "Whether the path of a CRUD message is absolute."},
+		{LogObserveCost, "The time spent building the entry's other fields, in microseconds."},
+		{LogPayload, "The payload of the message."},
+		{LogPayloadDecompressedSize, "The decompressed size of a gzip payload."},
+		{LogPayloadIsBinary, "Whether the payload looks like binary data rather than text."},
+		{LogPayloadNoCopy, "The payload of the message, without copying it."},
+		{LogPayloadIsValidJSON, "Whether a JSON payload is valid JSON."},
+		{LogPayloadSize, "The size of the payload of the message."},
+		{LogServiceName, "The service name of the message."},
+		{LogURL, "The URL of the message."},
+		{LogPartnerIDs, "The partner IDs of the message."},
+		{LogPartnerIDsNormalized, "The partner IDs trimmed, lowercased and deduplicated."},
+		{LogSessionID, "The session ID of the message."},
+		{LogQualityOfService, "The quality of service of the message."},
+		{LogSpans, "The timing spans of the message."},
+		{LogIncludeSpans, "Whether the timing spans should be included in the response."},
+		{LogQOSBucket, "The range of QOS values the message's QOS falls in."},
+		{LogQualityOfServiceDetailed, "The quality of service of the message with the name of its level."},
+		{LogRetryCount, "The retry count from the X-Xmidt-Retry-Count header."},
+	}
+
+	for _, b := range builtins {
+		opt := b.fn()
+		registry.names[opt.Name()] = registration{
+			FieldDescription: FieldDescription{
+				Name:        opt.Name(),
+				Key:         opt.Key(),
+				Description: b.description,
+			},
+			fn: b.fn,
+		}
+	}
+}
+
+// RegisterFieldOpt makes a FieldOpt available by name to ParseFieldNames and
+// AvailableFields.  The name must not already be registered.  fn is called
+// each time the name is parsed, and its FieldOpt is named by the
+// registration unless it was already named with Named.
+func RegisterFieldOpt(desc FieldDescription, fn func() FieldOpt) error {
+	desc.Name = strings.TrimSpace(desc.Name)
+	if desc.Name == "" || strings.Contains(desc.Name, ",") || strings.HasPrefix(desc.Name, GroupPrefix) {
+		return fmt.Errorf("%w: invalid field name '%s'", ErrInvalidInput, desc.Name)
+	}
+	if fn == nil {
+		return fmt.Errorf("%w: nil constructor for field '%s'", ErrInvalidInput, desc.Name)
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	if _, found := registry.names[desc.Name]; found {
+		return fmt.Errorf("%w: '%s'", ErrDuplicateField, desc.Name)
+	}
+
+	registry.names[desc.Name] = registration{
+		FieldDescription: desc,
+		fn:               fn,
+	}
+	return nil
+}
+
+// ParseFieldNames returns the FieldOpts for the provided names, in the same
+// order.  Surrounding whitespace is ignored, as are empty names.  A name of
+// the form group:name is replaced by the FieldOpts of the field group, see
+// RegisterFieldGroup.  If any of the names are not registered, an error
+// listing all of them is returned.  See ParseFieldNamesLenient for skipping
+// them instead.
+func ParseFieldNames(names []string) ([]FieldOpt, error) {
+	opts, unknown := resolveFieldNames(names)
+
+	var fields, groups []string
+	for _, name := range unknown {
+		if strings.HasPrefix(name, GroupPrefix) {
+			groups = append(groups, name)
+		} else {
+			fields = append(fields, name)
+		}
+	}
+
+	var errs []error
+	if len(fields) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrUnknownField, strings.Join(fields, ", ")))
+	}
+	if len(groups) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrUnknownGroup, strings.Join(groups, ", ")))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return opts, nil
+}
+
+// ParseFieldNamesLenient is ParseFieldNames, skipping the names that are not
+// registered instead of failing, so a configuration can name fields a newer
+// version adds before it is deployed.  A warning is returned for each name
+// skipped, in order, for the caller to log.  If names were given but none of
+// them are registered ErrNoFields is returned along with the warnings, as the
+// entries would have no fields.
+func ParseFieldNamesLenient(names []string) ([]FieldOpt, []string, error) {
+	opts, unknown := resolveFieldNames(names)
+
+	var warnings []string
+	for _, name := range unknown {
+		if strings.HasPrefix(name, GroupPrefix) {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", ErrUnknownGroup, name))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", ErrUnknownField, name))
+		}
+	}
+
+	if len(opts) == 0 && len(unknown) > 0 {
+		return nil, warnings, fmt.Errorf("%w: none of the field names are known", ErrNoFields)
+	}
+
+	return opts, warnings, nil
+}
+
+// resolveFieldNames returns the FieldOpts for the names that are registered,
+// in order, along with the names, including group references, that are not.
+func resolveFieldNames(names []string) (opts []FieldOpt, unknown []string) {
+	registry.lock.RLock()
+	defer registry.lock.RUnlock()
+
+	opts = make([]FieldOpt, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if group, ok := strings.CutPrefix(name, GroupPrefix); ok {
+			members, found := registry.groups[strings.TrimSpace(group)]
+			if !found {
+				unknown = append(unknown, name)
+				continue
+			}
+			opts = append(opts, members...)
+			continue
+		}
+
+		r, found := registry.names[name]
+		if !found {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		opt := r.fn()
+		if _, named := lookupOpt(opt); !named {
+			opt = Named(r.Name, r.Key, opt)
+		}
+		opts = append(opts, opt)
+	}
+
+	return opts, unknown
+}
+
+// MustParseFieldNames is ParseFieldNames, panicking if any of the names are
+// unknown.  It is meant for package level variables and startup wiring.
+func MustParseFieldNames(names []string) []FieldOpt {
+	opts, err := ParseFieldNames(names)
+	if err != nil {
+		panic(err)
+	}
+	return opts
+}
+
+// AvailableFields returns a description of every registered FieldOpt,
+// including the ones registered with RegisterFieldOpt, sorted by name.
+func AvailableFields() []FieldDescription {
+	registry.lock.RLock()
+	defer registry.lock.RUnlock()
+
+	list := make([]FieldDescription, 0, len(registry.names))
+	for _, r := range registry.names {
+		list = append(list, r.FieldDescription)
+	}
+
+	slices.SortFunc(list, func(a, b FieldDescription) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return list
+}
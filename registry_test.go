@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// builtinConstructors finds the exported Log* functions in the package that
+// take no arguments and return a FieldOpt.
+func builtinConstructors(t *testing.T) []string {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	require.NoError(t, err)
+
+	var names []string
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "Log") {
+					continue
+				}
+				if fn.Type.Params.NumFields() != 0 || fn.Type.Results.NumFields() != 1 {
+					continue
+				}
+				if ident, ok := fn.Type.Results.List[0].Type.(*ast.Ident); ok && ident.Name == "FieldOpt" {
+					names = append(names, fn.Name.Name)
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+func TestAvailableFields_Builtins(t *testing.T) {
+	counts := make(map[string]int)
+	for _, r := range registry.names {
+		name := runtime.FuncForPC(reflect.ValueOf(r.fn).Pointer()).Name()
+		counts[name[strings.LastIndex(name, ".")+1:]]++
+	}
+
+	constructors := builtinConstructors(t)
+	require.NotEmpty(t, constructors)
+	for _, name := range constructors {
+		assert.Equal(t, 1, counts[name], "%s must be registered exactly once", name)
+	}
+
+	for _, desc := range AvailableFields() {
+		assert.NotEmpty(t, desc.Name)
+		assert.NotEmpty(t, desc.Key, desc.Name)
+		assert.NotEmpty(t, desc.Description, desc.Name)
+	}
+}
+
+func TestAvailableFields_Sorted(t *testing.T) {
+	list := AvailableFields()
+	require.NotEmpty(t, list)
+	for i := 1; i < len(list); i++ {
+		assert.Less(t, list[i-1].Name, list[i].Name)
+	}
+}
+
+func TestRegisterFieldOpt(t *testing.T) {
+	custom := FieldDescription{
+		Name:        "test_custom",
+		Key:         "custom",
+		Description: "A custom field.",
+	}
+	fn := func() FieldOpt {
+		return func(msg wrp.Message) zap.Field {
+			return zap.String("custom", msg.Source)
+		}
+	}
+
+	require.NoError(t, RegisterFieldOpt(custom, fn))
+	t.Cleanup(func() {
+		registry.lock.Lock()
+		delete(registry.names, custom.Name)
+		registry.lock.Unlock()
+	})
+
+	assert.Contains(t, AvailableFields(), custom)
+
+	opts, err := ParseFieldNames([]string{"test_custom"})
+	require.NoError(t, err)
+	require.Len(t, opts, 1)
+	assert.Equal(t, zap.String("custom", "src"), opts[0](wrp.Message{Source: "src"}))
+	assert.Equal(t, custom.Name, opts[0].Name())
+	assert.Equal(t, custom.Key, opts[0].Key())
+
+	assert.ErrorIs(t, RegisterFieldOpt(custom, fn), ErrDuplicateField)
+	assert.ErrorIs(t, RegisterFieldOpt(FieldDescription{Name: "source"}, fn), ErrDuplicateField)
+	assert.ErrorIs(t, RegisterFieldOpt(FieldDescription{Name: " "}, fn), ErrInvalidInput)
+	assert.ErrorIs(t, RegisterFieldOpt(FieldDescription{Name: "a,b"}, fn), ErrInvalidInput)
+	assert.ErrorIs(t, RegisterFieldOpt(FieldDescription{Name: "nil_fn"}, nil), ErrInvalidInput)
+}
+
+func TestParseFieldNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    []string
+		expected []zap.Field
+		err      string
+	}{
+		{
+			name:     "none",
+			expected: []zap.Field{},
+		}, {
+			name:  "several",
+			names: []string{"msg_type", " dest ", "", "qos"},
+			expected: []zap.Field{
+				zap.Int(KeyMsgType, int(wrp.SimpleEventMessageType)),
+				zap.String(KeyDestination, "event:device-status"),
+				zap.Int(KeyQualityOfService, 42),
+			},
+		}, {
+			name:  "unknown names are all reported",
+			names: []string{"msg_type", "bogus", "dest", "also_bogus"},
+			err:   "bogus, also_bogus",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := ParseFieldNames(tt.names)
+			if tt.err != "" {
+				assert.ErrorIs(t, err, ErrUnknownField)
+				assert.ErrorContains(t, err, tt.err)
+				assert.Nil(t, opts)
+				assert.PanicsWithError(t, err.Error(), func() { MustParseFieldNames(tt.names) })
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, MustParseFieldNames(tt.names), len(opts))
+
+			core, recorded := observer.New(zap.InfoLevel)
+			ob := Observer{
+				Logger: zap.New(core),
+				Fields: opts,
+			}
+			ob.ObserveWRP(context.Background(), wrp.Message{
+				Type:             wrp.SimpleEventMessageType,
+				Destination:      "event:device-status",
+				QualityOfService: 42,
+			})
+
+			entries := recorded.All()
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.expected, entries[0].Context)
+		})
+	}
+}
+
+func TestParseFieldNames_Modes(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    []string
+		expected []string
+		warnings []string
+		strict   []error
+		lenient  error
+	}{
+		{
+			name:     "all known",
+			names:    []string{"source", "group:delivery"},
+			expected: []string{KeySource, KeyStatus, KeyRequestDeliveryResponse, KeyDeliveryOutcome, KeyQualityOfService},
+		}, {
+			name:     "mixed",
+			names:    []string{"source", "future_field", " dest ", "group:future"},
+			expected: []string{KeySource, KeyDestination},
+			warnings: []string{"unknown field: future_field", "unknown field group: group:future"},
+			strict:   []error{ErrUnknownField, ErrUnknownGroup},
+		}, {
+			name:     "unknown first",
+			names:    []string{"future_field", "qos"},
+			expected: []string{KeyQualityOfService},
+			warnings: []string{"unknown field: future_field"},
+			strict:   []error{ErrUnknownField},
+		}, {
+			name:     "all unknown",
+			names:    []string{"future_field", "other_field"},
+			warnings: []string{"unknown field: future_field", "unknown field: other_field"},
+			strict:   []error{ErrUnknownField},
+			lenient:  ErrNoFields,
+		}, {
+			name:     "empty",
+			names:    []string{"", " "},
+			expected: []string{},
+		},
+	}
+
+	keysOf := func(opts []FieldOpt) []string {
+		keys := make([]string, 0, len(opts))
+		for _, opt := range opts {
+			keys = append(keys, opt(wrp.Message{}).Key)
+		}
+		return keys
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Run("strict", func(t *testing.T) {
+				opts, err := ParseFieldNames(tt.names)
+				if tt.strict != nil {
+					for _, e := range tt.strict {
+						assert.ErrorIs(t, err, e)
+					}
+					assert.Nil(t, opts)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, keysOf(opts))
+			})
+
+			t.Run("lenient", func(t *testing.T) {
+				opts, warnings, err := ParseFieldNamesLenient(tt.names)
+				assert.Equal(t, tt.warnings, warnings)
+				if tt.lenient != nil {
+					assert.ErrorIs(t, err, tt.lenient)
+					assert.Nil(t, opts)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, keysOf(opts))
+			})
+		})
+	}
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"github.com/xmidt-org/wrpzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func int64p(i int64) *int64 {
+	return &i
+}
+
+// roundTripFixtures are messages as they are seen on the wire, covering the
+// pointer fields, the maps and the slices.
+var roundTripFixtures = []struct {
+	name string
+	msg  wrp.Message
+}{
+	{
+		name: "simple event",
+		msg: wrp.Message{
+			Type:             wrp.SimpleEventMessageType,
+			Source:           "mac:112233445566",
+			Destination:      "event:device-status/mac:112233445566/online",
+			TransactionUUID:  "c07ee5e1-70be-444c-a156-097c767ad8aa",
+			ContentType:      "application/json",
+			Headers:          []string{"X-Xmidt-Retry-Count: 1", "X-Test: value"},
+			Metadata:         map[string]string{"/boot-time": "1700000000", "/hw-model": "TG1682"},
+			Payload:          []byte(`{"online":true}`),
+			PartnerIDs:       []string{"comcast"},
+			SessionID:        "session-1",
+			QualityOfService: 24,
+		},
+	}, {
+		name: "request response",
+		msg: wrp.Message{
+			Type:                    wrp.SimpleRequestResponseMessageType,
+			Source:                  "dns:talaria.example.net",
+			Destination:             "mac:112233445566/config",
+			TransactionUUID:         "1b6a2f3e-2c3d-4e5f-8a9b-0c1d2e3f4a5b",
+			ContentType:             "application/msgpack",
+			Accept:                  "application/json",
+			Status:                  int64p(200),
+			RequestDeliveryResponse: int64p(0),
+			Payload:                 []byte{0x00, 0xff, 0x10, 0x80},
+			PartnerIDs:              []string{"comcast", "sky"},
+			QualityOfService:        87,
+		},
+	}, {
+		name: "crud",
+		msg: wrp.Message{
+			Type:            wrp.UpdateMessageType,
+			Source:          "dns:scytale.example.net",
+			Destination:     "mac:112233445566/parodus",
+			TransactionUUID: "5e1f2a3b-4c5d-4e6f-9a0b-1c2d3e4f5a6b",
+			Path:            "/config/wifi",
+			ServiceName:     "config",
+			URL:             "https://example.net/config",
+			Status:          int64p(-1),
+			Metadata:        map[string]string{"": "empty key"},
+		},
+	}, {
+		name: "minimal",
+		msg: wrp.Message{
+			Type: wrp.ServiceAliveMessageType,
+		},
+	},
+}
+
+// expectedFields returns the values the AllFields FieldOpts log for the
+// message, as they are read back from a zaptest observer.
+func expectedFields(msg wrp.Message) map[string]any {
+	headers := make([]any, 0, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers = append(headers, h)
+	}
+
+	metadata := make(map[string]any, len(msg.Metadata))
+	for k, v := range msg.Metadata {
+		metadata[k] = v
+	}
+
+	partnerIDs := make([]any, 0, len(msg.PartnerIDs))
+	for _, id := range msg.PartnerIDs {
+		partnerIDs = append(partnerIDs, id)
+	}
+
+	var status, rdr any
+	if msg.Status != nil {
+		status = *msg.Status
+	}
+	if msg.RequestDeliveryResponse != nil {
+		rdr = *msg.RequestDeliveryResponse
+	}
+
+	return map[string]any{
+		wrpzap.KeyMsgType:                 int64(msg.Type),
+		wrpzap.KeySource:                  msg.Source,
+		wrpzap.KeyDestination:             msg.Destination,
+		wrpzap.KeyTransactionUUID:         msg.TransactionUUID,
+		wrpzap.KeyContentType:             msg.ContentType,
+		wrpzap.KeyAccept:                  msg.Accept,
+		wrpzap.KeyStatus:                  status,
+		wrpzap.KeyRequestDeliveryResponse: rdr,
+		wrpzap.KeyHeaders:                 headers,
+		wrpzap.KeyMetadata:                metadata,
+		wrpzap.KeyPath:                    msg.Path,
+		wrpzap.KeyPayload:                 msg.Payload,
+		wrpzap.KeyServiceName:             msg.ServiceName,
+		wrpzap.KeyURL:                     msg.URL,
+		wrpzap.KeyPartnerIDs:              partnerIDs,
+		wrpzap.KeySessionID:               msg.SessionID,
+		wrpzap.KeyQualityOfService:        int64(msg.QualityOfService),
+	}
+}
+
+// TestRoundTrip encodes the fixtures with the wrp codecs, decodes them and
+// checks that what is logged for the decoded message matches the fixture, so
+// that a wrp-go upgrade changing what is decoded can't go unnoticed.
+func TestRoundTrip(t *testing.T) {
+	for _, format := range wrp.AllFormats() {
+		for _, fixture := range roundTripFixtures {
+			t.Run(format.String()+"/"+fixture.name, func(t *testing.T) {
+				var data []byte
+				require.NoError(t, wrp.NewEncoderBytes(&data, format).Encode(&fixture.msg))
+
+				var decoded wrp.Message
+				require.NoError(t, wrp.NewDecoderBytes(data, format).Decode(&decoded))
+
+				core, recorded := observer.New(zapcore.DebugLevel)
+				ob, err := wrpzap.NewObserver(zap.New(core),
+					wrpzap.WithLevel(zap.InfoLevel),
+					wrpzap.WithFields(wrpzap.AllFields()...),
+				)
+				require.NoError(t, err)
+				ob.ObserveWRP(context.Background(), decoded)
+
+				entries := recorded.AllUntimed()
+				require.Len(t, entries, 1)
+				assert.Equal(t, expectedFields(fixture.msg), entries[0].ContextMap())
+			})
+		}
+	}
+}
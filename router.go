@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// ServiceRouter passes each message to the Observer configured for the
+// service of its destination, so that each service's traffic can be logged
+// differently, for example config traffic with every field and iot traffic
+// with only a few.  Messages whose destination has no service, can't be
+// parsed or has a service without an Observer go to the default Observer.
+//
+// The services are compared exactly, as wrp.ParseLocator returns them.  An
+// Observer without a Logger logs nothing, so it can be used to drop the
+// messages of a service.
+type ServiceRouter struct {
+	routes   map[string]Observer
+	fallback Observer
+}
+
+// NewServiceRouter creates a ServiceRouter that passes the messages for each
+// service in routes to its Observer and the rest to fallback.  The routes are
+// copied.  An empty service is ErrInvalidInput, since no destination has it.
+func NewServiceRouter(routes map[string]Observer, fallback Observer) (*ServiceRouter, error) {
+	if _, found := routes[""]; found {
+		return nil, fmt.Errorf("%w: service is empty", ErrInvalidInput)
+	}
+
+	return &ServiceRouter{
+		routes:   maps.Clone(routes),
+		fallback: fallback,
+	}, nil
+}
+
+// ObserveWRP logs the message with the Observer for its destination's
+// service.
+func (r *ServiceRouter) ObserveWRP(_ context.Context, msg wrp.Message) {
+	ob := r.route(&msg)
+	ob.observe(&msg, nil)
+}
+
+// ObserveWRPPtr logs the message with the Observer for its destination's
+// service, without copying the message.  A nil message is ignored.
+func (r *ServiceRouter) ObserveWRPPtr(_ context.Context, msg *wrp.Message) {
+	if msg == nil {
+		return
+	}
+
+	ob := r.route(msg)
+	ob.observe(msg, nil)
+}
+
+// route returns the Observer for the message's destination service.
+func (r *ServiceRouter) route(msg *wrp.Message) Observer {
+	l, err := wrp.ParseLocator(msg.Destination)
+	if err != nil || l.Service == "" {
+		return r.fallback
+	}
+
+	if ob, found := r.routes[l.Service]; found {
+		return ob
+	}
+	return r.fallback
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestServiceRouter(t *testing.T) {
+	newObserver := func(t *testing.T, text string) (Observer, *observer.ObservedLogs) {
+		core, recorded := observer.New(zap.InfoLevel)
+		ob, err := NewObserver(zap.New(core),
+			WithLevel(zap.InfoLevel),
+			WithMessage(text),
+			WithFields(LogDestination()),
+		)
+		require.NoError(t, err)
+		return ob, recorded
+	}
+
+	tests := []struct {
+		name     string
+		dest     string
+		expected string
+	}{
+		{name: "config", dest: "mac:112233445566/config", expected: "config"},
+		{name: "config with a path", dest: "mac:112233445566/config/wifi", expected: "config"},
+		{name: "iot", dest: "mac:112233445566/iot", expected: "iot"},
+		{name: "dns", dest: "dns:talaria.example.net/iot", expected: "iot"},
+		{name: "other service", dest: "mac:112233445566/parodus", expected: "default"},
+		{name: "case differs", dest: "mac:112233445566/Config", expected: "default"},
+		{name: "no service", dest: "mac:112233445566", expected: "default"},
+		{name: "event", dest: "event:device-status/mac:112233445566/online", expected: "default"},
+		{name: "invalid", dest: "not a locator", expected: "default"},
+		{name: "empty", expected: "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, configLogs := newObserver(t, "config")
+			iot, iotLogs := newObserver(t, "iot")
+			fallback, fallbackLogs := newObserver(t, "default")
+
+			router, err := NewServiceRouter(map[string]Observer{"config": config, "iot": iot}, fallback)
+			require.NoError(t, err)
+
+			msg := wrp.Message{Destination: tt.dest}
+			router.ObserveWRP(context.Background(), msg)
+			router.ObserveWRPPtr(context.Background(), &msg)
+			router.ObserveWRPPtr(context.Background(), nil)
+
+			for text, recorded := range map[string]*observer.ObservedLogs{
+				"config":  configLogs,
+				"iot":     iotLogs,
+				"default": fallbackLogs,
+			} {
+				entries := recorded.AllUntimed()
+				if text != tt.expected {
+					assert.Empty(t, entries, text)
+					continue
+				}
+
+				require.Len(t, entries, 2, text)
+				for _, entry := range entries {
+					assert.Equal(t, text, entry.Message)
+					assert.Equal(t, map[string]any{KeyDestination: tt.dest}, entry.ContextMap())
+				}
+			}
+		})
+	}
+}
+
+func TestServiceRouter_Copied(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	ob := Observer{Logger: zap.New(core), Level: AtLevel(zap.InfoLevel), Fields: []FieldOpt{LogDestination()}}
+
+	routes := map[string]Observer{"config": ob}
+	router, err := NewServiceRouter(routes, Observer{})
+	require.NoError(t, err)
+
+	delete(routes, "config")
+	router.ObserveWRP(context.Background(), wrp.Message{Destination: "mac:112233445566/config"})
+	assert.Len(t, recorded.All(), 1)
+
+	// The default Observer has no Logger, so the message is dropped.
+	router.ObserveWRP(context.Background(), wrp.Message{Destination: "mac:112233445566/iot"})
+	assert.Len(t, recorded.All(), 1)
+}
+
+func TestNewServiceRouter_Invalid(t *testing.T) {
+	_, err := NewServiceRouter(map[string]Observer{"": {}}, Observer{})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"github.com/xmidt-org/sallust"
+	"go.uber.org/zap"
+)
+
+// NewObserverFromSallust builds the logger from the sallust configuration and
+// creates an Observer that logs to it.  Errors building the logger are
+// returned, as are errors from the options.
+func NewObserverFromSallust(cfg sallust.Config, opts ...Option) (Observer, error) {
+	return NewObserverFromBuilder(func() (*zap.Logger, error) { return cfg.Build() }, opts...)
+}
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/sallust"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewObserverFromSallust(t *testing.T) {
+	sink, path := newMemorySink(t)
+
+	ob, err := NewObserverFromSallust(sallust.Config{
+		Level:       "debug",
+		Encoding:    "json",
+		OutputPaths: []string{path},
+	},
+		WithLevel(zapcore.DebugLevel),
+		WithMessage("wrp received"),
+		WithFields(LogSource()),
+	)
+	require.NoError(t, err)
+
+	ob.ObserveWRP(context.Background(), wrp.Message{Source: "mac:112233445566"})
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(sink.Bytes(), &entry))
+	assert.Equal(t, "debug", entry["level"])
+	assert.Equal(t, "wrp received", entry["msg"])
+	assert.Equal(t, "mac:112233445566", entry[KeySource])
+}
+
+func TestNewObserverFromSallust_Errors(t *testing.T) {
+	// A bad output path is reported by sallust.Config.Build.
+	_, err := NewObserverFromSallust(sallust.Config{
+		OutputPaths: []string{"unregistered-scheme://"},
+	}, WithLevel(zap.InfoLevel))
+	assert.Error(t, err)
+
+	_, err = NewObserverFromSallust(sallust.Config{}, WithBatchCap(-1))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"go.uber.org/zap"
+)
+
+// withSeq appends the next sequence number to the fields of an entry that is
+// about to be written, when SequenceNumbers is set.  It must only be called
+// once the logger has accepted the entry, so that messages that aren't logged
+// don't use up sequence numbers.
+func (ob Observer) withSeq(fields ...zap.Field) []zap.Field {
+	if !ob.SequenceNumbers || ob.state == nil {
+		return fields
+	}
+
+	return append(fields, zap.Uint64(fSeq, ob.state.seq.Add(1)))
+}
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// seqs returns the sequence numbers of the entries, in the order written.
+func seqs(entries []observer.LoggedEntry) []uint64 {
+	list := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		if seq, ok := entry.ContextMap()[KeySeq]; ok {
+			list = append(list, seq.(uint64))
+		}
+	}
+	return list
+}
+
+func TestWithSequenceNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    zapcore.Level
+		opts     []Option
+		wrap     func(zapcore.Core) zapcore.Core
+		expected []uint64
+	}{
+		{
+			name:     "numbered",
+			level:    zap.InfoLevel,
+			expected: []uint64{1, 2, 3, 4},
+		}, {
+			name:     "detail entries are numbered too",
+			level:    zap.DebugLevel,
+			opts:     []Option{WithDebugFields(LogPayload())},
+			expected: []uint64{1, 2, 3, 4, 5, 6, 7, 8},
+		}, {
+			name:     "filtered messages don't use numbers",
+			level:    zap.InfoLevel,
+			opts:     []Option{WithFilter(func(msg wrp.Message) bool { return msg.Source != "skip" })},
+			expected: []uint64{1, 2},
+		}, {
+			name:     "disabled messages don't use numbers",
+			level:    zap.InfoLevel,
+			opts:     []Option{WithLevel(zap.DebugLevel)},
+			expected: []uint64{},
+		}, {
+			name:  "sampled messages don't use numbers",
+			level: zap.InfoLevel,
+			wrap: func(core zapcore.Core) zapcore.Core {
+				return zapcore.NewSamplerWithOptions(core, time.Minute, 1, 0)
+			},
+			expected: []uint64{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var core zapcore.Core
+			core, recorded := observer.New(tt.level)
+			if tt.wrap != nil {
+				core = tt.wrap(core)
+			}
+
+			opts := append([]Option{
+				WithLevel(zap.InfoLevel),
+				WithFields(LogSource()),
+				WithSequenceNumbers(),
+			}, tt.opts...)
+			ob, err := NewObserver(zap.New(core), opts...)
+			require.NoError(t, err)
+
+			for _, source := range []string{"a", "skip", "b", "skip"} {
+				ob.ObserveWRP(context.Background(), wrp.Message{Source: source})
+			}
+
+			assert.Equal(t, tt.expected, seqs(recorded.AllUntimed()))
+		})
+	}
+}
+
+func TestWithSequenceNumbers_Concurrent(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	ob, err := NewObserver(zap.New(core), WithLevel(zap.InfoLevel), WithFields(LogSource()), WithSequenceNumbers())
+	require.NoError(t, err)
+
+	const goroutines, messages = 8, 200
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < messages; j++ {
+				ob.ObserveWRP(context.Background(), wrp.Message{})
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every number is used exactly once, without gaps.
+	got := seqs(recorded.AllUntimed())
+	slices.Sort(got)
+	expected := make([]uint64, goroutines*messages)
+	for i := range expected {
+		expected[i] = uint64(i + 1)
+	}
+	assert.Equal(t, expected, got)
+}
+
+func TestWithSequenceNumbers_Wraparound(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	ob, err := NewObserver(zap.New(core), WithLevel(zap.InfoLevel), WithFields(LogSource()), WithSequenceNumbers())
+	require.NoError(t, err)
+
+	ob.state.seq.Store(math.MaxUint64 - 1)
+	for i := 0; i < 3; i++ {
+		ob.ObserveWRP(context.Background(), wrp.Message{})
+	}
+
+	assert.Equal(t, []uint64{math.MaxUint64, 0, 1}, seqs(recorded.AllUntimed()))
+}
+
+func TestWithSequenceNumbers_Unset(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+
+	// A struct literal has no state to keep the counter in.
+	ob := Observer{Logger: zap.New(core), Fields: []FieldOpt{LogSource()}, SequenceNumbers: true}
+	ob.ObserveWRP(context.Background(), wrp.Message{})
+
+	ob, err := NewObserver(zap.New(core), WithLevel(zap.InfoLevel), WithFields(LogSource()))
+	require.NoError(t, err)
+	ob.ObserveWRP(context.Background(), wrp.Message{})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Empty(t, seqs(entries))
+	assert.NotContains(t, ob.String(), "sequence_numbers")
+}
+
+func TestWithSequenceNumbers_Entries(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	ob, err := NewObserver(zap.New(core), WithLevel(zap.InfoLevel), WithFields(LogSource()), WithSequenceNumbers())
+	require.NoError(t, err)
+	assert.Contains(t, ob.String(), " sequence_numbers=true")
+
+	ob.ObserveWRP(context.Background(), wrp.Message{})
+	ob.ObserveWRPBatch(context.Background(), []wrp.Message{{}, {}})
+	ob.ObserveEncoded(context.Background(), []byte("bogus"), wrp.JSON)
+	ob.ObserveConvertible(context.Background(), broken{})
+
+	d, err := NewDeltaObserver(ob, 10)
+	require.NoError(t, err)
+	d.ObserveWRP(context.Background(), wrp.Message{SessionID: "a"})
+
+	top, err := NewTopNObserver(ob, 1, 1, time.Hour)
+	require.NoError(t, err)
+	top.ObserveWRP(context.Background(), wrp.Message{Destination: "a"})
+	require.NoError(t, top.Close(context.Background()))
+
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5, 6}, seqs(recorded.AllUntimed()))
+}
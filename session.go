@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// sessionHashLength is the number of hex characters of the hash logged.
+const sessionHashLength = 16
+
+// LogSessionIDHashed logs a salted SHA-256 hash of the session ID as
+// session_hash, so messages can be grouped by session without the session ID
+// being stored.  The hash is hex encoded and truncated to 16 characters.  An
+// empty string is logged when there is no session ID.  The hash is computed
+// when the entry is encoded.
+//
+// The salt is copied.  An empty salt is reported as ErrInvalidInput, since an
+// unsalted hash of a session ID is easily reversed.
+func LogSessionIDHashed(salt []byte) (FieldOpt, error) {
+	if len(salt) == 0 {
+		return nil, fmt.Errorf("%w: LogSessionIDHashed requires a salt", ErrInvalidInput)
+	}
+	salt = slices.Clone(salt)
+
+	return Named("session_id_hashed", KeySessionHash, func(msg wrp.Message) zap.Field {
+		if msg.SessionID == "" {
+			return zap.String(fSessionHash, "")
+		}
+
+		id := msg.SessionID
+		return lazy(fSessionHash, stringSize(fSessionHash, sessionHashLength), func(enc zapcore.ObjectEncoder) {
+			enc.AddString(fSessionHash, hashSessionID(salt, id))
+		})
+	}), nil
+}
+
+// MustLogSessionIDHashed is LogSessionIDHashed, panicking if the salt is
+// empty.  It is meant for package level variables and startup wiring.
+func MustLogSessionIDHashed(salt []byte) FieldOpt {
+	opt, err := LogSessionIDHashed(salt)
+	if err != nil {
+		panic(err)
+	}
+	return opt
+}
+
+// hashSessionID returns the truncated, salted hash of the session ID.
+func hashSessionID(salt []byte, id string) string {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(id))
+
+	var sum [sha256.Size]byte
+	return hex.EncodeToString(h.Sum(sum[:0]))[:sessionHashLength]
+}
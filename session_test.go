@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+func TestLogSessionIDHashed(t *testing.T) {
+	salt := []byte("pepper")
+	sum := sha256.Sum256([]byte("peppersession123"))
+	expected := hex.EncodeToString(sum[:])[:16]
+
+	opt, err := LogSessionIDHashed(salt)
+	require.NoError(t, err)
+
+	// Changing the caller's salt after construction has no effect.
+	salt[0] = 'X'
+
+	assert.Equal(t, map[string]any{KeySessionHash: expected}, fieldMap(opt, wrp.Message{SessionID: "session123"}))
+	assert.Equal(t, zap.String(KeySessionHash, ""), opt(wrp.Message{}))
+
+	// Different salts produce different hashes.
+	other := fieldMap(MustLogSessionIDHashed([]byte("salt")), wrp.Message{SessionID: "session123"})[KeySessionHash]
+	assert.NotEqual(t, expected, other)
+	assert.Len(t, other, 16)
+}
+
+func TestLogSessionIDHashed_NoSalt(t *testing.T) {
+	for _, salt := range [][]byte{nil, {}} {
+		opt, err := LogSessionIDHashed(salt)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		assert.Nil(t, opt)
+
+		assert.Panics(t, func() { MustLogSessionIDHashed(salt) })
+	}
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// spanElements are the labels of the positional elements of a span.
+var spanElements = [...]string{"parent", "name", "start", "duration", "status"}
+
+// LogSpans logs the timing spans of the message as an array.  A span with the
+// five elements of the wrp format is logged as an object with the keys
+// parent, name, start, duration and status.  A span with any other number of
+// elements is logged as it is, as an array of strings, so that a malformed
+// span is still visible.  A message without spans logs an empty array.
+func LogSpans() FieldOpt {
+	return Named("spans", KeySpans, func(msg wrp.Message) zap.Field {
+		return zap.Array(fSpans, spans(msg.Spans))
+	})
+}
+
+// LogIncludeSpans logs whether the timing spans should be included in the
+// response, or nil when it is not set.
+func LogIncludeSpans() FieldOpt {
+	return Named("include_spans", KeyIncludeSpans, func(msg wrp.Message) zap.Field {
+		return zap.Boolp(fIncludeSpans, msg.IncludeSpans)
+	})
+}
+
+// spans encodes the spans of a message.
+type spans [][]string
+
+func (s spans) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, sp := range s {
+		var err error
+		if len(sp) == len(spanElements) {
+			err = enc.AppendObject(span(sp))
+		} else {
+			err = enc.AppendArray(zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+				for _, v := range sp {
+					enc.AppendString(v)
+				}
+				return nil
+			}))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// span encodes a well formed span with its elements labeled.
+type span []string
+
+func (sp span) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for i, label := range spanElements {
+		enc.AddString(label, sp[i])
+	}
+	return nil
+}
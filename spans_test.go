@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+func TestLogSpans(t *testing.T) {
+	wellFormed := []string{"parent", "child", "1700000000", "15ms", "200"}
+	labeled := map[string]any{
+		"parent":   "parent",
+		"name":     "child",
+		"start":    "1700000000",
+		"duration": "15ms",
+		"status":   "200",
+	}
+
+	tests := []struct {
+		name     string
+		spans    [][]string
+		expected []any
+	}{
+		{
+			name:     "nil",
+			expected: []any{},
+		}, {
+			name:     "empty",
+			spans:    [][]string{},
+			expected: []any{},
+		}, {
+			name:     "well formed",
+			spans:    [][]string{wellFormed},
+			expected: []any{labeled},
+		}, {
+			name:     "too few elements",
+			spans:    [][]string{{"parent", "child"}},
+			expected: []any{[]any{"parent", "child"}},
+		}, {
+			name:     "too many elements",
+			spans:    [][]string{append(append([]string{}, wellFormed...), "extra")},
+			expected: []any{[]any{"parent", "child", "1700000000", "15ms", "200", "extra"}},
+		}, {
+			name:     "empty span",
+			spans:    [][]string{{}},
+			expected: []any{[]any(nil)},
+		}, {
+			name:     "mixed",
+			spans:    [][]string{wellFormed, {"odd"}, wellFormed},
+			expected: []any{labeled, []any{"odd"}, labeled},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := LogSpans()(wrp.Message{Spans: tt.spans})
+			assert.Equal(t, KeySpans, field.Key)
+			assert.Equal(t, map[string]any{KeySpans: tt.expected}, encode(field))
+		})
+	}
+}
+
+func TestLogIncludeSpans(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name     string
+		include  *bool
+		expected any
+	}{
+		{
+			name:     "unset",
+			expected: nil,
+		}, {
+			name:     "true",
+			include:  &yes,
+			expected: true,
+		}, {
+			name:     "false",
+			include:  &no,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := LogIncludeSpans()(wrp.Message{IncludeSpans: tt.include})
+			assert.Equal(t, map[string]any{KeyIncludeSpans: tt.expected}, encode(field))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Stats is a snapshot of the counters kept by an Observer.
+type Stats struct {
+	// Entries is the number of entries written.
+	Entries uint64
+
+	// Bytes is the total encoded size of the entries written.
+	Bytes uint64
+
+	// Dropped is the number of messages an AsyncObserver dropped because its
+	// buffer was full.  It is only reported by AsyncObserver.Stats.
+	Dropped uint64
+}
+
+// stats holds the live counters behind Stats.
+type stats struct {
+	entries atomic.Uint64
+	bytes   atomic.Uint64
+}
+
+// Stats returns a snapshot of the Observer's counters.  The counters are only
+// maintained for Observers created by NewObserver with WithByteAccounting.
+func (ob Observer) Stats() Stats {
+	if ob.state == nil {
+		return Stats{}
+	}
+
+	return Stats{
+		Entries: ob.state.stats.entries.Load(),
+		Bytes:   ob.state.stats.bytes.Load(),
+	}
+}
+
+// accountingCore wraps the root core of an Observer's logger and counts the
+// entries the wrapped core accepts.  The size of each entry is measured by
+// encoding it with a separate encoder, so the count is exact as long as that
+// encoder matches the one the wrapped core writes with.
+type accountingCore struct {
+	zapcore.Core
+	enc   zapcore.Encoder
+	stats *stats
+}
+
+func (c *accountingCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	return &accountingCore{
+		Core:  c.Core.With(fields),
+		enc:   enc,
+		stats: c.stats,
+	}
+}
+
+func (c *accountingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	// As the root core the entry is only accepted when the wrapped core added
+	// itself, so only then is the counter added.  Deferring to the wrapped
+	// core keeps sampling and filtering cores working as usual.
+	if ce = c.Core.Check(ent, ce); ce != nil {
+		ce = ce.AddCore(ent, counter{c})
+	}
+	return ce
+}
+
+// counter is the core added to a checked entry to count it when written.
+type counter struct {
+	*accountingCore
+}
+
+func (c counter) Check(_ zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce
+}
+
+func (c counter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+
+	c.stats.entries.Add(1)
+	c.stats.bytes.Add(uint64(buf.Len()))
+	buf.Free()
+	return nil
+}
+
+func (c counter) Sync() error {
+	return nil
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestObserver_Stats(t *testing.T) {
+	encoder := func() zapcore.Encoder {
+		return zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	}
+
+	tests := []struct {
+		name    string
+		level   zapcore.Level
+		wrap    func(zapcore.Core) zapcore.Core
+		entries uint64
+	}{
+		{
+			name:    "all entries written",
+			level:   zap.InfoLevel,
+			entries: 4,
+		}, {
+			name:  "disabled level",
+			level: zap.DebugLevel,
+		}, {
+			name:  "sampled",
+			level: zap.InfoLevel,
+			wrap: func(core zapcore.Core) zapcore.Core {
+				return zapcore.NewSamplerWithOptions(core, time.Minute, 1, 0)
+			},
+			entries: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			core := zapcore.NewCore(encoder(), zapcore.AddSync(&buf), zap.InfoLevel)
+			if tt.wrap != nil {
+				core = tt.wrap(core)
+			}
+
+			ob, err := NewObserver(zap.New(core),
+				WithLevel(tt.level),
+				WithMessage("test message"),
+				WithFields(LogSource(), LogPayload(), LogMetadata()),
+				WithByteAccounting(encoder()),
+			)
+			require.NoError(t, err)
+
+			msgs := []wrp.Message{
+				{Source: "mac:112233445566"},
+				{Source: "dns:example.com", Payload: []byte("test payload")},
+				{Metadata: map[string]string{"key": "value", "k": "v"}},
+				{},
+			}
+			for _, msg := range msgs {
+				ob.ObserveWRP(context.Background(), msg)
+			}
+
+			stats := ob.Stats()
+			assert.Equal(t, tt.entries, stats.Entries)
+			assert.Equal(t, uint64(buf.Len()), stats.Bytes)
+		})
+	}
+}
+
+func TestObserver_Stats_With(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := zap.NewProductionEncoderConfig()
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(cfg), zapcore.AddSync(&buf), zap.InfoLevel)
+
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithFields(LogSource()),
+		WithByteAccounting(zapcore.NewJSONEncoder(cfg)),
+	)
+	require.NoError(t, err)
+
+	ob.Logger = ob.Logger.With(zap.String("component", "test"))
+	ob.ObserveWRP(context.Background(), wrp.Message{Source: "mac:112233445566"})
+
+	assert.Equal(t, uint64(1), ob.Stats().Entries)
+	assert.Equal(t, uint64(buf.Len()), ob.Stats().Bytes)
+}
+
+func TestObserver_Stats_Unset(t *testing.T) {
+	ob := Observer{Logger: zap.NewNop()}
+	ob.ObserveWRP(context.Background(), wrp.Message{})
+	assert.Equal(t, Stats{}, ob.Stats())
+
+	_, err := NewObserver(zap.NewNop(), WithByteAccounting(nil))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
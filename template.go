@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+var (
+	// ErrInvalidTemplate is returned when a message template can't be parsed.
+	ErrInvalidTemplate = errors.New("invalid message template")
+)
+
+// templateValues are the placeholders a MessageTemplate understands.  The
+// names match the field keys.
+var templateValues = map[string]func(wrp.Message) string{
+	fMsgType:          func(msg wrp.Message) string { return messageTypeName(msg.Type) },
+	fSource:           func(msg wrp.Message) string { return msg.Source },
+	fDestination:      func(msg wrp.Message) string { return msg.Destination },
+	fTransactionUUID:  func(msg wrp.Message) string { return msg.TransactionUUID },
+	fContentType:      func(msg wrp.Message) string { return msg.ContentType },
+	fAccept:           func(msg wrp.Message) string { return msg.Accept },
+	fPath:             func(msg wrp.Message) string { return msg.Path },
+	fServiceName:      func(msg wrp.Message) string { return msg.ServiceName },
+	fURL:              func(msg wrp.Message) string { return msg.URL },
+	fSessionID:        func(msg wrp.Message) string { return msg.SessionID },
+	fQualityOfService: func(msg wrp.Message) string { return strconv.Itoa(int(msg.QualityOfService)) },
+}
+
+// MessageTemplate is a parsed message text containing placeholders that are
+// resolved from each message.  Use its Execute method as an Observer's
+// MessageFunc.
+type MessageTemplate struct {
+	parts []templatePart
+}
+
+// templatePart is either literal text or a value taken from the message.
+type templatePart struct {
+	text  string
+	value func(wrp.Message) string
+}
+
+// ParseMessageTemplate parses a message template.  Placeholders are field keys
+// wrapped in braces, for example:
+//
+//	wrp received for {dest}
+//
+// The supported placeholders are msg_type, source, dest, transaction_uuid,
+// content_type, accept, path, service_name, url, session_id and qos.  Unknown
+// placeholders are left in the text verbatim.  Literal braces are written as
+// {{ and }}.  An unterminated placeholder or an unescaped } is an error.
+func ParseMessageTemplate(text string) (*MessageTemplate, error) {
+	var t MessageTemplate
+	var literal strings.Builder
+
+	flush := func() {
+		if literal.Len() > 0 {
+			t.parts = append(t.parts, templatePart{text: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '{' && strings.HasPrefix(text[i:], "{{"):
+			literal.WriteByte('{')
+			i++
+		case c == '}' && strings.HasPrefix(text[i:], "}}"):
+			literal.WriteByte('}')
+			i++
+		case c == '{':
+			end := strings.IndexByte(text[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated placeholder at offset %d", ErrInvalidTemplate, i)
+			}
+
+			name := text[i+1 : i+end]
+			if value, ok := templateValues[name]; ok {
+				flush()
+				t.parts = append(t.parts, templatePart{value: value})
+			} else {
+				literal.WriteString(text[i : i+end+1])
+			}
+			i += end
+		case c == '}':
+			return nil, fmt.Errorf("%w: unexpected } at offset %d", ErrInvalidTemplate, i)
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	flush()
+
+	return &t, nil
+}
+
+// MustParseMessageTemplate is ParseMessageTemplate, panicking if the template
+// can't be parsed.  It is meant for package level variables and startup
+// wiring.
+func MustParseMessageTemplate(text string) *MessageTemplate {
+	t, err := ParseMessageTemplate(text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Execute resolves the template against the message.
+func (t *MessageTemplate) Execute(msg wrp.Message) string {
+	if len(t.parts) == 1 && t.parts[0].value == nil {
+		return t.parts[0].text
+	}
+
+	var b strings.Builder
+	for _, part := range t.parts {
+		if part.value != nil {
+			b.WriteString(part.value(msg))
+			continue
+		}
+		b.WriteString(part.text)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestParseMessageTemplate(t *testing.T) {
+	msg := wrp.Message{
+		Type:             wrp.SimpleEventMessageType,
+		Source:           "dns:talaria.example.net",
+		Destination:      "mac:112233445566/config",
+		TransactionUUID:  "test uuid",
+		QualityOfService: 42,
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "empty",
+			template: "",
+			expected: "",
+		}, {
+			name:     "no placeholders",
+			template: "wrp received",
+			expected: "wrp received",
+		}, {
+			name:     "destination",
+			template: "wrp received for {dest}",
+			expected: "wrp received for mac:112233445566/config",
+		}, {
+			name:     "several placeholders",
+			template: "{msg_type} {source} -> {dest} ({transaction_uuid}) qos={qos}",
+			expected: "SimpleEventMessageType dns:talaria.example.net -> mac:112233445566/config (test uuid) qos=42",
+		}, {
+			name:     "unknown placeholders are verbatim",
+			template: "wrp {unknown} for {dest}",
+			expected: "wrp {unknown} for mac:112233445566/config",
+		}, {
+			name:     "empty placeholder is verbatim",
+			template: "wrp {}",
+			expected: "wrp {}",
+		}, {
+			name:     "escaped braces",
+			template: "{{dest}} is {dest} }}",
+			expected: "{dest} is mac:112233445566/config }",
+		}, {
+			name:     "escaped braces around a placeholder",
+			template: "{{{dest}}}",
+			expected: "{mac:112233445566/config}",
+		}, {
+			name:     "unterminated placeholder",
+			template: "wrp {dest",
+			err:      true,
+		}, {
+			name:     "unexpected close",
+			template: "wrp } here",
+			err:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseMessageTemplate(tt.template)
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidTemplate)
+				assert.Nil(t, tmpl)
+				assert.PanicsWithError(t, err.Error(), func() { MustParseMessageTemplate(tt.template) })
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, tmpl.Execute(msg))
+			assert.Equal(t, tt.expected, MustParseMessageTemplate(tt.template).Execute(msg))
+		})
+	}
+}
+
+func TestObserver_MessageFunc(t *testing.T) {
+	tmpl, err := ParseMessageTemplate("wrp received for {dest}")
+	require.NoError(t, err)
+
+	core, recorded := observer.New(zap.InfoLevel)
+	ob := Observer{
+		Logger:      zap.New(core),
+		Level:       AtLevel(zap.InfoLevel),
+		Message:     "ignored",
+		MessageFunc: tmpl.Execute,
+	}
+
+	ob.ObserveWRP(context.Background(), wrp.Message{Destination: "mac:112233445566/config"})
+	ob.ObserveWRP(context.Background(), wrp.Message{Destination: "mac:665544332211/iot"})
+
+	entries := recorded.All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "wrp received for mac:112233445566/config", entries[0].Message)
+	assert.Equal(t, "wrp received for mac:665544332211/iot", entries[1].Message)
+}
@@ -0,0 +1,265 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"cmp"
+	"container/heap"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TopNObserver periodically logs the destinations that received the most
+// messages during the interval, instead of logging each message.  The entry
+// is logged using the Observer's Logger, Level and Message, with the number
+// of messages in the interval as message_count and the destinations as
+// top_destinations, most messages first.  An interval without messages logs
+// nothing.
+//
+// Counting every distinct destination could take unbounded memory, so the
+// counts are approximate.  At most capacity destinations are counted at a
+// time.  When a new destination arrives and all the counters are in use, the
+// destination with the lowest count is evicted and the new one takes over
+// its counter and count (the space-saving algorithm).  This means memory is
+// bounded by capacity no matter how many distinct destinations there are,
+// and:
+//
+//   - any destination that received more than message_count/capacity
+//     messages is counted
+//   - a count is never less than the true count, and is at most count_error
+//     more than it
+//
+// A capacity several times n gives good results.
+type TopNObserver struct {
+	ob       Observer
+	n        int
+	capacity int
+
+	lock     sync.Mutex
+	total    uint64
+	counters map[string]*topNCounter
+	byCount  topNHeap
+	closed   bool
+
+	ticker Timer
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewTopNObserver creates a TopNObserver that logs the top n destinations
+// every interval, counting at most capacity destinations.  n and interval
+// must be positive and capacity must be at least n.  Close must be called to
+// stop the TopNObserver.
+func NewTopNObserver(ob Observer, n, capacity int, interval time.Duration) (*TopNObserver, error) {
+	if ob.Logger == nil {
+		return nil, fmt.Errorf("%w: logger is nil", ErrInvalidInput)
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("%w: n must be positive", ErrInvalidInput)
+	}
+	if capacity < n {
+		return nil, fmt.Errorf("%w: capacity must be at least n", ErrInvalidInput)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: interval must be positive", ErrInvalidInput)
+	}
+
+	t := &TopNObserver{
+		ob:       ob,
+		n:        n,
+		capacity: capacity,
+		counters: make(map[string]*topNCounter, capacity),
+		byCount:  make(topNHeap, 0, capacity),
+		ticker:   ob.timers().NewTicker(interval),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go t.run()
+
+	return t, nil
+}
+
+// ObserveWRP counts the message's destination.  Messages the Observer's
+// Filter rejects are not counted.  Messages observed after Close are dropped
+// and reported to the Observer's OnError as ErrClosed.
+func (t *TopNObserver) ObserveWRP(_ context.Context, msg wrp.Message) {
+	t.ob.observed(&msg)
+
+	if t.ob.Filter != nil && !t.ob.Filter(msg) {
+		return
+	}
+
+	if !t.count(msg.Destination) {
+		t.ob.reportError(fmt.Errorf("%w: top destinations message dropped", ErrClosed))
+	}
+}
+
+// count counts a message sent to the destination.  It returns false when the
+// TopNObserver is closed.
+func (t *TopNObserver) count(dest string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.closed {
+		return false
+	}
+
+	t.total++
+
+	if c, found := t.counters[dest]; found {
+		c.count++
+		heap.Fix(&t.byCount, c.index)
+		return true
+	}
+
+	if len(t.byCount) < t.capacity {
+		c := &topNCounter{dest: dest, count: 1}
+		t.counters[c.dest] = c
+		heap.Push(&t.byCount, c)
+		return true
+	}
+
+	// Evict the destination with the lowest count and give its counter to
+	// the new destination.
+	c := t.byCount[0]
+	delete(t.counters, c.dest)
+	c.dest = dest
+	c.err = c.count
+	c.count++
+	t.counters[c.dest] = c
+	heap.Fix(&t.byCount, 0)
+	return true
+}
+
+// Close stops the periodic reports and logs the final one.  Messages
+// observed after Close are dropped.  The context is not used, since the final
+// report doesn't wait on anything.
+func (t *TopNObserver) Close(context.Context) error {
+	t.lock.Lock()
+	if t.closed {
+		t.lock.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.lock.Unlock()
+
+	close(t.stop)
+	<-t.done
+
+	t.report()
+	return nil
+}
+
+func (t *TopNObserver) run() {
+	defer close(t.done)
+
+	defer t.ticker.Stop()
+
+	for {
+		select {
+		case <-t.ticker.C():
+			t.report()
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// report logs the top destinations and starts a new interval.
+func (t *TopNObserver) report() {
+	t.lock.Lock()
+	total := t.total
+	counters := t.byCount
+	t.total = 0
+	t.counters = make(map[string]*topNCounter, t.capacity)
+	t.byCount = make(topNHeap, 0, t.capacity)
+	t.lock.Unlock()
+
+	if total == 0 {
+		return
+	}
+
+	ce := t.ob.Logger.Check(t.ob.baseLevel(), t.ob.Message)
+	if ce == nil {
+		return
+	}
+
+	top := make(topDestinations, 0, len(counters))
+	for _, c := range counters {
+		top = append(top, *c)
+	}
+	slices.SortFunc(top, func(a, b topNCounter) int {
+		if c := cmp.Compare(b.count, a.count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.dest, b.dest)
+	})
+	top = top[:min(len(top), t.n)]
+
+	ce.Write(t.ob.withSeq(
+		zap.Uint64(fMessageCount, total),
+		zap.Array(fTopDestinations, top),
+	)...)
+	t.ob.emitted(t.ob.baseLevel())
+}
+
+// topNCounter counts the messages sent to a destination.  err is the count
+// the destination inherited when it took over the counter.
+type topNCounter struct {
+	dest  string
+	count uint64
+	err   uint64
+	index int
+}
+
+func (c topNCounter) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString(fDestination, c.dest)
+	enc.AddUint64(fCount, c.count)
+	enc.AddUint64(fCountError, c.err)
+	return nil
+}
+
+type topDestinations []topNCounter
+
+func (t topDestinations) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, c := range t {
+		if err := enc.AppendObject(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// topNHeap is a min-heap of counters by count, implementing heap.Interface.
+type topNHeap []*topNCounter
+
+func (h topNHeap) Len() int           { return len(h) }
+func (h topNHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+
+func (h topNHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *topNHeap) Push(x any) {
+	c := x.(*topNCounter)
+	c.index = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *topNHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewTopNObserver(t *testing.T) {
+	ob := Observer{Logger: zap.NewNop()}
+
+	tests := []struct {
+		name     string
+		ob       Observer
+		n        int
+		capacity int
+		interval time.Duration
+	}{
+		{name: "no logger", n: 1, capacity: 1, interval: time.Second},
+		{name: "zero n", ob: ob, capacity: 1, interval: time.Second},
+		{name: "capacity less than n", ob: ob, n: 2, capacity: 1, interval: time.Second},
+		{name: "zero interval", ob: ob, n: 1, capacity: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topN, err := NewTopNObserver(tt.ob, tt.n, tt.capacity, tt.interval)
+			assert.ErrorIs(t, err, ErrInvalidInput)
+			assert.Nil(t, topN)
+		})
+	}
+}
+
+// topN returns the destinations and counts logged in the entry.
+func topN(t *testing.T, entry observer.LoggedEntry) []map[string]any {
+	t.Helper()
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range entry.Context {
+		field.AddTo(enc)
+	}
+
+	list, ok := enc.Fields[KeyTopDestinations].([]any)
+	require.True(t, ok)
+
+	top := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		top = append(top, item.(map[string]any))
+	}
+	return top
+}
+
+func TestTopNObserver(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	var reported []error
+	topNObserver, err := NewTopNObserver(Observer{
+		Logger:  zap.New(core),
+		Message: "top destinations",
+		OnError: func(err error) { reported = append(reported, err) },
+	}, 2, 10, time.Hour)
+	require.NoError(t, err)
+
+	counts := map[string]int{"a": 5, "b": 3, "c": 1, "d": 2}
+	for dest, count := range counts {
+		for range count {
+			topNObserver.ObserveWRP(context.Background(), wrp.Message{Destination: dest})
+		}
+	}
+
+	require.NoError(t, topNObserver.Close(context.Background()))
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "top destinations", entries[0].Message)
+	assert.Equal(t, uint64(11), entries[0].ContextMap()[KeyMessageCount])
+	assert.Equal(t, []map[string]any{
+		{KeyDestination: "a", KeyCount: uint64(5), KeyCountError: uint64(0)},
+		{KeyDestination: "b", KeyCount: uint64(3), KeyCountError: uint64(0)},
+	}, topN(t, entries[0]))
+
+	// Messages after Close are dropped and reported, and closing again does
+	// nothing.
+	topNObserver.ObserveWRP(context.Background(), wrp.Message{Destination: "a"})
+	require.NoError(t, topNObserver.Close(context.Background()))
+	assert.Len(t, recorded.AllUntimed(), 1)
+	require.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0], ErrClosed)
+}
+
+func TestTopNObserver_Intervals(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	topNObserver, err := NewTopNObserver(Observer{Logger: zap.New(core)}, 1, 1, time.Hour)
+	require.NoError(t, err)
+	defer topNObserver.Close(context.Background())
+
+	topNObserver.ObserveWRP(context.Background(), wrp.Message{Destination: "a"})
+	topNObserver.report()
+
+	// An interval without messages logs nothing.
+	topNObserver.report()
+
+	topNObserver.ObserveWRP(context.Background(), wrp.Message{Destination: "b"})
+	topNObserver.report()
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "a", topN(t, entries[0])[0][KeyDestination])
+	assert.Equal(t, "b", topN(t, entries[1])[0][KeyDestination])
+	assert.Equal(t, uint64(1), entries[1].ContextMap()[KeyMessageCount])
+}
+
+func TestTopNObserver_Periodic(t *testing.T) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	clock := newFakeClock(time.UnixMilli(0))
+	hook, writes := written()
+	topNObserver, err := NewTopNObserver(Observer{Logger: zap.New(core, hook), Clock: clock}, 1, 1, time.Minute)
+	require.NoError(t, err)
+	defer topNObserver.Close(context.Background())
+
+	for _, dest := range []string{"a", "b"} {
+		topNObserver.ObserveWRP(context.Background(), wrp.Message{Destination: dest})
+		clock.Add(time.Minute)
+		<-writes
+	}
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "a", topN(t, entries[0])[0][KeyDestination])
+	assert.Equal(t, "b", topN(t, entries[1])[0][KeyDestination])
+}
+
+func TestTopNObserver_Bounded(t *testing.T) {
+	const (
+		capacity = 100
+		distinct = 200_000
+	)
+
+	core, recorded := observer.New(zapcore.DebugLevel)
+	topNObserver, err := NewTopNObserver(Observer{Logger: zap.New(core)}, 3, capacity, time.Hour)
+	require.NoError(t, err)
+
+	// Three hot destinations are hidden in a stream of destinations that
+	// are each seen once.
+	hot := map[string]uint64{"hot-1": 0, "hot-2": 0, "hot-3": 0}
+	for i := range distinct {
+		topNObserver.ObserveWRP(context.Background(), wrp.Message{Destination: strconv.Itoa(i)})
+		if i%10 == 0 {
+			dest := "hot-" + strconv.Itoa(1+(i/10)%3)
+			hot[dest]++
+			topNObserver.ObserveWRP(context.Background(), wrp.Message{Destination: dest})
+		}
+	}
+
+	topNObserver.lock.Lock()
+	assert.LessOrEqual(t, len(topNObserver.counters), capacity)
+	assert.LessOrEqual(t, len(topNObserver.byCount), capacity)
+	topNObserver.lock.Unlock()
+
+	require.NoError(t, topNObserver.Close(context.Background()))
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+
+	top := topN(t, entries[0])
+	require.Len(t, top, 3)
+	for _, item := range top {
+		dest := item[KeyDestination].(string)
+		actual, found := hot[dest]
+		require.True(t, found, "unexpected destination %s", dest)
+
+		count := item[KeyCount].(uint64)
+		countErr := item[KeyCountError].(uint64)
+		assert.GreaterOrEqual(t, count, actual, "counts are never less than the true count")
+		assert.LessOrEqual(t, count-countErr, actual, "counts are at most count_error more than the true count")
+	}
+}
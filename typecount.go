@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"sync/atomic"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// typeCounters holds a count for each message type wrp defines, indexed by
+// the type, and a last count shared by every other type so that the storage
+// stays bounded whatever types the messages claim.
+type typeCounters [wrp.LastMessageType + 1]atomic.Uint64
+
+// next counts one more message of the type and returns its count.
+func (c *typeCounters) next(t wrp.MessageType) uint64 {
+	i := int(t)
+	if i < 0 || i >= int(wrp.LastMessageType) {
+		i = int(wrp.LastMessageType)
+	}
+	return c[i].Add(1)
+}
+
+// MarshalLogObject logs the counts of the types seen, by type name, with the
+// types wrp doesn't define counted together as "other".
+func (c *typeCounters) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for i := range c {
+		count := c[i].Load()
+		if count == 0 {
+			continue
+		}
+		name := "other"
+		if i < int(wrp.LastMessageType) {
+			name = typeName(wrp.MessageType(i))
+		}
+		enc.AddUint64(name, count)
+	}
+	return nil
+}
+
+// countType counts the message type when TypeCounters is set, returning the
+// msg_type_count field and whether the message was counted.
+func (ob Observer) countType(t wrp.MessageType) (zap.Field, bool) {
+	if !ob.TypeCounters || ob.state == nil {
+		return zap.Field{}, false
+	}
+
+	return zap.Uint64(fMsgTypeCount, ob.state.types.next(t)), true
+}
@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// typeCounts returns the msg_type_count of the entries, in the order written.
+func typeCounts(entries []observer.LoggedEntry) []uint64 {
+	list := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		if count, ok := entry.ContextMap()[KeyMsgTypeCount]; ok {
+			list = append(list, count.(uint64))
+		}
+	}
+	return list
+}
+
+func TestWithTypeCounters(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		types    []wrp.MessageType
+		expected []uint64
+	}{
+		{
+			name: "counted per type",
+			types: []wrp.MessageType{
+				wrp.SimpleEventMessageType,
+				wrp.SimpleEventMessageType,
+				wrp.SimpleRequestResponseMessageType,
+				wrp.SimpleEventMessageType,
+				wrp.SimpleRequestResponseMessageType,
+			},
+			expected: []uint64{1, 2, 1, 3, 2},
+		}, {
+			name: "filtered messages leave gaps",
+			opts: []Option{WithFilter(func(msg wrp.Message) bool { return msg.Type != wrp.SimpleEventMessageType || msg.Source != "skip" })},
+			types: []wrp.MessageType{
+				wrp.SimpleEventMessageType,
+				wrp.SimpleEventMessageType,
+				wrp.SimpleEventMessageType,
+			},
+			expected: []uint64{1, 3},
+		}, {
+			name: "undefined types share a count",
+			types: []wrp.MessageType{
+				wrp.Invalid0MessageType,
+				wrp.LastMessageType,
+				wrp.MessageType(-1),
+				wrp.MessageType(1000),
+				wrp.Invalid0MessageType,
+			},
+			expected: []uint64{1, 1, 2, 3, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zap.InfoLevel)
+			opts := append([]Option{
+				WithLevel(zap.InfoLevel),
+				WithFields(LogSource()),
+				WithTypeCounters(),
+			}, tt.opts...)
+			ob, err := NewObserver(zap.New(core), opts...)
+			require.NoError(t, err)
+
+			for i, mt := range tt.types {
+				source := "keep"
+				if i == 1 {
+					source = "skip"
+				}
+				ob.ObserveWRP(context.Background(), wrp.Message{Type: mt, Source: source})
+			}
+
+			assert.Equal(t, tt.expected, typeCounts(recorded.AllUntimed()))
+		})
+	}
+}
+
+func TestWithTypeCounters_Fields(t *testing.T) {
+	core, recorded := observer.New(zap.DebugLevel)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithFields(LogSource()),
+		WithDebugFields(LogPayload()),
+		WithTypeCounters(),
+		WithSequenceNumbers(),
+	)
+	require.NoError(t, err)
+	assert.Contains(t, ob.String(), " type_counters=true")
+
+	ob.ObserveWRP(context.Background(), wrp.Message{Type: wrp.SimpleEventMessageType, Source: "a"})
+
+	// Only the primary entry carries the count.
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, []zap.Field{
+		zap.String(fSource, "a"),
+		zap.Uint64(fMsgTypeCount, 1),
+		zap.Uint64(fSeq, 1),
+	}, entries[0].Context)
+	assert.NotContains(t, entries[1].ContextMap(), KeyMsgTypeCount)
+}
+
+func TestWithTypeCounters_Concurrent(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	ob, err := NewObserver(zap.New(core), WithLevel(zap.InfoLevel), WithFields(LogMessageTypeAsNum()), WithTypeCounters())
+	require.NoError(t, err)
+
+	const goroutines, messages = 8, 200
+	types := []wrp.MessageType{wrp.SimpleEventMessageType, wrp.CreateMessageType, wrp.MessageType(99)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < messages; j++ {
+				ob.ObserveWRP(context.Background(), wrp.Message{Type: types[j%len(types)]})
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every count of every type is used exactly once, without gaps.
+	got := make(map[int64]map[uint64]bool)
+	for _, entry := range recorded.AllUntimed() {
+		fields := entry.ContextMap()
+		mt := fields[KeyMsgType].(int64)
+		if got[mt] == nil {
+			got[mt] = make(map[uint64]bool)
+		}
+		count := fields[KeyMsgTypeCount].(uint64)
+		assert.False(t, got[mt][count], "count %d of type %d used twice", count, mt)
+		got[mt][count] = true
+	}
+
+	require.Len(t, got, len(types))
+	for mt, counts := range got {
+		for i := 1; i <= len(counts); i++ {
+			assert.True(t, counts[uint64(i)], "count %d of type %d missing", i, mt)
+		}
+	}
+}
+
+func TestWithTypeCounters_Wraparound(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	ob, err := NewObserver(zap.New(core), WithLevel(zap.InfoLevel), WithFields(LogSource()), WithTypeCounters())
+	require.NoError(t, err)
+
+	ob.state.types[wrp.SimpleEventMessageType].Store(math.MaxUint64 - 1)
+	for i := 0; i < 3; i++ {
+		ob.ObserveWRP(context.Background(), wrp.Message{Type: wrp.SimpleEventMessageType})
+	}
+
+	assert.Equal(t, []uint64{math.MaxUint64, 0, 1}, typeCounts(recorded.AllUntimed()))
+}
+
+func TestWithTypeCounters_Unset(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+
+	// A struct literal has no state to keep the counters in.
+	ob := Observer{Logger: zap.New(core), Fields: []FieldOpt{LogSource()}, TypeCounters: true}
+	ob.ObserveWRP(context.Background(), wrp.Message{})
+
+	ob, err := NewObserver(zap.New(core), WithLevel(zap.InfoLevel), WithFields(LogSource()))
+	require.NoError(t, err)
+	ob.ObserveWRP(context.Background(), wrp.Message{})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Empty(t, typeCounts(entries))
+	assert.NotContains(t, ob.String(), "type_counters")
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+// uuidDashes are the positions of the dashes in the canonical UUID form.
+var uuidDashes = [...]int{8, 13, 18, 23}
+
+// validUUID reports whether s is an RFC 4122 UUID in the canonical
+// 8-4-4-4-12 hex form, in either case.  Any version is accepted, but the
+// variant must be the RFC 4122 variant, except for the nil UUID.
+func validUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	dash := 0
+	allZero := true
+	for i := 0; i < len(s); i++ {
+		if dash < len(uuidDashes) && i == uuidDashes[dash] {
+			if s[i] != '-' {
+				return false
+			}
+			dash++
+			continue
+		}
+
+		if !isHex(s[i]) {
+			return false
+		}
+		if s[i] != '0' {
+			allZero = false
+		}
+	}
+
+	if allZero {
+		return true
+	}
+
+	// The variant is the top bits of the first digit of the fourth group,
+	// which must be 10xx: 8, 9, a or b.
+	switch s[19] {
+	case '8', '9', 'a', 'b', 'A', 'B':
+		return true
+	}
+
+	return false
+}
+
+func isHex(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+// LogTransactionUUIDValid logs whether the transaction UUID is an RFC 4122
+// UUID in the canonical form, of any version.  An empty transaction UUID logs
+// false.
+func LogTransactionUUIDValid() FieldOpt {
+	return Named("transaction_uuid_valid", KeyTransactionUUIDValid, func(msg wrp.Message) zap.Field {
+		return zap.Bool(fTransactionUUIDValid, validUUID(msg.TransactionUUID))
+	})
+}
+
+// DefaultParentTransactionUUIDMetadataKey is the metadata key the transaction
+// UUID of the transaction that spawned the message is recorded under.
+const DefaultParentTransactionUUIDMetadataKey = "/parent-transaction-uuid"
+
+// LogParentTransactionUUID logs the transaction UUID of the parent transaction,
+// recorded in the metadata key, as parent_transaction_uuid, so that chained
+// transactions can be followed along with LogTransactionUUID.  An empty key
+// uses DefaultParentTransactionUUIDMetadataKey.  A missing value logs an empty
+// string.
+//
+// Calling the returned FieldOpt directly produces an inline field holding
+// the same field as its AppendFields method, which matches the key exactly.
+func LogParentTransactionUUID(metadataKey string) FieldOpt {
+	if metadataKey == "" {
+		metadataKey = DefaultParentTransactionUUIDMetadataKey
+	}
+
+	return Named("parent_transaction_uuid", KeyParentTransactionUUID, envAppender(func(env fieldEnv, msg wrp.Message, fields []zap.Field) []zap.Field {
+		value, _ := metadataValue(msg.Metadata, metadataKey, env.normalizeMetadata)
+		return append(fields, zap.String(fParentTransactionUUID, value))
+	}))
+}
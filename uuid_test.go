@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+)
+
+func TestValidUUID(t *testing.T) {
+	tests := []struct {
+		description string
+		uuid        string
+		expected    bool
+	}{
+		{description: "empty", uuid: ""},
+		{description: "version 1", uuid: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", expected: true},
+		{description: "version 4", uuid: "f47ac10b-58cc-4372-a567-0e02b2c3d479", expected: true},
+		{description: "version 7", uuid: "017f22e2-79b0-7cc3-98c4-dc0c0c07398f", expected: true},
+		{description: "uppercase", uuid: "F47AC10B-58CC-4372-A567-0E02B2C3D479", expected: true},
+		{description: "mixed case", uuid: "f47AC10b-58cc-4372-B567-0e02b2c3d479", expected: true},
+		{description: "variant 9", uuid: "f47ac10b-58cc-4372-9567-0e02b2c3d479", expected: true},
+		{description: "variant 8", uuid: "f47ac10b-58cc-4372-8567-0e02b2c3d479", expected: true},
+		{description: "nil uuid", uuid: "00000000-0000-0000-0000-000000000000", expected: true},
+		{description: "NCS variant", uuid: "f47ac10b-58cc-4372-7567-0e02b2c3d479"},
+		{description: "microsoft variant", uuid: "f47ac10b-58cc-4372-c567-0e02b2c3d479"},
+		{description: "future variant", uuid: "f47ac10b-58cc-4372-e567-0e02b2c3d479"},
+		{description: "no dashes", uuid: "f47ac10b58cc4372a5670e02b2c3d479"},
+		{description: "braces", uuid: "{f47ac10b-58cc-4372-a567-0e02b2c3d479}"},
+		{description: "urn", uuid: "urn:uuid:f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+		{description: "too short", uuid: "f47ac10b-58cc-4372-a567-0e02b2c3d47"},
+		{description: "too long", uuid: "f47ac10b-58cc-4372-a567-0e02b2c3d4790"},
+		{description: "misplaced dash", uuid: "f47ac10-b58cc-4372-a567-0e02b2c3d479"},
+		{description: "dash replaced", uuid: "f47ac10b_58cc-4372-a567-0e02b2c3d479"},
+		{description: "non hex", uuid: "g47ac10b-58cc-4372-a567-0e02b2c3d479"},
+		{description: "surrounding space", uuid: " f47ac10b-58cc-4372-a567-0e02b2c3d47"},
+		{description: "all dashes", uuid: "------------------------------------"},
+		{description: "not a uuid", uuid: "transaction-1234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validUUID(tt.uuid))
+		})
+	}
+}
+
+func TestLogTransactionUUIDValid(t *testing.T) {
+	opt := LogTransactionUUIDValid()
+
+	assert.Equal(t, zap.Bool(KeyTransactionUUIDValid, true),
+		opt(wrp.Message{TransactionUUID: "f47ac10b-58cc-4372-a567-0e02b2c3d479"}))
+	assert.Equal(t, zap.Bool(KeyTransactionUUIDValid, false),
+		opt(wrp.Message{TransactionUUID: "not-a-uuid"}))
+	assert.Equal(t, zap.Bool(KeyTransactionUUIDValid, false), opt(wrp.Message{}))
+}
+
+func TestLogParentTransactionUUID(t *testing.T) {
+	const parent = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+
+	tests := []struct {
+		name     string
+		key      string
+		metadata map[string]string
+		expected string
+	}{
+		{
+			name:     "default key",
+			metadata: map[string]string{DefaultParentTransactionUUIDMetadataKey: parent},
+			expected: parent,
+		}, {
+			name:     "custom key",
+			key:      "/parent",
+			metadata: map[string]string{"/parent": parent, DefaultParentTransactionUUIDMetadataKey: "other"},
+			expected: parent,
+		}, {
+			name:     "absent",
+			metadata: map[string]string{"/parent": parent},
+		}, {
+			name: "no metadata",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := wrp.Message{TransactionUUID: "child", Metadata: tt.metadata}
+			assert.Equal(t, []zap.Field{zap.String(KeyParentTransactionUUID, tt.expected)}, LogParentTransactionUUID(tt.key).AppendFields(msg, nil))
+		})
+	}
+}
+
+func FuzzValidUUID(f *testing.F) {
+	f.Add("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	f.Add("")
+	f.Add("------------------------------------")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		if validUUID(s) {
+			assert.Len(t, s, 36)
+		}
+	})
+}
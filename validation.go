@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ValidationLevel is the lowest level the entries of ObserveValidationError
+// are logged at.
+const ValidationLevel = zapcore.WarnLevel
+
+// ObserveValidation validates the message with validate and, when it fails,
+// logs the failure with ObserveValidationError.  The validation error is
+// returned, so the caller can reject the message.  A wrpvalidator.Validator
+// can be used with a function like:
+//
+//	func(msg wrp.Message) error { return validator.Validate(msg, nil) }
+func (ob Observer) ObserveValidation(ctx context.Context, msg wrp.Message, validate func(wrp.Message) error) error {
+	if validate == nil {
+		return nil
+	}
+
+	err := validate(msg)
+	ob.ObserveValidationError(ctx, msg, err)
+	return err
+}
+
+// ObserveValidationError logs the message that failed validation with the
+// error.  The entry has the Observer's fields, along with each failure as an
+// element of validation_failures and the number of failures as
+// validation_failure_count.  Errors combining several, like those from
+// errors.Join or multierr, are split into their failures, recursively.  The
+// entry is logged at the level chosen for the message, and at least at
+// ValidationLevel.  A nil error logs nothing.
+//
+// The message is usually observed on its own as well, so logging the failure
+// is not observing it again: the Filter is not consulted, since a failure is
+// logged whatever the message, and the message is not counted by the Metrics
+// or the type counters, kept by KeepLast or recorded in the
+// CorrelationIndex.  No Debug detail entry is logged.
+func (ob Observer) ObserveValidationError(_ context.Context, msg wrp.Message, err error) {
+	if err == nil || ob.Logger == nil {
+		return
+	}
+
+	text := ob.message(&msg)
+	level := max(ob.level(&msg), ValidationLevel)
+	ce := ob.Logger.Check(level, text)
+	if ce == nil {
+		return
+	}
+
+	failures := validationFailures(nil, err)
+	fields := append(ob.fields(ob.fieldOpts(&msg), &msg),
+		zap.Strings(fValidationFailures, failures),
+		zap.Int(fValidationFailureCount, len(failures)),
+	)
+	fields = ob.fitBudget(text, fields)
+	if ob.StackOnError && level >= zapcore.ErrorLevel {
+		fields = append(fields, zap.StackSkip(fStacktrace, 1))
+	}
+
+	ce.Write(ob.withSeq(fields...)...)
+	ob.emitted(level)
+}
+
+// validationFailures appends the messages of the individual failures in the
+// error.
+func validationFailures(failures []string, err error) []string {
+	var errs []error
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		errs = e.Unwrap()
+	case interface{ Errors() []error }:
+		errs = e.Errors()
+	default:
+		return append(failures, err.Error())
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			failures = validationFailures(failures, err)
+		}
+	}
+	return failures
+}
@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var (
+	errBadSource = errors.New("invalid source")
+	errBadDest   = errors.New("invalid destination")
+	errBadType   = errors.New("invalid message type")
+)
+
+// errorList combines errors the way multierr's errors did before they
+// implemented Unwrap() []error.
+type errorList []error
+
+func (e errorList) Error() string   { return fmt.Sprint([]error(e)) }
+func (e errorList) Errors() []error { return e }
+
+func TestObserver_ObserveValidationError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		level    zapcore.Level
+		expected []any
+	}{
+		{
+			name: "nil",
+		}, {
+			name:     "single",
+			err:      errBadSource,
+			level:    zap.WarnLevel,
+			expected: []any{"invalid source"},
+		}, {
+			name:     "wrapped",
+			err:      fmt.Errorf("rule 3: %w", errBadSource),
+			level:    zap.WarnLevel,
+			expected: []any{"rule 3: invalid source"},
+		}, {
+			name:     "joined",
+			err:      errors.Join(errBadSource, errBadDest),
+			level:    zap.WarnLevel,
+			expected: []any{"invalid source", "invalid destination"},
+		}, {
+			name:     "nested",
+			err:      errors.Join(errBadSource, errors.Join(errBadDest, nil, errBadType)),
+			level:    zap.WarnLevel,
+			expected: []any{"invalid source", "invalid destination", "invalid message type"},
+		}, {
+			name:     "several wrapped",
+			err:      fmt.Errorf("%w; %w", errBadSource, errBadDest),
+			level:    zap.WarnLevel,
+			expected: []any{"invalid source", "invalid destination"},
+		}, {
+			name:     "error list",
+			err:      errorList{errBadSource, errors.Join(errBadDest, errBadType)},
+			level:    zap.WarnLevel,
+			expected: []any{"invalid source", "invalid destination", "invalid message type"},
+		}, {
+			name:     "escalated past the validation level",
+			err:      errBadType,
+			level:    zap.ErrorLevel,
+			expected: []any{"invalid message type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zap.InfoLevel)
+			ob, err := NewObserver(zap.New(core),
+				WithLevel(zap.InfoLevel),
+				WithMessage("wrp rejected"),
+				WithFields(LogSource()),
+				WithEscalations(EscalateOnRDRFailure()),
+			)
+			require.NoError(t, err)
+
+			msg := wrp.Message{Source: "bogus"}
+			if tt.level == zap.ErrorLevel {
+				msg.RequestDeliveryResponse = int64p(1)
+			}
+			ob.ObserveValidationError(context.Background(), msg, tt.err)
+
+			entries := recorded.AllUntimed()
+			if tt.expected == nil {
+				assert.Empty(t, entries)
+				return
+			}
+
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.level, entries[0].Level)
+			assert.Equal(t, "wrp rejected", entries[0].Message)
+			assert.Equal(t, map[string]any{
+				KeySource:                 "bogus",
+				KeyValidationFailures:     tt.expected,
+				KeyValidationFailureCount: int64(len(tt.expected)),
+			}, entries[0].ContextMap())
+
+			// The Observer's own escalations are untouched.
+			assert.Len(t, ob.Escalations, 1)
+		})
+	}
+}
+
+func TestObserver_ObserveValidation(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	ob := Observer{Logger: zap.New(core), Fields: []FieldOpt{LogSource()}}
+
+	validate := func(msg wrp.Message) error {
+		if msg.Source == "" {
+			return errors.Join(errBadSource, errBadDest)
+		}
+		return nil
+	}
+
+	assert.NoError(t, ob.ObserveValidation(context.Background(), wrp.Message{Source: "a"}, validate))
+	assert.NoError(t, ob.ObserveValidation(context.Background(), wrp.Message{}, nil))
+	assert.Empty(t, recorded.AllUntimed())
+
+	err := ob.ObserveValidation(context.Background(), wrp.Message{}, validate)
+	assert.ErrorIs(t, err, errBadSource)
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zap.WarnLevel, entries[0].Level)
+	assert.Equal(t, int64(2), entries[0].ContextMap()[KeyValidationFailureCount])
+}
+
+func TestObserver_ObserveValidationError_NoLogger(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Observer{}.ObserveValidationError(context.Background(), wrp.Message{}, errBadSource)
+	})
+}
+
+func TestObserver_ObserveValidationError_NotObservedAgain(t *testing.T) {
+	core, recorded := observer.New(zap.DebugLevel)
+	metrics := &recordingMetrics{}
+	index, err := NewCorrelationIndex(10, 10)
+	require.NoError(t, err)
+	ob, err := NewObserver(zap.New(core),
+		WithLevel(zap.InfoLevel),
+		WithFields(LogSource()),
+		WithDebugFields(LogPayload()),
+		WithFilter(func(msg wrp.Message) bool { return msg.Destination != "event:quiet" }),
+		WithMetrics(metrics),
+		WithTypeCounters(),
+		WithCorrelationIndex(index),
+	)
+	require.NoError(t, err)
+
+	msg := wrp.Message{
+		Type:            wrp.SimpleEventMessageType,
+		Source:          "mac:112233445566",
+		TransactionUUID: "uuid-1",
+	}
+	ob.ObserveWRP(context.Background(), msg)
+	ob.ObserveValidationError(context.Background(), msg, errBadDest)
+
+	// The primary and detail entries, then the failure alone.
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 3)
+	assert.Equal(t, map[string]any{
+		KeySource:                 "mac:112233445566",
+		KeyValidationFailures:     []any{"invalid destination"},
+		KeyValidationFailureCount: int64(1),
+	}, entries[2].ContextMap())
+
+	assert.Len(t, metrics.messages, 1, "the message is observed once")
+	assert.Equal(t, []zapcore.Level{zap.InfoLevel, zap.DebugLevel, zap.WarnLevel}, metrics.entries)
+	assert.Len(t, index.LastTransactions("mac:112233445566"), 1)
+
+	// The Filter doesn't hide failures.
+	quiet := wrp.Message{Source: "mac:112233445566", Destination: "event:quiet"}
+	ob.ObserveWRP(context.Background(), quiet)
+	ob.ObserveValidationError(context.Background(), quiet, errBadSource)
+	entries = recorded.AllUntimed()
+	require.Len(t, entries, 4)
+	assert.Equal(t, []any{"invalid source"}, entries[3].ContextMap()[KeyValidationFailures])
+}
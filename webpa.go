@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"strings"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// webpaHeaders are the legacy WebPA headers LogWebpaHeaders logs, with the
+// keys they are logged under.
+var webpaHeaders = [...]struct {
+	name string
+	key  string
+}{
+	{"X-Webpa-Device-Name", "device_name"},
+	{"X-Webpa-Device-Id", "device_id"},
+	{"X-Webpa-Transaction-Id", "transaction_id"},
+	{"X-Webpa-Message-Type", "message_type"},
+	{"X-Webpa-Source", "source"},
+	{"X-Webpa-Destination", "destination"},
+	{"X-Webpa-Convey", "convey"},
+	{"X-Webpa-Partner-Id", "partner_id"},
+}
+
+// webpaObject holds the values of the webpaHeaders found, in the same order.
+type webpaObject struct {
+	values [len(webpaHeaders)]string
+	found  [len(webpaHeaders)]bool
+}
+
+func (w *webpaObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for i, h := range webpaHeaders {
+		if w.found[i] {
+			enc.AddString(h.key, w.values[i])
+		}
+	}
+	return nil
+}
+
+// LogWebpaHeaders logs the legacy WebPA headers found in the headers of
+// messages bridged from older systems, like X-Webpa-Device-Name, as an object
+// under webpa_headers.  Each header is logged under a snake_case key without
+// the X-Webpa- prefix, like device_name.  The names are compared
+// case-insensitively, the first of duplicated headers is logged, and headers
+// that aren't WebPA headers are ignored.  The headers logged are
+// Device-Name, Device-Id, Transaction-Id, Message-Type, Source, Destination,
+// Convey and Partner-Id.
+func LogWebpaHeaders() FieldOpt {
+	return Named("webpa_headers", KeyWebpaHeaders, func(msg wrp.Message) zap.Field {
+		var w webpaObject
+		for _, header := range msg.Headers {
+			name, value, ok := splitHeader(header)
+			if !ok {
+				continue
+			}
+			for i, h := range webpaHeaders {
+				if !w.found[i] && strings.EqualFold(name, h.name) {
+					w.values[i], w.found[i] = value, true
+					break
+				}
+			}
+		}
+		return zap.Object(fWebpaHeaders, &w)
+	})
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/wrp-go/v3"
+)
+
+// bridgedHeaders are the headers of a message bridged from a WebPA
+// deployment: the original HTTP request headers carried along, with the
+// casing each hop happened to use.
+var bridgedHeaders = []string{
+	"Content-Type: application/json",
+	"X-Webpa-Device-Name: mac:112233445566",
+	"x-webpa-transaction-id:  2c9a1f6e-6f0a-4b8e-9f3e-1c2b3a4d5e6f ",
+	"X-WEBPA-MESSAGE-TYPE: SimpleEvent",
+	"X-Webpa-Convey: eyJmaXJtd2FyZS1uYW1lIjoiVEcxNjgyXzIuMTEifQ==",
+	"X-Webpa-Source: dns:webpa.example.net",
+	"X-Webpa-Device-Name: mac:665544332211",
+	"X-Webpa-Unrelated: ignored",
+	"X-Xmidt-Retry-Count: 2",
+	"X-Webpa-Partner-Id",
+	"User-Agent: WebPA-1.6",
+}
+
+func TestLogWebpaHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  []string
+		expected map[string]any
+	}{
+		{
+			name:     "no headers",
+			expected: map[string]any{},
+		}, {
+			name:     "no webpa headers",
+			headers:  []string{"Content-Type: application/json", "X-Xmidt-Retry-Count: 2"},
+			expected: map[string]any{},
+		}, {
+			name:    "bridged",
+			headers: bridgedHeaders,
+			expected: map[string]any{
+				"device_name":    "mac:112233445566",
+				"transaction_id": "2c9a1f6e-6f0a-4b8e-9f3e-1c2b3a4d5e6f",
+				"message_type":   "SimpleEvent",
+				"convey":         "eyJmaXJtd2FyZS1uYW1lIjoiVEcxNjgyXzIuMTEifQ==",
+				"source":         "dns:webpa.example.net",
+			},
+		}, {
+			name:    "empty value",
+			headers: []string{"X-Webpa-Device-Id:"},
+			expected: map[string]any{
+				"device_id": "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := fieldMap(LogWebpaHeaders(), wrp.Message{Headers: tt.headers})
+			assert.Equal(t, map[string]any{KeyWebpaHeaders: tt.expected}, fields)
+		})
+	}
+}
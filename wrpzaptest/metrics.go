@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzaptest
+
+import (
+	"sync"
+
+	"github.com/xmidt-org/wrp-go/v3"
+	"github.com/xmidt-org/wrpzap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Metrics is a wrpzap.Metrics that keeps the counts in memory, for tests.
+// The zero value is ready to use.
+type Metrics struct {
+	lock         sync.Mutex
+	messages     map[wrp.MessageType]int
+	qos          map[wrp.QOSLevel]int
+	payloadBytes int
+	entries      map[zapcore.Level]int
+}
+
+var _ wrpzap.Metrics = (*Metrics)(nil)
+
+// MessageObserved counts the message by type and QOS level, and adds up the
+// payload sizes.
+func (m *Metrics) MessageObserved(msgType wrp.MessageType, qos wrp.QOSValue, payloadBytes int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.messages == nil {
+		m.messages = make(map[wrp.MessageType]int)
+		m.qos = make(map[wrp.QOSLevel]int)
+	}
+	m.messages[msgType]++
+	m.qos[qos.Level()]++
+	m.payloadBytes += payloadBytes
+}
+
+// EntryEmitted counts the entry by level.
+func (m *Metrics) EntryEmitted(level zapcore.Level) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.entries == nil {
+		m.entries = make(map[zapcore.Level]int)
+	}
+	m.entries[level]++
+}
+
+// Messages returns the number of messages observed of the type.
+func (m *Metrics) Messages(msgType wrp.MessageType) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.messages[msgType]
+}
+
+// QOS returns the number of messages observed with the QOS level.
+func (m *Metrics) QOS(level wrp.QOSLevel) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.qos[level]
+}
+
+// PayloadBytes returns the total size of the payloads of the messages
+// observed.
+func (m *Metrics) PayloadBytes() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.payloadBytes
+}
+
+// Entries returns the number of entries written at the level.
+func (m *Metrics) Entries(level zapcore.Level) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.entries[level]
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzaptest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"github.com/xmidt-org/wrpzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestMetrics(t *testing.T) {
+	var m Metrics
+	assert.Zero(t, m.Messages(wrp.SimpleEventMessageType))
+	assert.Zero(t, m.Entries(zapcore.InfoLevel))
+
+	ob, err := wrpzap.NewObserver(zap.NewNop(),
+		wrpzap.WithLevel(zapcore.InfoLevel),
+		wrpzap.WithFields(wrpzap.LogSource()),
+		wrpzap.WithMetrics(&m),
+	)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ob.ObserveWRP(context.Background(), wrp.Message{
+				Type:             wrp.SimpleEventMessageType,
+				QualityOfService: wrp.QOSCriticalValue,
+				Payload:          []byte("abc"),
+			})
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 4, m.Messages(wrp.SimpleEventMessageType))
+	assert.Zero(t, m.Messages(wrp.CreateMessageType))
+	assert.Equal(t, 4, m.QOS(wrp.QOSCritical))
+	assert.Equal(t, 12, m.PayloadBytes())
+
+	// The Nop logger writes nothing.
+	assert.Zero(t, m.Entries(zapcore.InfoLevel))
+}
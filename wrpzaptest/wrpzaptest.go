@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+// Package wrpzaptest provides helpers for testing code that uses a
+// wrpzap.Observer.  It is a separate package so that binaries using wrpzap
+// don't link the testing package.
+package wrpzaptest
+
+import (
+	"testing"
+
+	"github.com/xmidt-org/wrpzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Message is the message text of the entries logged by the Observer returned
+// by ObserverForTesting.
+const Message = "wrp observed"
+
+// ObserverForTesting returns an Observer that logs at Debug level with the
+// Message, along with the entries it has logged.  The entries are also
+// written to the test's log.  When no fields are provided the DefaultFields
+// are logged.
+//
+// The test fails immediately if the Observer can't be created.
+func ObserverForTesting(tb testing.TB, fields ...wrpzap.FieldOpt) (wrpzap.Observer, *observer.ObservedLogs) {
+	tb.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zaptest.NewLogger(tb, zaptest.Level(zapcore.DebugLevel),
+		zaptest.WrapOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(c, core)
+		})),
+	)
+	tb.Cleanup(func() {
+		_ = logger.Sync()
+	})
+
+	ob, err := wrpzap.NewObserver(logger,
+		wrpzap.WithLevel(zapcore.DebugLevel),
+		wrpzap.WithMessage(Message),
+		wrpzap.WithFields(fields...),
+		wrpzap.WithDefaultFields(),
+	)
+	if err != nil {
+		tb.Fatalf("creating the observer: %v", err)
+	}
+
+	return ob, logs
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package wrpzaptest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/wrp-go/v3"
+	"github.com/xmidt-org/wrpzap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestObserverForTesting(t *testing.T) {
+	ob, logs := ObserverForTesting(t, wrpzap.LogSource())
+
+	ob.ObserveWRP(context.Background(), wrp.Message{Source: "mac:112233445566"})
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.DebugLevel, entries[0].Level)
+	assert.Equal(t, Message, entries[0].Message)
+	assert.Equal(t, map[string]any{wrpzap.KeySource: "mac:112233445566"}, entries[0].ContextMap())
+}
+
+func TestObserverForTesting_DefaultFields(t *testing.T) {
+	ob, logs := ObserverForTesting(t)
+
+	ob.ObserveWRP(context.Background(), wrp.Message{Source: "mac:112233445566"})
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Len(t, entries[0].Context, len(wrpzap.DefaultFields()))
+}